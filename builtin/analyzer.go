@@ -12,9 +12,11 @@ import (
 var ErrNoExpand = errors.New("no macro expansion")
 
 // Analyzer parses builtin value forms and returns Expr that can
-// be evaluated against Env.
+// be evaluated against Env. If Profile is set, forms using special
+// forms or global bindings not allowed by it are rejected.
 type Analyzer struct {
 	Specials map[string]ParseSpecial
+	Profile  *Profile
 }
 
 // ParseSpecial validates a special form invocation, parse the form and
@@ -28,21 +30,57 @@ func (ba Analyzer) Analyze(env core.Env, form core.Any) (core.Expr, error) {
 		return ConstExpr{Const: Nil{}}, nil
 	}
 
-	exp, err := macroExpand(ba, env, form)
-	if err != nil && !errors.Is(err, ErrNoExpand) {
+	// make sure a disallowed macro is not expanded.
+	if seq, ok := form.(core.Seq); ok {
+		first, err := seq.First()
+		if err != nil {
+			return nil, err
+		}
+		if sym, ok := SymbolOf(first); ok {
+			if err := ba.checkBinding(env, sym); err != nil {
+				return nil, core.WithFrame(err, formFrame(sym, seq))
+			}
+		}
+	}
+
+	expr, err := macroExpand(ba, env, form)
+	if err == nil {
+		return expr, nil
+	} else if !errors.Is(err, ErrNoExpand) {
 		return nil, err
 	}
 
-	switch f := exp.(type) {
+	switch f := form.(type) {
 	case Symbol:
+		if err := ba.checkBinding(env, f); err != nil {
+			return nil, err
+		}
 		return ResolveExpr{Symbol: f}, nil
 
+	case MetaSymbol:
+		if err := ba.checkBinding(env, f.Symbol); err != nil {
+			return nil, err
+		}
+		return ResolveExpr{Symbol: f.Symbol}, nil
+
 	case core.Vector:
 		return VectorExpr{
 			Vector:   f,
 			Analyzer: ba,
 		}, nil
 
+	case core.Map:
+		return MapExpr{
+			Map:      f,
+			Analyzer: ba,
+		}, nil
+
+	case core.Set:
+		return SetExpr{
+			Set:      f,
+			Analyzer: ba,
+		}, nil
+
 	case core.Seq:
 		cnt, err := f.Count()
 		if err != nil {
@@ -54,7 +92,7 @@ func (ba Analyzer) Analyze(env core.Env, form core.Any) (core.Expr, error) {
 		return ba.analyzeSeq(env, f)
 	}
 
-	return ConstExpr{Const: exp}, nil
+	return ConstExpr{Const: form}, nil
 }
 
 func (ba Analyzer) analyzeSeq(env core.Env, seq core.Seq) (core.Expr, error) {
@@ -67,8 +105,12 @@ func (ba Analyzer) analyzeSeq(env core.Env, seq core.Seq) (core.Expr, error) {
 	// The call target may be a special form.  In this case, we need to get the
 	// corresponding parser function, which will take care of parsing/analyzing
 	// the tail.
-	if sym, ok := first.(Symbol); ok {
+	if sym, ok := SymbolOf(first); ok {
 		if parse, found := ba.Specials[string(sym)]; found {
+			if err := ba.checkSpecial(string(sym)); err != nil {
+				return nil, core.WithFrame(err, formFrame(sym, seq))
+			}
+
 			next, err := seq.Next()
 			if err != nil {
 				return nil, err
@@ -79,7 +121,11 @@ func (ba Analyzer) analyzeSeq(env core.Env, seq core.Seq) (core.Expr, error) {
 
 	// Call target is not a special form and must be a Invokable. Analyze
 	// the arguments and create an InvokeExpr.
-	ie := InvokeExpr{Name: fmt.Sprintf("%v", first)}
+	ie := InvokeExpr{
+		Name:     fmt.Sprintf("%v", first),
+		Form:     seq,
+		Position: PositionOf(seq),
+	}
 	err = core.ForEach(seq, func(item core.Any) (done bool, err error) {
 		if ie.Target == nil {
 			ie.Target, err = ba.Analyze(env, first)
@@ -95,11 +141,16 @@ func (ba Analyzer) analyzeSeq(env core.Env, seq core.Seq) (core.Expr, error) {
 	return ie, err
 }
 
-func macroExpand(a core.Analyzer, env core.Env, form core.Any) (core.Any, error) {
+// formFrame returns the frame for errors in the analysis of the form.
+func formFrame(name Symbol, form core.Any) core.Frame {
+	return core.Frame{Name: string(name), Form: form, Position: PositionOf(form)}
+}
+
+// macroExpand expands the form if it is a macro invocation and returns the
+// analyzed expansion. Returns ErrNoExpand if the form is not a macro call.
+func macroExpand(a core.Analyzer, env core.Env, form core.Any) (core.Expr, error) {
 	res, err := macroExpand1(env, form)
-	if errors.Is(err, ErrNoExpand) {
-		return res, nil
-	} else if err != nil {
+	if err != nil {
 		return nil, err
 	}
 	return a.Analyze(env, res)
@@ -117,7 +168,7 @@ func macroExpand1(env core.Env, form core.Any) (core.Any, error) {
 	}
 
 	var target core.Any
-	sym, ok := first.(Symbol)
+	sym, ok := SymbolOf(first)
 	if ok {
 		v, err := ResolveExpr{Symbol: sym}.Eval(env)
 		if err != nil {
@@ -50,6 +50,7 @@ func TestBuiltinAnalyzer_Analyze(t *testing.T) {
 				Name:   "hundred",
 				Target: builtin.ResolveExpr{Symbol: "hundred"},
 				Args:   []core.Expr{builtin.ConstExpr{Const: 1}},
+				Form:   builtin.NewList(builtin.Symbol("hundred"), 1),
 			},
 		},
 	}
@@ -90,6 +91,101 @@ func TestBultinAnalyzer_Analyze_Vector(t *testing.T) {
 	assert.Equal(t, ba, expr.(builtin.VectorExpr).Analyzer)
 }
 
+func TestBultinAnalyzer_Analyze_Map(t *testing.T) {
+	t.Parallel()
+
+	m, err := builtin.NewMap(builtin.Keyword("foo"), builtin.Symbol("bar"))
+	require.NoError(t, err)
+
+	var ba builtin.Analyzer
+	expr, err := ba.Analyze(core.New(nil), m)
+	require.NoError(t, err)
+	require.IsType(t, builtin.MapExpr{}, expr)
+	assert.Equal(t, m, expr.(builtin.MapExpr).Map)
+	assert.Equal(t, ba, expr.(builtin.MapExpr).Analyzer)
+}
+
+func TestBultinAnalyzer_Analyze_Set(t *testing.T) {
+	t.Parallel()
+
+	s, err := builtin.NewSet(builtin.Symbol("foo"))
+	require.NoError(t, err)
+
+	var ba builtin.Analyzer
+	expr, err := ba.Analyze(core.New(nil), s)
+	require.NoError(t, err)
+	require.IsType(t, builtin.SetExpr{}, expr)
+	assert.Equal(t, s, expr.(builtin.SetExpr).Set)
+	assert.Equal(t, ba, expr.(builtin.SetExpr).Analyzer)
+}
+
+func TestBuiltinAnalyzer_Analyze_Profile(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(map[string]core.Any{
+		"allowed":    fakeFn{},
+		"disallowed": fakeFn{},
+	})
+
+	ba := &builtin.Analyzer{
+		Specials: map[string]builtin.ParseSpecial{
+			"foo": func(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+				return builtin.ConstExpr{Const: "foo"}, nil
+			},
+			"bar": func(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+				return builtin.ConstExpr{Const: "bar"}, nil
+			},
+		},
+		Profile: &builtin.Profile{
+			Name:     "test",
+			Specials: []string{"foo"},
+			Bindings: []string{"allowed"},
+		},
+	}
+
+	table := []struct {
+		title   string
+		form    core.Any
+		wantErr string
+	}{
+		{title: "AllowedSpecial", form: builtin.NewList(builtin.Symbol("foo"))},
+		{title: "AllowedBinding", form: builtin.NewList(builtin.Symbol("allowed"))},
+		{title: "NonRootSymbol", form: builtin.Symbol("local")},
+		{
+			title:   "DisallowedSpecial",
+			form:    builtin.NewList(builtin.Symbol("bar")),
+			wantErr: "EvalError: not allowed: special form 'bar' is not allowed in profile 'test'",
+		},
+		{
+			title:   "DisallowedBinding",
+			form:    builtin.NewVector(builtin.Symbol("disallowed")),
+			wantErr: "EvalError: not allowed: binding 'disallowed' is not allowed in profile 'test'",
+		},
+		{
+			title:   "DisallowedInvokeTarget",
+			form:    builtin.NewList(builtin.Symbol("disallowed"), builtin.Int64(1)),
+			wantErr: "EvalError: not allowed: binding 'disallowed' is not allowed in profile 'test'",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			expr, err := ba.Analyze(env, tt.form)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			if expr != nil {
+				// vector elements are analyzed during evaluation.
+				_, err = expr.Eval(env)
+			}
+			assert.True(t, errors.Is(err, builtin.ErrNotAllowed), "want ErrNotAllowed, got %v", err)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
 type fakeFn struct{}
 
 func (fakeFn) Invoke(_ ...core.Any) (core.Any, error) { return 100, nil }
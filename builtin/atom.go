@@ -0,0 +1,289 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	_ core.Ref              = (*Atom)(nil)
+	_ core.EqualityProvider = (*Atom)(nil)
+)
+
+// ErrInvalidState is returned when the validator of an atom rejects a new
+// value.
+var ErrInvalidState = errors.New("invalid reference state")
+
+// Atom is a mutable reference that can be safely shared between goroutines.
+// Value of the atom is changed atomically using Swap, Reset or CompareAndSet.
+// A validator can be set to reject invalid values and watches are notified
+// after every change.
+type Atom struct {
+	mu        sync.Mutex
+	val       core.Any
+	version   uint64
+	validator core.Invokable
+	watches   []watch
+}
+
+type watch struct {
+	key core.Any
+	fn  core.Invokable
+}
+
+// NewAtom returns a new atom with the given initial value.
+func NewAtom(v core.Any) *Atom {
+	if v == nil {
+		v = Nil{}
+	}
+	return &Atom{val: v}
+}
+
+// MakeAtom implements (atom val :validator fn?) and returns a new atom. The
+// initial value must be accepted by the validator.
+func MakeAtom(ctx context.Context, v core.Any, opts ...core.Any) (*Atom, error) {
+	a := NewAtom(v)
+	if len(opts) == 0 {
+		return a, nil
+	} else if len(opts) != 2 || opts[0] != Keyword("validator") {
+		return nil, fmt.Errorf("atom: invalid options %v, only ':validator fn' is supported", opts)
+	}
+
+	if err := SetValidator(ctx, a, opts[1]); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Deref returns the current value of the atom.
+func (a *Atom) Deref(_ context.Context) (core.Any, error) {
+	v, _ := a.load()
+	return v, nil
+}
+
+// Swap atomically sets the value of the atom to (fn current-value args*).
+// fn may be invoked more than once if the value is changed concurrently and
+// hence should be free of side effects. Returns the new value.
+func (a *Atom) Swap(ctx context.Context, fn core.Invokable, args ...core.Any) (core.Any, error) {
+	for {
+		old, ver := a.load()
+
+		v, err := invoke(ctx, fn, append([]core.Any{old}, args...)...)
+		if err != nil {
+			return nil, err
+		}
+
+		swapped, err := a.set(ctx, ver, old, v)
+		if err != nil {
+			return nil, err
+		} else if swapped {
+			return v, nil
+		}
+
+		if err := contextErr(ctx); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Reset sets the value of the atom to v irrespective of the current value
+// and returns v.
+func (a *Atom) Reset(ctx context.Context, v core.Any) (core.Any, error) {
+	for {
+		old, ver := a.load()
+		if swapped, err := a.set(ctx, ver, old, v); err != nil {
+			return nil, err
+		} else if swapped {
+			return v, nil
+		}
+	}
+}
+
+// CompareAndSet sets the value of the atom to v only if the current value
+// is equal to old. Returns true if the value was set.
+func (a *Atom) CompareAndSet(ctx context.Context, old, v core.Any) (bool, error) {
+	for {
+		cur, ver := a.load()
+		if eq, err := core.Eq(cur, old); err != nil || !eq {
+			return false, err
+		}
+
+		if swapped, err := a.set(ctx, ver, cur, v); err != nil || swapped {
+			return swapped, err
+		}
+	}
+}
+
+// SetValidator sets the validator of the atom. fn is invoked with every new
+// value and the value is rejected if the result is not truthy. nil removes
+// the validator.
+func (a *Atom) SetValidator(fn core.Invokable) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.validator = fn
+}
+
+// AddWatch adds a watch with the given key. fn is invoked with the key, the
+// atom, the old and the new value after every change of the value. Adding
+// a watch with an existing key replaces it.
+func (a *Atom) AddWatch(key core.Any, fn core.Invokable) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ws := make([]watch, 0, len(a.watches)+1)
+	for _, w := range a.watches {
+		if eq, err := core.Eq(w.key, key); err != nil {
+			return err
+		} else if !eq {
+			ws = append(ws, w)
+		}
+	}
+	a.watches = append(ws, watch{key: key, fn: fn})
+	return nil
+}
+
+// RemoveWatch removes the watch with the given key.
+func (a *Atom) RemoveWatch(key core.Any) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ws := make([]watch, 0, len(a.watches))
+	for _, w := range a.watches {
+		if eq, err := core.Eq(w.key, key); err != nil {
+			return err
+		} else if !eq {
+			ws = append(ws, w)
+		}
+	}
+	a.watches = ws
+	return nil
+}
+
+// Equals returns true if other is the same atom.
+func (a *Atom) Equals(other core.Any) (bool, error) {
+	o, ok := other.(*Atom)
+	return ok && o == a, nil
+}
+
+// SExpr returns a string representation of the atom.
+func (a *Atom) SExpr() (string, error) {
+	v, _ := a.load()
+	s, err := toSExpr(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("#atom[%s]", s), nil
+}
+
+func (a *Atom) load() (core.Any, uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.val, a.version
+}
+
+// set validates v and sets it as the value if the value has not changed
+// since the version ver was loaded. Watches are notified after the change.
+// The lock is not held while invoking the validator and the watches so that
+// they can use the atom.
+func (a *Atom) set(ctx context.Context, ver uint64, old, v core.Any) (bool, error) {
+	if v == nil {
+		v = Nil{}
+	}
+
+	a.mu.Lock()
+	validator := a.validator
+	a.mu.Unlock()
+
+	if validator != nil {
+		ok, err := invoke(ctx, validator, v)
+		if err != nil {
+			return false, err
+		} else if !IsTruthy(ok) {
+			return false, core.Error{
+				Cause:   ErrInvalidState,
+				Message: fmt.Sprintf("validator rejected value of type '%s'", reflect.TypeOf(v)),
+			}
+		}
+	}
+
+	a.mu.Lock()
+	if a.version != ver {
+		a.mu.Unlock()
+		return false, nil
+	}
+	a.val = v
+	a.version++
+	watches := a.watches
+	a.mu.Unlock()
+
+	for _, w := range watches {
+		if _, err := invoke(ctx, w.fn, w.key, a, old, v); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// SwapAtom implements (swap! atom fn args*).
+func SwapAtom(ctx context.Context, a *Atom, fn core.Invokable, args ...core.Any) (core.Any, error) {
+	return a.Swap(ctx, fn, args...)
+}
+
+// ResetAtom implements (reset! atom val).
+func ResetAtom(ctx context.Context, a *Atom, v core.Any) (core.Any, error) {
+	return a.Reset(ctx, v)
+}
+
+// CompareAndSetAtom implements (compare-and-set! atom old new).
+func CompareAndSetAtom(ctx context.Context, a *Atom, old, v core.Any) (Bool, error) {
+	ok, err := a.CompareAndSet(ctx, old, v)
+	return Bool(ok), err
+}
+
+// SetValidator implements (set-validator! atom fn). The current value must
+// be accepted by the validator. Nil removes the validator. fn can be an
+// Invokable or a Go func.
+func SetValidator(ctx context.Context, a *Atom, fn core.Any) error {
+	if IsNil(fn) {
+		a.SetValidator(nil)
+		return nil
+	}
+
+	inv, err := invokableOf(fn)
+	if err != nil {
+		return fmt.Errorf("validator must be invokable: %w", err)
+	}
+
+	cur, _ := a.load()
+	if ok, err := invoke(ctx, inv, cur); err != nil {
+		return err
+	} else if !IsTruthy(ok) {
+		return core.Error{
+			Cause:   ErrInvalidState,
+			Message: "validator rejected the current value",
+		}
+	}
+
+	a.SetValidator(inv)
+	return nil
+}
+
+// AddWatch implements (add-watch atom key fn). fn can be an Invokable or a
+// Go func.
+func AddWatch(a *Atom, key core.Any, fn core.Any) (*Atom, error) {
+	inv, err := invokableOf(fn)
+	if err != nil {
+		return nil, fmt.Errorf("watch must be invokable: %w", err)
+	}
+	return a, a.AddWatch(key, inv)
+}
+
+// RemoveWatch implements (remove-watch atom key).
+func RemoveWatch(a *Atom, key core.Any) (*Atom, error) {
+	return a, a.RemoveWatch(key)
+}
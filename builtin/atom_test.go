@@ -0,0 +1,166 @@
+package builtin
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	inc := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		return args[0].(Int64) + 1, nil
+	})
+
+	a := NewAtom(Int64(0))
+	testSExpr(t, a, "#atom[0]")
+
+	v, err := a.Swap(ctx, inc)
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(1), v)
+
+	v, err = a.Reset(ctx, Int64(10))
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(10), v)
+
+	ok, err := a.CompareAndSet(ctx, Int64(1), Int64(20))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = a.CompareAndSet(ctx, Int64(10), Int64(20))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	v, err = a.Deref(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(20), v)
+
+	eq, err := core.Eq(a, a)
+	assert.NoError(t, err)
+	assert.True(t, eq)
+}
+
+func TestAtom_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const n = 100
+	ctx := context.Background()
+	inc := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		return args[0].(Int64) + 1, nil
+	})
+
+	a := NewAtom(Int64(0))
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := a.Swap(ctx, inc)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	v, _ := a.Deref(ctx)
+	assert.Equal(t, Int64(n), v)
+}
+
+func TestAtom_Validator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	positive := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		return Bool(args[0].(Int64) > 0), nil
+	})
+
+	_, err := MakeAtom(ctx, Int64(0), Keyword("validator"), positive)
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	_, err = MakeAtom(ctx, Int64(0), Keyword("meta"))
+	assert.Error(t, err)
+
+	a, err := MakeAtom(ctx, Int64(1), Keyword("validator"), positive)
+	require.NoError(t, err)
+
+	_, err = a.Reset(ctx, Int64(-1))
+	assert.ErrorIs(t, err, ErrInvalidState)
+
+	v, _ := a.Deref(ctx)
+	assert.Equal(t, Int64(1), v, "rejected value must not be set")
+
+	assert.NoError(t, SetValidator(ctx, a, Nil{}))
+	_, err = a.Reset(ctx, Int64(-1))
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, SetValidator(ctx, a, Int64(1)), core.ErrNotInvokable)
+
+	goPositive := func(v int64) bool { return v > 0 }
+	assert.ErrorIs(t, SetValidator(ctx, a, goPositive), ErrInvalidState,
+		"go func validator must reject the current value")
+
+	_, err = a.Reset(ctx, Int64(5))
+	require.NoError(t, err)
+	require.NoError(t, SetValidator(ctx, a, goPositive))
+
+	_, err = a.Reset(ctx, Int64(-1))
+	assert.ErrorIs(t, err, ErrInvalidState)
+}
+
+func TestAtom_Watch(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	a := NewAtom(Int64(0))
+
+	var calls [][]core.Any
+	watch := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		calls = append(calls, args)
+		return Nil{}, nil
+	})
+
+	_, err := AddWatch(a, Keyword("w"), watch)
+	require.NoError(t, err)
+	_, err = AddWatch(a, Keyword("w"), watch)
+	require.NoError(t, err)
+
+	_, err = a.Reset(ctx, Int64(1))
+	require.NoError(t, err)
+	assert.Equal(t, [][]core.Any{{Keyword("w"), a, Int64(0), Int64(1)}}, calls,
+		"watch must be replaced and invoked once")
+
+	_, err = RemoveWatch(a, Keyword("w"))
+	require.NoError(t, err)
+	_, err = a.Reset(ctx, Int64(2))
+	require.NoError(t, err)
+	assert.Len(t, calls, 1)
+
+	_, err = AddWatch(a, Keyword("fail"), fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		return nil, errUnknown
+	}))
+	require.NoError(t, err)
+	_, err = a.Reset(ctx, Int64(3))
+	assert.ErrorIs(t, err, errUnknown)
+
+	_, err = RemoveWatch(a, Keyword("fail"))
+	require.NoError(t, err)
+
+	var got []core.Any
+	_, err = AddWatch(a, Keyword("go"), func(_ context.Context, key, _ core.Any, old, v int) error {
+		got = append(got, key, Int64(old), Int64(v))
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = a.Reset(ctx, Int64(4))
+	require.NoError(t, err)
+	assert.Equal(t, []core.Any{Keyword("go"), Int64(3), Int64(4)}, got)
+
+	_, err = AddWatch(a, Keyword("bad"), Keyword("not-fn"))
+	assert.ErrorIs(t, err, core.ErrNotInvokable)
+}
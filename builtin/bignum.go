@@ -0,0 +1,190 @@
+package builtin
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	_ core.Comparable       = BigInt{}
+	_ core.Comparable       = BigDecimal{}
+	_ core.Comparable       = Ratio{}
+	_ core.EqualityProvider = BigInt{}
+	_ core.EqualityProvider = BigDecimal{}
+	_ core.EqualityProvider = Ratio{}
+)
+
+// BigInt represents an arbitrary-precision integer Value. BigInt values are
+// immutable and the zero value represents 0.
+type BigInt struct{ v *big.Int }
+
+// NewBigInt returns a BigInt with the value of v. v is copied.
+func NewBigInt(v *big.Int) BigInt { return BigInt{v: new(big.Int).Set(v)} }
+
+// Big returns a copy of the value as big.Int.
+func (bi BigInt) Big() *big.Int { return new(big.Int).Set(bi.val()) }
+
+// SExpr returns a valid s-expression representing BigInt.
+func (bi BigInt) SExpr() (string, error) { return bi.String() + "N", nil }
+
+// Comp performs comparison against another number.
+func (bi BigInt) Comp(other core.Any) (int, error) { return compareNumbers(bi, other) }
+
+// Equals returns true if other is a number with the same value.
+func (bi BigInt) Equals(other core.Any) (bool, error) { return equalsByComp(bi, other) }
+
+func (bi BigInt) String() string { return bi.val().String() }
+
+func (bi BigInt) val() *big.Int {
+	if bi.v == nil {
+		return new(big.Int)
+	}
+	return bi.v
+}
+
+// BigDecimal represents an arbitrary-precision decimal Value as an unscaled
+// integer and a scale (i.e., unscaled × 10^-scale). Arithmetic on decimals
+// is exact and hence they are suitable for money. The scale is retained for
+// representation (e.g., 1.50M) but does not affect comparison and equality.
+// BigDecimal values are immutable and the zero value represents 0.
+type BigDecimal struct {
+	unscaled *big.Int
+	scale    int
+}
+
+// NewBigDecimal returns a BigDecimal with value unscaled × 10^-scale. If the
+// scale is negative, it is normalised to 0. unscaled is copied.
+func NewBigDecimal(unscaled *big.Int, scale int) BigDecimal {
+	u := new(big.Int).Set(unscaled)
+	if scale < 0 {
+		u.Mul(u, pow10(-scale))
+		scale = 0
+	}
+	return BigDecimal{unscaled: u, scale: scale}
+}
+
+// ParseBigDecimal parses a decimal string of the form [+-]digits[.digits]
+// with an optional exponent (e.g., 1.5e3) into a BigDecimal.
+func ParseBigDecimal(s string) (BigDecimal, error) {
+	mantissa, exp := s, 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return BigDecimal{}, fmt.Errorf("invalid decimal '%s'", s)
+		}
+		mantissa, exp = s[:i], e
+	}
+
+	intPart, frac := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		intPart, frac = mantissa[:i], mantissa[i+1:]
+	}
+
+	digits := strings.TrimLeft(intPart, "+-")
+	if len(intPart)-len(digits) > 1 || !isDigits(digits) || !isDigits(frac) || digits == "" {
+		return BigDecimal{}, fmt.Errorf("invalid decimal '%s'", s)
+	}
+
+	unscaled, ok := new(big.Int).SetString(intPart+frac, 10)
+	if !ok {
+		return BigDecimal{}, fmt.Errorf("invalid decimal '%s'", s)
+	}
+	return NewBigDecimal(unscaled, len(frac)-exp), nil
+}
+
+// Unscaled returns a copy of the unscaled value of the decimal.
+func (bd BigDecimal) Unscaled() *big.Int { return new(big.Int).Set(bd.val()) }
+
+// Scale returns the number of digits after the decimal point.
+func (bd BigDecimal) Scale() int { return bd.scale }
+
+// Rat returns the value of the decimal as big.Rat.
+func (bd BigDecimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(bd.val(), pow10(bd.scale))
+}
+
+// SExpr returns a valid s-expression representing BigDecimal.
+func (bd BigDecimal) SExpr() (string, error) { return bd.String() + "M", nil }
+
+// Comp performs comparison against another number.
+func (bd BigDecimal) Comp(other core.Any) (int, error) { return compareNumbers(bd, other) }
+
+// Equals returns true if other is a number with the same value irrespective
+// of the scale (i.e., 1.5M equals 1.50M).
+func (bd BigDecimal) Equals(other core.Any) (bool, error) { return equalsByComp(bd, other) }
+
+func (bd BigDecimal) String() string {
+	s := new(big.Int).Abs(bd.val()).String()
+	if bd.scale > 0 {
+		if len(s) <= bd.scale {
+			s = strings.Repeat("0", bd.scale-len(s)+1) + s
+		}
+		s = s[:len(s)-bd.scale] + "." + s[len(s)-bd.scale:]
+	}
+
+	if bd.val().Sign() < 0 {
+		return "-" + s
+	}
+	return s
+}
+
+func (bd BigDecimal) val() *big.Int {
+	if bd.unscaled == nil {
+		return new(big.Int)
+	}
+	return bd.unscaled
+}
+
+// Ratio represents an exact rational number Value (e.g., 1/3). The ratio is
+// always kept in lowest terms. Ratio values are immutable and the zero value
+// represents 0.
+type Ratio struct{ v *big.Rat }
+
+// NewRatio returns a Ratio with the value of r. r is copied.
+func NewRatio(r *big.Rat) Ratio { return Ratio{v: new(big.Rat).Set(r)} }
+
+// Rat returns a copy of the value as big.Rat.
+func (r Ratio) Rat() *big.Rat { return new(big.Rat).Set(r.val()) }
+
+// SExpr returns a valid s-expression representing Ratio.
+func (r Ratio) SExpr() (string, error) { return r.String(), nil }
+
+// Comp performs comparison against another number.
+func (r Ratio) Comp(other core.Any) (int, error) { return compareNumbers(r, other) }
+
+// Equals returns true if other is a number with the same value.
+func (r Ratio) Equals(other core.Any) (bool, error) { return equalsByComp(r, other) }
+
+func (r Ratio) String() string { return r.val().RatString() }
+
+func (r Ratio) val() *big.Rat {
+	if r.v == nil {
+		return new(big.Rat)
+	}
+	return r.v
+}
+
+func equalsByComp(c core.Comparable, other core.Any) (bool, error) {
+	res, err := c.Comp(other)
+	if err == core.ErrIncomparable {
+		return false, nil
+	}
+	return err == nil && res == 0, err
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
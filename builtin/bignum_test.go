@@ -0,0 +1,123 @@
+package builtin
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBigInt(t *testing.T) {
+	n, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	v := NewBigInt(n)
+	testSExpr(t, v, "123456789012345678901234567890N")
+	testSExpr(t, BigInt{}, "0N")
+
+	n.SetInt64(1) // must not affect v.
+	assert.Equal(t, "123456789012345678901234567890", v.String())
+
+	testComp(t, v, Int64(1), 1, nil)
+	testComp(t, v, String("1"), 0, core.ErrIncomparable)
+	testComp(t, v, NewBigInt(big.NewInt(1)), 1, nil)
+	testComp(t, NewBigInt(big.NewInt(-1)), BigInt{}, -1, nil)
+	testComp(t, NewBigInt(big.NewInt(0)), BigInt{}, 0, nil)
+
+	eq, err := v.Equals(v)
+	assert.NoError(t, err)
+	assert.True(t, eq)
+
+	eq, err = v.Equals(String("123456789012345678901234567890"))
+	assert.NoError(t, err)
+	assert.False(t, eq)
+}
+
+func TestBigDecimal(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{title: "Integer", src: "10", want: "10"},
+		{title: "WithFraction", src: "1.50", want: "1.50"},
+		{title: "Negative", src: "-0.05", want: "-0.05"},
+		{title: "PlusSign", src: "+3.1", want: "3.1"},
+		{title: "MissingIntegerPart", src: ".5", wantErr: true},
+		{title: "TrailingPoint", src: "1.", want: "1"},
+		{title: "Exponent", src: "1.5e3", want: "1500"},
+		{title: "NegativeExponent", src: "15e-3", want: "0.015"},
+		{title: "Large", src: "12345678901234567890.123456789", want: "12345678901234567890.123456789"},
+		{title: "InvalidDigits", src: "1.5a", wantErr: true},
+		{title: "InvalidSign", src: "+-1", wantErr: true},
+		{title: "InvalidExponent", src: "1e1.5", wantErr: true},
+		{title: "Empty", src: "", wantErr: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := ParseBigDecimal(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			testSExpr(t, got, tt.want+"M")
+		})
+	}
+
+	d := NewBigDecimal(big.NewInt(150), 2)
+	assert.Equal(t, 2, d.Scale())
+	assert.Equal(t, "150", d.Unscaled().String())
+	assert.Equal(t, "3/2", d.Rat().RatString())
+	assert.Equal(t, "1500", NewBigDecimal(big.NewInt(15), -2).String())
+	testSExpr(t, BigDecimal{}, "0M")
+
+	testComp(t, d, mustDecimal(t, "1.5"), 0, nil)
+	testComp(t, d, mustDecimal(t, "1.49"), 1, nil)
+	testComp(t, d, mustDecimal(t, "2"), -1, nil)
+	testComp(t, d, Float64(1.5), 0, nil)
+	testComp(t, d, Keyword("a"), 0, core.ErrIncomparable)
+
+	eq, err := d.Equals(mustDecimal(t, "1.500"))
+	assert.NoError(t, err)
+	assert.True(t, eq)
+
+	h1, err := hashOf(d)
+	assert.NoError(t, err)
+	h2, err := hashOf(mustDecimal(t, "1.5000"))
+	assert.NoError(t, err)
+	assert.Equal(t, h1, h2)
+}
+
+func TestRatio(t *testing.T) {
+	v := NewRatio(big.NewRat(2, -6))
+	testSExpr(t, v, "-1/3")
+	assert.Equal(t, "-1/3", v.Rat().RatString())
+	testSExpr(t, Ratio{}, "0")
+
+	testComp(t, v, NewRatio(big.NewRat(-1, 3)), 0, nil)
+	testComp(t, v, NewRatio(big.NewRat(1, 3)), -1, nil)
+	testComp(t, v, Int64(0), -1, nil)
+	testComp(t, v, Nil{}, 0, core.ErrIncomparable)
+
+	eq, err := v.Equals(NewRatio(big.NewRat(-2, 6)))
+	assert.NoError(t, err)
+	assert.True(t, eq)
+}
+
+func TestPersistentMap_BigNumKeys(t *testing.T) {
+	m, err := NewMap(mustDecimal(t, "1.5"), String("a"), NewRatio(big.NewRat(1, 3)), String("b"))
+	assert.NoError(t, err)
+
+	v, err := m.EntryAt(mustDecimal(t, "1.50"))
+	assert.NoError(t, err)
+	assert.Equal(t, String("a"), v)
+
+	v, err = m.EntryAt(NewRatio(big.NewRat(2, 6)))
+	assert.NoError(t, err)
+	assert.Equal(t, String("b"), v)
+}
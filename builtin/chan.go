@@ -0,0 +1,420 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+
+	"github.com/spy16/slurp/core"
+)
+
+var _ core.EqualityProvider = (*Chan)(nil)
+
+var (
+	// ErrClosed is returned when sending to or closing a channel that is
+	// closed.
+	ErrClosed = errors.New("channel closed")
+
+	// ErrChanDir is returned when sending to or closing a receive-only
+	// channel or receiving from a send-only channel.
+	ErrChanDir = errors.New("invalid channel direction")
+)
+
+// Chan wraps a Go channel so that it can be used for communication between
+// go blocks. Any Go channel (e.g., chan int passed in from Go) can be used.
+// Values sent to channels of a concrete element type are converted to that
+// type and values received are converted to builtin types when possible.
+type Chan struct{ rv reflect.Value }
+
+// NewChan returns a channel of values with the given buffer size. The size
+// must not be negative. Size 0 creates an unbuffered channel.
+func NewChan(size int) (Chan, error) {
+	if size < 0 {
+		return Chan{}, fmt.Errorf("invalid channel size %d", size)
+	}
+	return Chan{rv: reflect.ValueOf(make(chan core.Any, size))}, nil
+}
+
+// MakeChan implements (chan size?) and returns a new channel.
+func MakeChan(size ...int) (Chan, error) {
+	switch len(size) {
+	case 0:
+		return NewChan(0)
+	case 1:
+		return NewChan(size[0])
+	default:
+		return Chan{}, core.Error{
+			Cause:   core.ErrArity,
+			Message: fmt.Sprintf("chan requires at most 1 argument, got %d", len(size)),
+		}
+	}
+}
+
+// ToChan returns v as a Chan if it is a Chan or a Go channel.
+func ToChan(v core.Any) (Chan, error) {
+	if c, ok := v.(Chan); ok {
+		return c, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Chan {
+		return Chan{}, fmt.Errorf("value of type '%s' is not a channel", reflect.TypeOf(v))
+	}
+	return Chan{rv: rv}, nil
+}
+
+// Chan returns the underlying Go channel.
+func (c Chan) Chan() interface{} { return c.rv.Interface() }
+
+// Put sends v to the channel. Blocks until the value is received (or buffered)
+// or the context is done. Returns false if the channel is closed.
+func (c Chan) Put(ctx context.Context, v core.Any) (bool, error) {
+	if _, err := selectChan(ctx, []chanOp{{ch: c, val: v, put: true}}, false); err != nil {
+		if errors.Is(err, ErrClosed) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Take receives a value from the channel. Blocks until a value is available
+// or the context is done. Returns Nil if the channel is closed.
+func (c Chan) Take(ctx context.Context) (core.Any, error) {
+	res, err := selectChan(ctx, []chanOp{{ch: c}}, false)
+	if err != nil {
+		return nil, err
+	}
+	return res.val, nil
+}
+
+// Close closes the channel. Returns ErrClosed if the channel is already
+// closed.
+func (c Chan) Close() (err error) {
+	if c.rv.Type().ChanDir()&reflect.SendDir == 0 {
+		return fmt.Errorf("%w: cannot close receive-only channel '%s'", ErrChanDir, c.rv.Type())
+	}
+
+	if c.rv.IsNil() {
+		return fmt.Errorf("cannot close nil channel '%s'", c.rv.Type())
+	}
+
+	// only the panic from closing a closed channel is recovered.
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); !ok || e.Error() != "close of closed channel" {
+				panic(r)
+			}
+			err = ErrClosed
+		}
+	}()
+	c.rv.Close()
+	return nil
+}
+
+// Equals returns true if other is the same channel.
+func (c Chan) Equals(other core.Any) (bool, error) {
+	o, ok := other.(Chan)
+	return ok && c.rv.Pointer() == o.rv.Pointer(), nil
+}
+
+// SExpr returns a string representation of the channel.
+func (c Chan) SExpr() (string, error) {
+	return fmt.Sprintf("#chan[%s %d/%d]", c.rv.Type().Elem(), c.rv.Len(), c.rv.Cap()), nil
+}
+
+// Put implements (>! ch val).
+func Put(ctx context.Context, ch, v core.Any) (Bool, error) {
+	c, err := ToChan(ch)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := c.Put(ctx, v)
+	return Bool(ok), err
+}
+
+// Take implements (<! ch).
+func Take(ctx context.Context, ch core.Any) (core.Any, error) {
+	c, err := ToChan(ch)
+	if err != nil {
+		return nil, err
+	}
+	return c.Take(ctx)
+}
+
+// CloseChan implements (close! ch).
+func CloseChan(ch core.Any) error {
+	c, err := ToChan(ch)
+	if err != nil {
+		return err
+	}
+	return c.Close()
+}
+
+// Alts implements (alts! [op*] :default val?). Each op is either a channel
+// to receive from or a [ch val] vector to send val to the channel. Blocks
+// until one of the operations completes and returns [val ch] where val is
+// the value received or true if the value was sent. If :default is given,
+// [val :default] is returned immediately if no operation is ready. Sending
+// to a closed channel fails with ErrClosed.
+func Alts(ctx context.Context, ops core.Vector, opts ...core.Any) (core.Any, error) {
+	var hasDefault bool
+	var defaultVal core.Any
+	if len(opts) > 0 {
+		if len(opts) != 2 || opts[0] != Keyword("default") {
+			return nil, fmt.Errorf("alts!: invalid options %v, only ':default val' is supported", opts)
+		}
+		hasDefault, defaultVal = true, opts[1]
+	}
+
+	cnt, err := ops.Count()
+	if err != nil {
+		return nil, err
+	}
+
+	chOps := make([]chanOp, cnt)
+	for i := 0; i < cnt; i++ {
+		op, err := ops.EntryAt(i)
+		if err != nil {
+			return nil, err
+		}
+
+		if chOps[i], err = parseChanOp(op); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := selectChan(ctx, chOps, hasDefault)
+	if err != nil {
+		return nil, err
+	} else if res == nil {
+		return NewVector(defaultVal, Keyword("default")), nil
+	}
+	return NewVector(res.val, res.ch), nil
+}
+
+// chanOp is a send (put) or receive operation on a channel.
+type chanOp struct {
+	ch  Chan
+	val core.Any
+	put bool
+}
+
+func parseChanOp(op core.Any) (chanOp, error) {
+	vec, ok := op.(core.Vector)
+	if !ok {
+		c, err := ToChan(op)
+		return chanOp{ch: c}, err
+	}
+
+	if cnt, err := vec.Count(); err != nil {
+		return chanOp{}, err
+	} else if cnt != 2 {
+		return chanOp{}, fmt.Errorf("put operation must be [ch val], got %d items", cnt)
+	}
+
+	ch, err := vec.EntryAt(0)
+	if err != nil {
+		return chanOp{}, err
+	}
+
+	val, err := vec.EntryAt(1)
+	if err != nil {
+		return chanOp{}, err
+	}
+
+	c, err := ToChan(ch)
+	return chanOp{ch: c, val: val, put: true}, err
+}
+
+// selectChan performs the first operation that is ready and returns the op
+// with val set to the value received (or true if sent). Returns nil op if
+// none of the operations are ready and hasDefault is true.
+func selectChan(ctx context.Context, ops []chanOp, hasDefault bool) (res *chanOp, err error) {
+	cases := make([]reflect.SelectCase, 0, len(ops)+1)
+	for _, op := range ops {
+		if err := op.checkDir(); err != nil {
+			return nil, err
+		}
+
+		if !op.put {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: op.ch.rv})
+			continue
+		}
+
+		v, err := toElem(op.val, op.ch.rv.Type().Elem())
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: op.ch.rv, Send: v})
+	}
+
+	if hasDefault {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	} else {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+	}
+
+	defer func() {
+		if v := recover(); v != nil {
+			// sending to a closed channel panics. other panics are not
+			// expected since the ops are validated already.
+			if e, ok := v.(error); !ok || e.Error() != "send on closed channel" {
+				panic(v)
+			}
+			res, err = nil, fmt.Errorf("%w: %v", ErrClosed, v)
+		}
+	}()
+
+	chosen, recv, recvOK := reflect.Select(cases)
+	if chosen == len(ops) {
+		if hasDefault {
+			return nil, nil
+		}
+		return nil, contextErr(ctx)
+	}
+
+	op := ops[chosen]
+	if op.put {
+		op.val = Bool(true)
+	} else if !recvOK {
+		op.val = Nil{}
+	} else {
+		op.val = fromElem(recv)
+	}
+	return &op, nil
+}
+
+// checkDir returns ErrChanDir if the channel does not support the op.
+func (op chanOp) checkDir() error {
+	dir := op.ch.rv.Type().ChanDir()
+	if op.put && dir&reflect.SendDir == 0 {
+		return fmt.Errorf("%w: cannot send to receive-only channel '%s'", ErrChanDir, op.ch.rv.Type())
+	} else if !op.put && dir&reflect.RecvDir == 0 {
+		return fmt.Errorf("%w: cannot receive from send-only channel '%s'", ErrChanDir, op.ch.rv.Type())
+	}
+	return nil
+}
+
+// toElem converts v to a value of the channel element type t. Numbers are
+// converted only if the value fits in t (e.g., 1.5 is not sent to chan int
+// and 300 is not sent to chan int8).
+func toElem(v core.Any, t reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(t), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Type().AssignableTo(t) {
+		return rv, nil
+	} else if isNumberKind(rv.Kind()) && isNumberKind(t.Kind()) {
+		if !fitsNumber(rv, t) {
+			return reflect.Value{}, fmt.Errorf(
+				"value %v of type '%s' does not fit in channel of '%s'", v, rv.Type(), t)
+		}
+		return rv.Convert(t), nil
+	} else if bi, ok := v.(BigInt); ok && (isIntKind(t.Kind()) || isUintKind(t.Kind())) {
+		return bigToElem(bi, t)
+	} else if rv.Kind() == t.Kind() && rv.Type().ConvertibleTo(t) {
+		return rv.Convert(t), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf(
+		"value of type '%s' cannot be sent to channel of '%s'", rv.Type(), t)
+}
+
+// bigToElem converts the big integer to a value of the integer type t if
+// it fits in t.
+func bigToElem(bi BigInt, t reflect.Type) (reflect.Value, error) {
+	b, zero := bi.val(), reflect.Zero(t)
+	if isIntKind(t.Kind()) && b.IsInt64() && !zero.OverflowInt(b.Int64()) {
+		return reflect.ValueOf(b.Int64()).Convert(t), nil
+	} else if isUintKind(t.Kind()) && b.IsUint64() && !zero.OverflowUint(b.Uint64()) {
+		return reflect.ValueOf(b.Uint64()).Convert(t), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf(
+		"value %v of type '%s' does not fit in channel of '%s'", bi, reflect.TypeOf(bi), t)
+}
+
+// fitsNumber returns true if the number rv can be converted to the number
+// type t without losing its value.
+func fitsNumber(rv reflect.Value, t reflect.Type) bool {
+	zero := reflect.Zero(t)
+
+	switch {
+	case isIntKind(rv.Kind()):
+		i := rv.Int()
+		if isIntKind(t.Kind()) {
+			return !zero.OverflowInt(i)
+		} else if isUintKind(t.Kind()) {
+			return i >= 0 && !zero.OverflowUint(uint64(i))
+		}
+		return true
+
+	case isUintKind(rv.Kind()):
+		u := rv.Uint()
+		if isIntKind(t.Kind()) {
+			return u <= math.MaxInt64 && !zero.OverflowInt(int64(u))
+		} else if isUintKind(t.Kind()) {
+			return !zero.OverflowUint(u)
+		}
+		return true
+
+	default:
+		f := rv.Float()
+		if isIntKind(t.Kind()) {
+			return f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 &&
+				!zero.OverflowInt(int64(f))
+		} else if isUintKind(t.Kind()) {
+			return f == math.Trunc(f) && f >= 0 && f < math.MaxUint64 &&
+				!zero.OverflowUint(uint64(f))
+		}
+		return !zero.OverflowFloat(f)
+	}
+}
+
+// fromElem converts the value received from a channel to builtin type if
+// it is a Go primitive. Unsigned integers that do not fit in Int64 are
+// converted to BigInt.
+func fromElem(rv reflect.Value) core.Any {
+	if rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return Nil{}
+		}
+		return rv.Interface()
+	}
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Int64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := rv.Uint(); u > math.MaxInt64 {
+			return NewBigInt(new(big.Int).SetUint64(u))
+		}
+		return Int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return Float64(rv.Float())
+	case reflect.String:
+		return String(rv.String())
+	case reflect.Bool:
+		return Bool(rv.Bool())
+	}
+	return rv.Interface()
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUintKind(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uintptr
+}
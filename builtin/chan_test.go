@@ -0,0 +1,182 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChan(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	c, err := MakeChan(1)
+	require.NoError(t, err)
+	testSExpr(t, c, "#chan[core.Any 0/1]")
+
+	ok, err := Put(ctx, c, Keyword("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, Bool(true), ok)
+
+	v, err := Take(ctx, c)
+	assert.NoError(t, err)
+	assert.Equal(t, Keyword("a"), v)
+
+	assert.NoError(t, CloseChan(c))
+	assert.ErrorIs(t, CloseChan(c), ErrClosed)
+
+	v, err = Take(ctx, c)
+	assert.NoError(t, err)
+	assert.Equal(t, Nil{}, v, "take from closed channel must return nil")
+
+	ok, err = Put(ctx, c, Keyword("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, Bool(false), ok, "put to closed channel must return false")
+
+	_, err = MakeChan(1, 2)
+	assert.ErrorIs(t, err, core.ErrArity)
+
+	_, err = MakeChan(-1)
+	assert.Error(t, err)
+
+	_, err = Take(ctx, Int64(1))
+	assert.Error(t, err)
+}
+
+func TestChan_GoChannel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ch := make(chan int, 2)
+
+	ok, err := Put(ctx, ch, Int64(10))
+	assert.NoError(t, err)
+	assert.Equal(t, Bool(true), ok)
+	assert.Equal(t, 10, <-ch)
+
+	ch <- 20
+	v, err := Take(ctx, ch)
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(20), v)
+
+	_, err = Put(ctx, ch, String("foo"))
+	assert.Error(t, err, "string must not be sent to chan int")
+
+	recvOnly := (<-chan int)(ch)
+	assert.ErrorIs(t, CloseChan(recvOnly), ErrChanDir)
+
+	var nilCh chan int
+	err = CloseChan(nilCh)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrClosed, "nil channel must not be reported as closed")
+
+	_, err = Put(ctx, recvOnly, Int64(1))
+	assert.ErrorIs(t, err, ErrChanDir)
+
+	_, err = Take(ctx, (chan<- int)(ch))
+	assert.ErrorIs(t, err, ErrChanDir)
+
+	_, err = Alts(ctx, NewVector(ch, NewVector(recvOnly, Int64(1))))
+	assert.ErrorIs(t, err, ErrChanDir)
+}
+
+func TestChan_GoChannel_Numbers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	table := []struct {
+		title string
+		ch    interface{}
+		val   core.Any
+		want  core.Any
+	}{
+		{title: "IntegralFloatToInt", ch: make(chan int, 1), val: Float64(2), want: Int64(2)},
+		{title: "FractionalFloatToInt", ch: make(chan int, 1), val: Float64(1.5)},
+		{title: "NaNToInt", ch: make(chan int64, 1), val: Float64(math.NaN())},
+		{title: "HugeFloatToInt", ch: make(chan int64, 1), val: Float64(1e300)},
+		{title: "IntToInt8", ch: make(chan int8, 1), val: Int64(-128), want: Int64(-128)},
+		{title: "IntOverflowsInt8", ch: make(chan int8, 1), val: Int64(300)},
+		{title: "IntToUint8", ch: make(chan uint8, 1), val: Int64(200), want: Int64(200)},
+		{title: "IntOverflowsUint8", ch: make(chan uint8, 1), val: Int64(256)},
+		{title: "NegativeToUint", ch: make(chan uint, 1), val: Int64(-1)},
+		{title: "FloatToFloat32", ch: make(chan float32, 1), val: Float64(0.5), want: Float64(0.5)},
+		{title: "FloatOverflowsFloat32", ch: make(chan float32, 1), val: Float64(1e300)},
+		{title: "IntToFloat", ch: make(chan float64, 1), val: Int64(3), want: Float64(3)},
+		{title: "MaxUint64", ch: make(chan uint64, 1), val: NewBigInt(new(big.Int).SetUint64(math.MaxUint64)),
+			want: NewBigInt(new(big.Int).SetUint64(math.MaxUint64))},
+		{title: "BigIntToInt64", ch: make(chan int64, 1), val: NewBigInt(big.NewInt(-5)), want: Int64(-5)},
+		{title: "BigIntOverflowsUint64", ch: make(chan uint64, 1), val: NewBigInt(new(big.Int).Lsh(big.NewInt(1), 64))},
+		{title: "NegativeBigIntToUint", ch: make(chan uint64, 1), val: NewBigInt(big.NewInt(-1))},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ok, err := Put(ctx, tt.ch, tt.val)
+			if tt.want == nil {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, Bool(true), ok)
+
+			got, err := Take(ctx, tt.ch)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChan_Cancel(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewChan(0)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = Take(ctx, c)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, err = Put(ctx, c, Int64(1))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAlts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	a, _ := NewChan(0)
+	b, _ := NewChan(1)
+	closed, _ := NewChan(0)
+	_ = closed.Close()
+
+	got, err := Alts(ctx, NewVector(a, b), Keyword("default"), Keyword("none"))
+	assert.NoError(t, err)
+	assert.Equal(t, NewVector(Keyword("none"), Keyword("default")), got)
+
+	got, err = Alts(ctx, NewVector(a, NewVector(b, Int64(1))))
+	assert.NoError(t, err)
+	assert.Equal(t, NewVector(Bool(true), b), got)
+
+	got, err = Alts(ctx, NewVector(a, b))
+	assert.NoError(t, err)
+	assert.Equal(t, NewVector(Int64(1), b), got)
+
+	_, err = Alts(ctx, NewVector(NewVector(closed, Int64(1))))
+	assert.ErrorIs(t, err, ErrClosed)
+
+	_, err = Alts(ctx, NewVector(a), Keyword("timeout"))
+	assert.Error(t, err)
+
+	_, err = Alts(ctx, NewVector(NewVector(a)))
+	assert.Error(t, err)
+}
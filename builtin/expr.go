@@ -1,6 +1,7 @@
 package builtin
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -14,8 +15,20 @@ var (
 	_ core.Expr = (*DefExpr)(nil)
 	_ core.Expr = (*QuoteExpr)(nil)
 	_ core.Expr = (*ConstExpr)(nil)
+	_ core.Expr = (*FnExpr)(nil)
 	_ core.Expr = (*InvokeExpr)(nil)
+	_ core.Expr = (*LoopExpr)(nil)
+	_ core.Expr = (*BindingExpr)(nil)
+	_ core.Expr = (*RecurExpr)(nil)
+	_ core.Expr = (*TryExpr)(nil)
+	_ core.Expr = (*ThrowExpr)(nil)
+	_ core.Expr = (*InNSExpr)(nil)
+	_ core.Expr = (*ReferExpr)(nil)
+	_ core.Expr = (*AliasExpr)(nil)
 	_ core.Expr = (*ResolveExpr)(nil)
+	_ core.Expr = (*VectorExpr)(nil)
+	_ core.Expr = (*MapExpr)(nil)
+	_ core.Expr = (*SetExpr)(nil)
 )
 
 // ConstExpr returns the Const value wrapped inside when evaluated. It has
@@ -25,6 +38,17 @@ type ConstExpr struct{ Const core.Any }
 // Eval returns the constant value unmodified.
 func (ce ConstExpr) Eval(_ core.Env) (core.Any, error) { return ce.Const, nil }
 
+// FnExpr creates a closure of the Fn definition when evaluated.
+type FnExpr struct{ Fn Fn }
+
+// Eval returns a copy of the Fn bound to the given env so that the body
+// can resolve symbols from the scope in which the Fn was created.
+func (fe FnExpr) Eval(env core.Env) (core.Any, error) {
+	fn := fe.Fn
+	fn.Env = env
+	return fn, nil
+}
+
 // QuoteExpr expression represents a quoted form and
 type QuoteExpr struct{ Form core.Any }
 
@@ -33,11 +57,14 @@ func (qe QuoteExpr) Eval(_ core.Env) (core.Any, error) { return qe.Form, nil }
 
 // DefExpr represents the (def name value) binding form.
 type DefExpr struct {
-	Name  string
-	Value core.Expr
+	Name    string
+	Value   core.Expr
+	Dynamic bool
 }
 
-// Eval creates the binding with the name and value in Root env.
+// Eval creates the binding with the name and value in the current
+// namespace, or in the Root env if namespaces are not in use. If Dynamic
+// is true, the name is bound to a dynamic var with the value as the root.
 func (de DefExpr) Eval(env core.Env) (core.Any, error) {
 	var val core.Any
 	var err error
@@ -50,7 +77,22 @@ func (de DefExpr) Eval(env core.Env) (core.Any, error) {
 		val = Nil{}
 	}
 
-	if err := core.Root(env).Bind(de.Name, val); err != nil {
+	ns := CurrentNS(env)
+	if de.Dynamic {
+		name := de.Name
+		if ns != nil {
+			name = ns.Name() + "/" + name
+		}
+		val = NewVar(name, val)
+	}
+
+	if ns != nil {
+		err = ns.Bind(de.Name, val)
+	} else {
+		err = core.Root(env).Bind(de.Name, val)
+	}
+
+	if err != nil {
 		return nil, err
 	}
 	return Symbol(de.Name), nil
@@ -63,17 +105,237 @@ type LetExpr struct {
 	Exprs  DoExpr
 }
 
-// Eval creates binds name-value pair to a child environment, and
-// then evaluates the expressions.
-func (le LetExpr) Eval(env core.Env) (_ core.Any, err error) {
-	bs := make(map[string]core.Any, len(le.Names))
+// Eval binds each name-value pair in order to a child environment, and
+// then evaluates the expressions. Values are evaluated in the child env
+// so that each value can refer to the bindings before it.
+func (le LetExpr) Eval(env core.Env) (core.Any, error) {
+	letEnv := env.Child("<let>", nil)
 	for i, symbol := range le.Names {
-		if bs[symbol], err = le.Values[i].Eval(env); err != nil {
-			return
+		v, err := le.Values[i].Eval(letEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := letEnv.Bind(symbol, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return le.Exprs.Eval(letEnv)
+}
+
+// LoopExpr represents the (loop [binding*] expr*) form.
+type LoopExpr struct {
+	Names  []string
+	Values []core.Expr
+	Body   core.Expr
+}
+
+// Eval binds the name-value pairs in order to a child environment and
+// evaluates the body. As long as the body evaluates a RecurExpr, the body
+// is evaluated again with the names bound to the recur values. Iteration
+// runs in constant stack.
+func (le LoopExpr) Eval(env core.Env) (core.Any, error) {
+	ctx := core.ContextOf(env)
+	loopEnv := env.Child("<loop>", nil)
+	for i, name := range le.Names {
+		v, err := le.Values[i].Eval(loopEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := loopEnv.Bind(name, v); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		if err := step(ctx); err != nil {
+			return nil, err
+		}
+
+		res, err := le.Body.Eval(loopEnv)
+		if err != nil {
+			return nil, err
+		}
+
+		r, ok := res.(recur)
+		if !ok {
+			return res, nil
+		}
+
+		// a fresh env for each iteration so that closures created in an
+		// iteration retain their bindings.
+		loopEnv = env.Child("<loop>", nil)
+		if err := r.bind(loopEnv, le.Names); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// RecurExpr represents the (recur expr*) form. RecurExpr must appear only
+// in the tail position of a LoopExpr or Fn body.
+type RecurExpr struct{ Args []core.Expr }
+
+// Eval evaluates the args and returns the values to be re-bound by the
+// enclosing LoopExpr or Fn.
+func (re RecurExpr) Eval(env core.Env) (core.Any, error) {
+	vals := make(recur, len(re.Args))
+	for i, arg := range re.Args {
+		v, err := arg.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return vals, nil
+}
+
+// TryExpr represents the (try expr* catch* finally?) form.
+type TryExpr struct {
+	Body    core.Expr
+	Catches []CatchExpr
+	Finally core.Expr
+}
+
+// Eval evaluates the body and returns the result. If the body fails, the
+// first catch clause that matches the error is evaluated for the result.
+// Error is returned if none of the clauses match. Errors that stop the
+// evaluation on behalf of the host (i.e., core.ErrBudgetExceeded and the
+// context being done) are never caught. Finally is evaluated in all cases
+// and its result is ignored, but an error from it is returned.
+func (te TryExpr) Eval(env core.Env) (res core.Any, err error) {
+	if te.Finally != nil {
+		defer func() {
+			if _, finErr := te.Finally.Eval(env); finErr != nil {
+				res, err = nil, finErr
+			}
+		}()
+	}
+
+	res, err = te.Body.Eval(env)
+	if err == nil {
+		return res, nil
+	} else if !catchable(err) {
+		return nil, err
+	}
+
+	for _, c := range te.Catches {
+		matched, matchErr := c.match(env, err)
+		if matchErr != nil {
+			return nil, matchErr
+		} else if matched {
+			return c.eval(env, err)
+		}
+	}
+
+	return nil, err
+}
+
+// CatchExpr represents the (catch matcher name expr*) clause of try. The
+// Matcher must evaluate to one of:
+//
+//   - error: matches if errors.Is(err, matcher).
+//   - reflect.Type: matches if the caught value is assignable to the type.
+//   - :default keyword: matches any error.
+//
+// The caught value (bound to Name) is the thrown value if the error was
+// created by throw (see ThrowExpr), and the error itself otherwise.
+type CatchExpr struct {
+	Matcher core.Expr
+	Name    string
+	Body    core.Expr
+}
+
+func (ce CatchExpr) match(env core.Env, err error) (bool, error) {
+	m, evalErr := ce.Matcher.Eval(env)
+	if evalErr != nil {
+		return false, evalErr
+	}
+
+	switch matcher := m.(type) {
+	case error:
+		return errors.Is(err, matcher), nil
+
+	case reflect.Type:
+		return reflect.TypeOf(caughtValue(err)).AssignableTo(matcher), nil
+
+	case Keyword:
+		if matcher == "default" {
+			return true, nil
 		}
 	}
 
-	return le.Exprs.Eval(env.Child("<let>", bs))
+	return false, fmt.Errorf(
+		"invalid catch matcher of type '%s'", reflect.TypeOf(m))
+}
+
+func (ce CatchExpr) eval(env core.Env, err error) (core.Any, error) {
+	catchEnv := env.Child("<catch>", nil)
+	if e := catchEnv.Bind(ce.Name, caughtValue(err)); e != nil {
+		return nil, e
+	}
+	return ce.Body.Eval(catchEnv)
+}
+
+// catchable returns false if the error stops the evaluation on behalf of
+// the host and hence must not be caught by the evaluated code.
+func catchable(err error) bool {
+	return !errors.Is(err, core.ErrBudgetExceeded) &&
+		!errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded)
+}
+
+func caughtValue(err error) core.Any {
+	if e, ok := err.(core.Error); ok && e.Message == "" && e.Value == nil && len(e.Stack) > 0 {
+		// error wrapped only to record the stack trace.
+		err = e.Cause
+	}
+
+	var e core.Error
+	if errors.As(err, &e) && e.Value != nil {
+		return e.Value
+	}
+	return err
+}
+
+// ThrowExpr represents the (throw expr) form.
+type ThrowExpr struct{ Value core.Expr }
+
+// Eval evaluates the value and returns it as the error. Values that are
+// not errors are wrapped in core.Error with ErrThrown as the cause.
+func (te ThrowExpr) Eval(env core.Env) (core.Any, error) {
+	v, err := te.Value.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if e, ok := v.(error); ok {
+		return nil, e
+	}
+
+	return nil, core.Error{
+		Cause:   core.ErrThrown,
+		Message: fmt.Sprintf("%v", v),
+		Value:   v,
+	}
+}
+
+// recur is the result of evaluating a RecurExpr.
+type recur []core.Any
+
+func (r recur) bind(env core.Env, names []string) error {
+	if len(r) != len(names) {
+		return fmt.Errorf("%w: recur with %d args, want %d",
+			core.ErrArity, len(r), len(names))
+	}
+
+	for i, name := range names {
+		if err := env.Bind(name, r[i]); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // IfExpr represents the if-then-else form.
@@ -121,16 +383,81 @@ func (de DoExpr) Eval(env core.Env) (core.Any, error) {
 	return res, nil
 }
 
+// BindingExpr represents the (binding [name value*] expr*) form.
+type BindingExpr struct {
+	Names  []Symbol
+	Values []core.Expr
+	Body   core.Expr
+}
+
+// Eval rebinds the dynamic vars referred to by the names to the values for
+// the dynamic extent of the body (i.e., the body and all the functions it
+// invokes). Values are evaluated before any of the vars are rebound.
+func (be BindingExpr) Eval(env core.Env) (core.Any, error) {
+	vals := make(map[*Var]core.Any, len(be.Names))
+	for i, name := range be.Names {
+		dv, err := ResolveVar(env, name)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := be.Values[i].Eval(env)
+		if err != nil {
+			return nil, err
+		}
+		vals[dv] = v
+	}
+
+	ctx := WithBindings(core.ContextOf(env), vals)
+	return be.Body.Eval(core.ContextChild(env, "<binding>", ctx))
+}
+
 // ResolveExpr resolves a symbol from the given environment.
 type ResolveExpr struct{ Symbol Symbol }
 
 // Eval resolves the symbol in the given environment or its parent env
-// and returns the result. Returns ErrNotFound if the symbol was not
-// found in the entire hierarchy.
+// and returns the result. Global bindings in the current namespace are
+// resolved before the Root env. Qualified symbols (i.e., ns/name) are
+// resolved in the namespace (or alias) directly. Returns ErrNotFound if
+// the symbol was not found in the entire hierarchy. If the symbol refers
+// to a dynamic var, the value of the var in the context of the env is
+// returned.
 func (re ResolveExpr) Eval(env core.Env) (core.Any, error) {
+	v, err := re.resolve(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if dv, ok := v.(*Var); ok {
+		return dv.Deref(core.ContextOf(env))
+	}
+	return v, nil
+}
+
+func (re ResolveExpr) resolve(env core.Env) (core.Any, error) {
+	if nsName, name, ok := splitQualified(re.Symbol); ok {
+		if ns, err := FindNS(env, nsName); err == nil {
+			return ns.Resolve(name)
+		}
+		// not a namespace. resolve as a regular symbol.
+	}
+
+	// the current namespace is looked up using the env being evaluated since
+	// its context may carry the namespace (See WithCurrentNS).
+	evalEnv := env
+
 	var v core.Any
 	var err error
 	for env != nil {
+		if env.Parent() == nil {
+			if ns := CurrentNS(evalEnv); ns != nil {
+				v, err = ns.Resolve(string(re.Symbol))
+				if !errors.Is(err, core.ErrNotFound) {
+					break
+				}
+			}
+		}
+
 		v, err = env.Resolve(string(re.Symbol))
 		if errors.Is(err, core.ErrNotFound) {
 			// not found in the current frame. check parent.
@@ -145,32 +472,110 @@ func (re ResolveExpr) Eval(env core.Env) (core.Any, error) {
 	return v, err
 }
 
+// InNSExpr represents the (in-ns name) form.
+type InNSExpr struct{ Name string }
+
+// Eval sets the namespace as the current namespace (creating it if
+// required) and returns it.
+func (ie InNSExpr) Eval(env core.Env) (core.Any, error) {
+	return InNS(env, ie.Name)
+}
+
+// ReferExpr represents the (refer ns name*) form.
+type ReferExpr struct {
+	NS    string
+	Names []string
+}
+
+// Eval refers the names (or all if none) from the namespace in the
+// current namespace.
+func (re ReferExpr) Eval(env core.Env) (core.Any, error) {
+	cur := CurrentNS(env)
+	if cur == nil {
+		return nil, fmt.Errorf("%w: no current namespace", ErrNoNamespace)
+	}
+
+	other, err := FindNS(env, re.NS)
+	if err != nil {
+		return nil, err
+	}
+
+	cur.Refer(other, re.Names...)
+	return Nil{}, nil
+}
+
+// AliasExpr represents the (alias alias ns) form.
+type AliasExpr struct {
+	Alias string
+	NS    string
+}
+
+// Eval creates the alias for the namespace in the current namespace.
+func (ae AliasExpr) Eval(env core.Env) (core.Any, error) {
+	cur := CurrentNS(env)
+	if cur == nil {
+		return nil, fmt.Errorf("%w: no current namespace", ErrNoNamespace)
+	}
+
+	other, err := FindNS(env, ae.NS)
+	if err != nil {
+		return nil, err
+	}
+
+	cur.Alias(ae.Alias, other)
+	return Nil{}, nil
+}
+
 // GoExpr evaluates an expression in a separate goroutine.
 type GoExpr struct{ Form core.Expr }
 
 // Eval forks the given env to get a child env and launches goroutine
-// with the child env to evaluate the form.
+// with the child env to evaluate the form. Returns a Future of the result
+// of the form. Error from the evaluation is returned when the future is
+// dereferenced.
 func (ge GoExpr) Eval(env core.Env) (core.Any, error) {
-	// TODO: verify this.
 	e := env.Child("<go>", nil)
 
-	go func() {
-		_, _ = ge.Form.Eval(e)
-	}()
+	return NewFuture(func() (core.Any, error) {
+		return ge.Form.Eval(e)
+	}), nil
+}
+
+// LazySeqExpr evaluates to a LazySeq that evaluates Body when the sequence
+// is realized. Body is evaluated in the env of the LazySeqExpr and must
+// evaluate to nil, a Seq or a Seqable value.
+type LazySeqExpr struct{ Body core.Expr }
 
-	return nil, nil
+// Eval returns a new LazySeq without evaluating the Body.
+func (le LazySeqExpr) Eval(env core.Env) (core.Any, error) {
+	return NewLazySeq(func() (core.Any, error) {
+		return le.Body.Eval(env)
+	}), nil
 }
 
-// InvokeExpr performs invocation of target when evaluated.
+// InvokeExpr performs invocation of target when evaluated. If Tail is
+// true, the invocation is in the tail position of an Fn body and an Fn
+// target is not invoked directly but by the Fn.Invoke of the enclosing
+// Fn. This keeps the stack bounded for tail calls. Form and Position of
+// the call-site are recorded in the stack trace of errors returned by
+// the invocation.
 type InvokeExpr struct {
-	Name   string
-	Target core.Expr
-	Args   []core.Expr
+	Name     string
+	Target   core.Expr
+	Args     []core.Expr
+	Tail     bool
+	Form     core.Any
+	Position core.Position
 }
 
 // Eval evaluates the target expr and invokes the result if it is an
 // Invokable  Returns error otherwise.
 func (ie InvokeExpr) Eval(env core.Env) (core.Any, error) {
+	ctx := core.ContextOf(env)
+	if err := step(ctx); err != nil {
+		return nil, core.WithFrame(err, ie.frame())
+	}
+
 	val, err := ie.Target.Eval(env)
 	if err != nil {
 		return nil, err
@@ -196,7 +601,30 @@ func (ie InvokeExpr) Eval(env core.Env) (core.Any, error) {
 		}
 	}
 
-	return fn.Invoke(args...)
+	if target, ok := fn.(Fn); ok && ie.Tail {
+		return tailCall{fn: target, args: args, frame: ie.frame()}, nil
+	}
+
+	meter := core.MeterOf(ctx)
+	if err := meter.Enter(); err != nil {
+		meter.Leave()
+		return nil, core.WithFrame(err, ie.frame())
+	}
+
+	res, err := invoke(ctx, fn, args...)
+	meter.Leave()
+	if err != nil {
+		return nil, core.WithFrame(err, ie.frame())
+	}
+
+	if err := checkLength(meter, res); err != nil {
+		return nil, core.WithFrame(err, ie.frame())
+	}
+	return res, nil
+}
+
+func (ie InvokeExpr) frame() core.Frame {
+	return core.Frame{Name: ie.Name, Form: ie.Form, Position: ie.Position}
 }
 
 // VectorExpr evaluates a vector.
@@ -207,10 +635,17 @@ type VectorExpr struct {
 
 // Eval returns a new vector whose contents are the evaluated values
 // of the objects contained by the evaluated vector. Elements are evaluated left to right.
+// Source position is removed from the metadata of the result.
 func (vex VectorExpr) Eval(env core.Env) (core.Any, error) {
+	if err := checkLiteral(env, vex.Vector); err != nil {
+		return nil, err
+	}
+
 	cnt, err := vex.Vector.Count()
-	if err != nil || cnt == 0 {
-		return vex.Vector, err
+	if err != nil {
+		return nil, err
+	} else if cnt == 0 {
+		return withoutPosition(vex.Vector)
 	}
 
 	for i := 0; i < cnt; i++ {
@@ -233,5 +668,186 @@ func (vex VectorExpr) Eval(env core.Env) (core.Any, error) {
 		}
 	}
 
-	return vex.Vector, nil
+	return withoutPosition(vex.Vector)
+}
+
+// MapExpr evaluates a map.
+type MapExpr struct {
+	Analyzer core.Analyzer
+	Map      core.Map
+}
+
+// Eval returns a new map whose keys and values are the evaluated values
+// of the keys and values contained by the map. Returns error if two keys
+// evaluate to the same value. Source position is removed from the metadata
+// of the result.
+func (me MapExpr) Eval(env core.Env) (core.Any, error) {
+	if err := checkLiteral(env, me.Map); err != nil {
+		return nil, err
+	}
+
+	seq, err := me.Map.Seq()
+	if err != nil {
+		return nil, err
+	}
+
+	var res core.Map = EmptyMap
+	err = core.ForEach(seq, func(item core.Any) (bool, error) {
+		entry, ok := item.(core.Vector)
+		if !ok {
+			return true, fmt.Errorf("invalid map entry of type '%s'", reflect.TypeOf(item))
+		}
+
+		key, err := entry.EntryAt(0)
+		if err != nil {
+			return true, err
+		}
+
+		val, err := entry.EntryAt(1)
+		if err != nil {
+			return true, err
+		}
+
+		newKey, err := core.Eval(env, me.Analyzer, key)
+		if err != nil {
+			return true, err
+		}
+
+		newVal, err := core.Eval(env, me.Analyzer, val)
+		if err != nil {
+			return true, err
+		}
+
+		if found, err := res.HasKey(newKey); err != nil {
+			return true, err
+		} else if found {
+			return true, fmt.Errorf("duplicate key: %v", newKey)
+		}
+
+		res, err = res.Assoc(newKey, newVal)
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutPosition(withMetaOf(me.Map, res))
+}
+
+// SetExpr evaluates a set.
+type SetExpr struct {
+	Analyzer core.Analyzer
+	Set      core.Set
+}
+
+// Eval returns a new set whose members are the evaluated values of the
+// members of the set. Returns error if two members evaluate to the same
+// value. Source position is removed from the metadata of the result.
+func (se SetExpr) Eval(env core.Env) (core.Any, error) {
+	if err := checkLiteral(env, se.Set); err != nil {
+		return nil, err
+	}
+
+	seq, err := se.Set.Seq()
+	if err != nil {
+		return nil, err
+	}
+
+	var res core.Set = EmptySet
+	err = core.ForEach(seq, func(item core.Any) (bool, error) {
+		other, err := core.Eval(env, se.Analyzer, item)
+		if err != nil {
+			return true, err
+		}
+
+		if found, err := res.Contains(other); err != nil {
+			return true, err
+		} else if found {
+			return true, fmt.Errorf("duplicate item: %v", other)
+		}
+
+		res, err = res.Conj(other)
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutPosition(withMetaOf(se.Set, res))
+}
+
+// withMetaOf returns coll with the metadata of the literal form (if any).
+func withMetaOf(form, coll core.Any) core.Any {
+	src, ok := form.(core.Meta)
+	if !ok || src.Meta() == nil {
+		return coll
+	}
+
+	if dst, ok := coll.(core.Meta); ok {
+		return dst.WithMeta(src.Meta())
+	}
+	return coll
+}
+
+// checkLiteral records the length of the collection literal in the meter of
+// the env (if any). The evaluated collection has as many items as the
+// literal and hence the length is checked before it is built.
+func checkLiteral(env core.Env, form core.Any) error {
+	return checkLength(core.MeterOf(core.ContextOf(env)), form)
+}
+
+// checkLength records the length of v in the meter if v is a collection.
+// Sequences that are not counted (e.g., lazy sequences) are not realized
+// and not recorded.
+func checkLength(m *core.Meter, v core.Any) error {
+	if m == nil {
+		return nil
+	} else if seq, ok := v.(core.Seq); ok && !core.IsCounted(seq) {
+		return nil
+	}
+
+	var cnt int
+	var err error
+	switch coll := v.(type) {
+	case core.Vector:
+		cnt, err = coll.Count()
+	case core.Map:
+		cnt, err = coll.Count()
+	case core.Set:
+		cnt, err = coll.Count()
+	case *LinkedList:
+		cnt, err = coll.Count()
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return m.Length(cnt)
+}
+
+// invoke invokes fn with the context if it is a core.ContextInvokable.
+func invoke(ctx context.Context, fn core.Invokable, args ...core.Any) (core.Any, error) {
+	if ci, ok := fn.(core.ContextInvokable); ok {
+		return ci.InvokeContext(ctx, args...)
+	}
+	return fn.Invoke(args...)
+}
+
+// step checks the context for cancellation and records a step in the meter
+// of the context (if any).
+func step(ctx context.Context) error {
+	if err := contextErr(ctx); err != nil {
+		return err
+	}
+	return core.MeterOf(ctx).Step()
+}
+
+// contextErr returns the error of the context wrapped in core.Error if the
+// context is done. Returns nil otherwise.
+func contextErr(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return core.Error{Cause: err}
+	}
+	return nil
 }
@@ -1,7 +1,9 @@
 package builtin
 
 import (
+	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/spy16/slurp/core"
@@ -188,24 +190,90 @@ func TestIfExpr_Eval(t *testing.T) {
 
 func TestGoExpr_Eval(t *testing.T) {
 	t.Parallel()
+
+	table := []struct {
+		title   string
+		form    core.Expr
+		want    core.Any
+		wantErr string
+	}{
+		{title: "WithError", form: fakeExpr{Err: errUnknown}, wantErr: "failed"},
+		{title: "WithSuccess", form: fakeExpr{Res: 100}, want: 100},
+		{title: "WithPanic", form: panicExpr{}, wantErr: "panic: failed"},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := GoExpr{Form: tt.form}.Eval(core.New(nil))
+			assert.NoError(t, err)
+
+			f, ok := got.(*Future)
+			if !assert.True(t, ok, "want *Future, got %#v", got) {
+				return
+			}
+
+			v, err := f.Deref(context.Background())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, v)
+			assert.True(t, f.Realized())
+		})
+	}
+}
+
+func TestBindingExpr_Eval(t *testing.T) {
+	t.Parallel()
+
+	x := NewVar("x", Int64(1))
+	getX := fakeContextInvokable(func(ctx context.Context, args ...core.Any) (core.Any, error) {
+		return x.Deref(ctx)
+	})
+
 	runExprTests(t, []exprTest{
 		{
-			title: "WithError",
+			title: "RootValue",
 			expr: func() (core.Expr, core.Env) {
-				return &GoExpr{
-					Form: fakeExpr{Err: errUnknown},
-				}, core.New(nil)
+				return ResolveExpr{Symbol: "x"}, core.New(map[string]core.Any{"x": x})
 			},
-			wantErr: nil,
+			want: Int64(1),
 		},
 		{
-			title: "WithSuccess",
+			title: "Rebound",
 			expr: func() (core.Expr, core.Env) {
-				return &GoExpr{
-					Form: fakeExpr{Res: 100},
-				}, core.New(nil)
+				return BindingExpr{
+					Names:  []Symbol{"x"},
+					Values: []core.Expr{ConstExpr{Const: Int64(2)}},
+					Body: DoExpr{InvokeExpr{
+						Target: ConstExpr{Const: getX},
+					}},
+				}, core.New(map[string]core.Any{"x": x})
+			},
+			want: Int64(2),
+		},
+		{
+			title: "NotDynamic",
+			expr: func() (core.Expr, core.Env) {
+				return BindingExpr{
+					Names:  []Symbol{"y"},
+					Values: []core.Expr{ConstExpr{Const: Int64(2)}},
+					Body:   DoExpr{},
+				}, core.New(map[string]core.Any{"y": Int64(1)})
+			},
+			wantErr: ErrNotDynamic,
+		},
+		{
+			title: "ValueEvalErr",
+			expr: func() (core.Expr, core.Env) {
+				return BindingExpr{
+					Names:  []Symbol{"x"},
+					Values: []core.Expr{fakeExpr{Err: errUnknown}},
+					Body:   DoExpr{},
+				}, core.New(map[string]core.Any{"x": x})
 			},
-			wantErr: nil,
+			wantErr: errUnknown,
 		},
 	})
 }
@@ -231,6 +299,32 @@ func TestInvokeExpr_Eval(t *testing.T) {
 			},
 			wantErr: core.ErrNotInvokable,
 		},
+		{
+			title: "ContextCancelled",
+			expr: func() (core.Expr, core.Env) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				return &InvokeExpr{
+					Target: ConstExpr{Const: fakeInvokable(func(args ...core.Any) (core.Any, error) {
+						return nil, errUnknown
+					})},
+				}, core.ContextChild(core.New(nil), "<eval>", ctx)
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			title: "InvokeContext",
+			expr: func() (core.Expr, core.Env) {
+				ctx := context.WithValue(context.Background(), fakeCtxKey{}, "bar")
+				return &InvokeExpr{
+					Target: ConstExpr{Const: fakeContextInvokable(func(ctx context.Context, args ...core.Any) (core.Any, error) {
+						return ctx.Value(fakeCtxKey{}), nil
+					})},
+				}, core.ContextChild(core.New(nil), "<eval>", ctx)
+			},
+			want: "bar",
+		},
 		{
 			title: "InvokeWithArgs",
 			expr: func() (core.Expr, core.Env) {
@@ -247,6 +341,17 @@ func TestInvokeExpr_Eval(t *testing.T) {
 			},
 			want: 10,
 		},
+		{
+			title: "TailCall",
+			expr: func() (core.Expr, core.Env) {
+				return &InvokeExpr{
+					Target: ConstExpr{Const: Fn{}},
+					Args:   []core.Expr{ConstExpr{Const: 10}},
+					Tail:   true,
+				}, core.New(nil)
+			},
+			want: tailCall{fn: Fn{}, args: []core.Any{10}},
+		},
 		{
 			title: "ArgEvalErr",
 			expr: func() (core.Expr, core.Env) {
@@ -259,6 +364,29 @@ func TestInvokeExpr_Eval(t *testing.T) {
 			},
 			wantErr: errUnknown,
 		},
+		{
+			title: "InvokeErrFrame",
+			expr: func() (core.Expr, core.Env) {
+				return &InvokeExpr{
+					Name: "fail",
+					Target: ConstExpr{Const: fakeInvokable(func(args ...core.Any) (core.Any, error) {
+						return nil, errUnknown
+					})},
+					Form:     NewList(Symbol("fail")),
+					Position: core.Position{File: "test.slurp", Ln: 1, Col: 1},
+				}, core.New(nil)
+			},
+			wantErr: errUnknown,
+			assert: func(t *testing.T, _ core.Any, err error, _ core.Env) {
+				var e core.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, []core.Frame{{
+					Name:     "fail",
+					Form:     NewList(Symbol("fail")),
+					Position: core.Position{File: "test.slurp", Ln: 1, Col: 1},
+				}}, e.Stack)
+			},
+		},
 	})
 }
 
@@ -313,6 +441,443 @@ func TestVectorExpr_Eval(t *testing.T) {
 	})
 }
 
+func TestMapExpr_Eval(t *testing.T) {
+	t.Run("ConstMembers", func(t *testing.T) {
+		m, err := NewMap(Keyword("foo"), Int64(1))
+		assert.NoError(t, err)
+
+		got, err := (MapExpr{
+			Analyzer: &Analyzer{},
+			Map:      m,
+		}).Eval(core.New(nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, m, got)
+	})
+
+	t.Run("SymbolMembers", func(t *testing.T) {
+		env := core.New(map[string]core.Any{
+			"k": Keyword("key"),
+			"v": String("value"),
+		})
+		m, err := NewMap(Symbol("k"), Symbol("v"), Keyword("foo"), Symbol("v"))
+		assert.NoError(t, err)
+
+		got, err := (MapExpr{
+			Analyzer: &Analyzer{},
+			Map:      m,
+		}).Eval(env)
+		assert.NoError(t, err)
+
+		want, err := NewMap(Keyword("key"), String("value"), Keyword("foo"), String("value"))
+		assert.NoError(t, err)
+
+		eq, err := core.Eq(want, got)
+		assert.NoError(t, err)
+		assert.True(t, eq, "want=%v\ngot=%v", want, got)
+	})
+
+	t.Run("KeyEvaluatesToOtherKey", func(t *testing.T) {
+		env := core.New(map[string]core.Any{
+			"a": Int64(5),
+			"b": Symbol("a"),
+		})
+		m, err := NewMap(Symbol("a"), Int64(1), Symbol("b"), Int64(2))
+		assert.NoError(t, err)
+
+		got, err := (MapExpr{
+			Analyzer: &Analyzer{},
+			Map:      m,
+		}).Eval(env)
+		assert.NoError(t, err)
+
+		want, err := NewMap(Int64(5), Int64(1), Symbol("a"), Int64(2))
+		assert.NoError(t, err)
+
+		eq, err := core.Eq(want, got)
+		assert.NoError(t, err)
+		assert.True(t, eq, "want=%v\ngot=%v", want, got)
+	})
+
+	t.Run("DuplicateKey", func(t *testing.T) {
+		env := core.New(map[string]core.Any{"x": Keyword("k")})
+		m, err := NewMap(Symbol("x"), Int64(1), Keyword("k"), Int64(2))
+		assert.NoError(t, err)
+
+		got, err := (MapExpr{
+			Analyzer: &Analyzer{},
+			Map:      m,
+		}).Eval(env)
+		assert.EqualError(t, err, "duplicate key: :k")
+		assert.Nil(t, got)
+	})
+
+	t.Run("UnboundSymbolMember", func(t *testing.T) {
+		m, err := NewMap(Keyword("foo"), Symbol("bar"))
+		assert.NoError(t, err)
+
+		got, err := (MapExpr{
+			Analyzer: &Analyzer{},
+			Map:      m,
+		}).Eval(core.New(nil))
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestSetExpr_Eval(t *testing.T) {
+	t.Run("ConstMembers", func(t *testing.T) {
+		s := mustSet(t, Keyword("foo"), Int64(1))
+
+		got, err := (SetExpr{
+			Analyzer: &Analyzer{},
+			Set:      s,
+		}).Eval(core.New(nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, s, got)
+	})
+
+	t.Run("SymbolMembers", func(t *testing.T) {
+		env := core.New(map[string]core.Any{"foo": Keyword("foo")})
+
+		got, err := (SetExpr{
+			Analyzer: &Analyzer{},
+			Set:      mustSet(t, Symbol("foo"), Int64(1)),
+		}).Eval(env)
+		assert.NoError(t, err)
+
+		eq, err := core.Eq(mustSet(t, Keyword("foo"), Int64(1)), got)
+		assert.NoError(t, err)
+		assert.True(t, eq, "got=%v", got)
+	})
+
+	t.Run("DuplicateMember", func(t *testing.T) {
+		env := core.New(map[string]core.Any{"x": Int64(1)})
+
+		got, err := (SetExpr{
+			Analyzer: &Analyzer{},
+			Set:      mustSet(t, Symbol("x"), Int64(1)),
+		}).Eval(env)
+		assert.EqualError(t, err, "duplicate item: 1")
+		assert.Nil(t, got)
+	})
+
+	t.Run("UnboundSymbolMember", func(t *testing.T) {
+		got, err := (SetExpr{
+			Analyzer: &Analyzer{},
+			Set:      mustSet(t, Symbol("foo")),
+		}).Eval(core.New(nil))
+
+		assert.Error(t, err)
+		assert.Nil(t, got)
+	})
+}
+
+func TestCollectionExpr_Eval_Budget(t *testing.T) {
+	t.Parallel()
+
+	// members are unbound symbols. the length must be checked before any
+	// member is evaluated.
+	m, err := NewMap(Symbol("a"), Int64(1), Symbol("b"), Int64(2))
+	assert.NoError(t, err)
+
+	table := map[string]core.Expr{
+		"Vector": VectorExpr{Analyzer: &Analyzer{}, Vector: NewVector(Symbol("a"), Symbol("b"))},
+		"Map":    MapExpr{Analyzer: &Analyzer{}, Map: m},
+		"Set":    SetExpr{Analyzer: &Analyzer{}, Set: mustSet(t, Symbol("a"), Symbol("b"))},
+	}
+
+	for title, expr := range table {
+		expr := expr
+		t.Run(title, func(t *testing.T) {
+			ctx := core.WithMeter(context.Background(), core.NewMeter(core.Budget{MaxLength: 1}))
+
+			got, err := expr.Eval(core.ContextChild(core.New(nil), "<eval>", ctx))
+			assert.ErrorIs(t, err, core.ErrBudgetExceeded)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestLoopExpr_Eval(t *testing.T) {
+	t.Parallel()
+
+	const n = 100000
+	lessThanN := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		return Bool(args[0].(Int64) < n), nil
+	})
+	inc := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+		return args[0].(Int64) + 1, nil
+	})
+
+	runExprTests(t, []exprTest{
+		{
+			title: "NoRecur",
+			expr: func() (core.Expr, core.Env) {
+				return LoopExpr{
+					Names:  []string{"x"},
+					Values: []core.Expr{ConstExpr{Const: Int64(1)}},
+					Body:   ResolveExpr{Symbol: "x"},
+				}, core.New(nil)
+			},
+			want: Int64(1),
+		},
+		{
+			title: "Recur",
+			expr: func() (core.Expr, core.Env) {
+				i := ResolveExpr{Symbol: "i"}
+				return LoopExpr{
+					Names:  []string{"i"},
+					Values: []core.Expr{ConstExpr{Const: Int64(0)}},
+					Body: IfExpr{
+						Test: InvokeExpr{Target: ConstExpr{Const: lessThanN}, Args: []core.Expr{i}},
+						Then: RecurExpr{Args: []core.Expr{
+							InvokeExpr{Target: ConstExpr{Const: inc}, Args: []core.Expr{i}},
+						}},
+						Else: i,
+					},
+				}, core.New(nil)
+			},
+			want: Int64(n),
+		},
+		{
+			title: "RecurArityMismatch",
+			expr: func() (core.Expr, core.Env) {
+				return LoopExpr{
+					Names:  []string{"i"},
+					Values: []core.Expr{ConstExpr{Const: Int64(0)}},
+					Body:   RecurExpr{},
+				}, core.New(nil)
+			},
+			wantErr: core.ErrArity,
+		},
+		{
+			title: "BodyEvalFail",
+			expr: func() (core.Expr, core.Env) {
+				return LoopExpr{Body: fakeExpr{Err: errUnknown}}, core.New(nil)
+			},
+			wantErr: errUnknown,
+		},
+		{
+			title: "ContextCancelled",
+			expr: func() (core.Expr, core.Env) {
+				ctx, cancel := context.WithCancel(context.Background())
+				count := fakeInvokable(func(args ...core.Any) (core.Any, error) {
+					if args[0].(Int64) == 10 {
+						cancel()
+					}
+					return args[0].(Int64) + 1, nil
+				})
+
+				i := ResolveExpr{Symbol: "i"}
+				return LoopExpr{
+					Names:  []string{"i"},
+					Values: []core.Expr{ConstExpr{Const: Int64(0)}},
+					Body: RecurExpr{Args: []core.Expr{
+						InvokeExpr{Target: ConstExpr{Const: count}, Args: []core.Expr{i}},
+					}},
+				}, core.ContextChild(core.New(nil), "<eval>", ctx)
+			},
+			wantErr: context.Canceled,
+		},
+	})
+}
+
+func TestTryExpr_Eval(t *testing.T) {
+	t.Parallel()
+
+	catchAll := CatchExpr{
+		Matcher: ConstExpr{Const: Keyword("default")},
+		Name:    "e",
+		Body:    ResolveExpr{Symbol: "e"},
+	}
+
+	runExprTests(t, []exprTest{
+		{
+			title: "NoError",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    ConstExpr{Const: 10},
+					Catches: []CatchExpr{catchAll},
+				}, core.New(nil)
+			},
+			want: 10,
+		},
+		{
+			title: "NoMatchingCatch",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body: fakeExpr{Err: errUnknown},
+					Catches: []CatchExpr{
+						{Matcher: ConstExpr{Const: core.ErrArity}, Name: "e", Body: ConstExpr{Const: 1}},
+					},
+				}, core.New(nil)
+			},
+			wantErr: errUnknown,
+		},
+		{
+			title: "CatchByCause",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body: fakeExpr{Err: core.Error{Cause: core.ErrArity}},
+					Catches: []CatchExpr{
+						{Matcher: ConstExpr{Const: core.ErrNotFound}, Name: "e", Body: ConstExpr{Const: 1}},
+						{Matcher: ConstExpr{Const: core.ErrArity}, Name: "e", Body: ConstExpr{Const: 2}},
+						catchAll,
+					},
+				}, core.New(nil)
+			},
+			want: 2,
+		},
+		{
+			title: "CatchByType",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body: ThrowExpr{Value: ConstExpr{Const: Int64(10)}},
+					Catches: []CatchExpr{
+						{Matcher: ConstExpr{Const: reflect.TypeOf(String(""))}, Name: "e", Body: ConstExpr{Const: 1}},
+						{Matcher: ConstExpr{Const: reflect.TypeOf(Int64(0))}, Name: "e", Body: ResolveExpr{Symbol: "e"}},
+					},
+				}, core.New(nil)
+			},
+			want: Int64(10),
+		},
+		{
+			title: "CatchDefault",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    fakeExpr{Err: errUnknown},
+					Catches: []CatchExpr{catchAll},
+				}, core.New(nil)
+			},
+			want: errUnknown,
+		},
+		{
+			title: "CatchWithStackTrace",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body: InvokeExpr{
+						Name: "fail",
+						Target: ConstExpr{Const: fakeInvokable(func(args ...core.Any) (core.Any, error) {
+							return nil, errUnknown
+						})},
+					},
+					Catches: []CatchExpr{catchAll},
+				}, core.New(nil)
+			},
+			want: errUnknown,
+		},
+		{
+			title: "Finally",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    fakeExpr{Err: errUnknown},
+					Catches: []CatchExpr{catchAll},
+					Finally: DefExpr{Name: "finally", Value: ConstExpr{Const: Bool(true)}},
+				}, core.New(nil)
+			},
+			want: errUnknown,
+			assert: func(t *testing.T, _ core.Any, _ error, env core.Env) {
+				v, err := env.Resolve("finally")
+				assert.NoError(t, err)
+				assert.Equal(t, Bool(true), v)
+			},
+		},
+		{
+			title: "FinallyErr",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    ConstExpr{Const: 10},
+					Finally: fakeExpr{Err: errUnknown},
+				}, core.New(nil)
+			},
+			wantErr: errUnknown,
+		},
+		{
+			title: "BudgetExceededNotCaught",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    fakeExpr{Err: core.Error{Cause: core.ErrBudgetExceeded}},
+					Catches: []CatchExpr{{Matcher: ConstExpr{Const: core.ErrBudgetExceeded}, Name: "e", Body: ConstExpr{Const: 1}}, catchAll},
+					Finally: DefExpr{Name: "finally", Value: ConstExpr{Const: Bool(true)}},
+				}, core.New(nil)
+			},
+			wantErr: core.ErrBudgetExceeded,
+			assert: func(t *testing.T, _ core.Any, _ error, env core.Env) {
+				v, err := env.Resolve("finally")
+				assert.NoError(t, err)
+				assert.Equal(t, Bool(true), v)
+			},
+		},
+		{
+			title: "ContextCancelledNotCaught",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    fakeExpr{Err: core.Error{Cause: context.Canceled}},
+					Catches: []CatchExpr{catchAll},
+				}, core.New(nil)
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			title: "DeadlineExceededNotCaught",
+			expr: func() (core.Expr, core.Env) {
+				return TryExpr{
+					Body:    fakeExpr{Err: core.Error{Cause: context.DeadlineExceeded}},
+					Catches: []CatchExpr{catchAll},
+				}, core.New(nil)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+	})
+
+	t.Run("InvalidMatcher", func(t *testing.T) {
+		got, err := TryExpr{
+			Body: fakeExpr{Err: errUnknown},
+			Catches: []CatchExpr{
+				{Matcher: ConstExpr{Const: Int64(1)}, Name: "e", Body: ConstExpr{Const: 1}},
+			},
+		}.Eval(core.New(nil))
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, errUnknown))
+		assert.Nil(t, got)
+	})
+}
+
+func TestThrowExpr_Eval(t *testing.T) {
+	t.Parallel()
+	runExprTests(t, []exprTest{
+		{
+			title: "Error",
+			expr: func() (core.Expr, core.Env) {
+				return ThrowExpr{Value: ConstExpr{Const: errUnknown}}, core.New(nil)
+			},
+			wantErr: errUnknown,
+		},
+		{
+			title: "Value",
+			expr: func() (core.Expr, core.Env) {
+				return ThrowExpr{Value: ConstExpr{Const: Keyword("oops")}}, core.New(nil)
+			},
+			wantErr: core.ErrThrown,
+			assert: func(t *testing.T, _ core.Any, err error, _ core.Env) {
+				var e core.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, Keyword("oops"), e.Value)
+			},
+		},
+		{
+			title: "ValueEvalErr",
+			expr: func() (core.Expr, core.Env) {
+				return ThrowExpr{Value: fakeExpr{Err: errUnknown}}, core.New(nil)
+			},
+			wantErr: errUnknown,
+		},
+	})
+}
+
 func runExprTests(t *testing.T, table []exprTest) {
 	for _, tt := range table {
 		t.Run(tt.title, func(t *testing.T) {
@@ -353,3 +918,19 @@ func (f fakeExpr) Eval(_ core.Env) (core.Any, error) { return f.Res, f.Err }
 type fakeInvokable func(args ...core.Any) (core.Any, error)
 
 func (f fakeInvokable) Invoke(args ...core.Any) (core.Any, error) { return f(args...) }
+
+type panicExpr struct{}
+
+func (panicExpr) Eval(_ core.Env) (core.Any, error) { panic("failed") }
+
+type fakeCtxKey struct{}
+
+type fakeContextInvokable func(ctx context.Context, args ...core.Any) (core.Any, error)
+
+func (f fakeContextInvokable) Invoke(args ...core.Any) (core.Any, error) {
+	return f(context.Background(), args...)
+}
+
+func (f fakeContextInvokable) InvokeContext(ctx context.Context, args ...core.Any) (core.Any, error) {
+	return f(ctx, args...)
+}
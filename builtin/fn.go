@@ -1,6 +1,7 @@
 package builtin
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 var (
 	_ core.Invokable        = (*Fn)(nil)
+	_ core.ContextInvokable = (*Fn)(nil)
 	_ core.EqualityProvider = (*Fn)(nil)
 )
 
@@ -24,21 +26,77 @@ type Fn struct {
 }
 
 // Invoke selects and executes a func defined in the Fn and returns
-// the result of execution.
+// the result of execution. If the body evaluates a recur, the body is
+// executed again with the params bound to the recur values. Calls to
+// other Fn in tail position of the body are executed here as well so
+// that the stack does not grow. Errors from such calls have the frame
+// of the last tail call pushed to their stack trace.
 func (fn Fn) Invoke(args ...core.Any) (core.Any, error) {
+	return fn.InvokeContext(context.Background(), args...)
+}
+
+// InvokeContext is same as Invoke except that the body is evaluated with
+// the context (See core.ContextOf). The context is checked before every
+// recur and tail call and the invocation fails with the context error if
+// it is done.
+func (fn Fn) InvokeContext(ctx context.Context, args ...core.Any) (core.Any, error) {
+	var frame *core.Frame
+	for {
+		res, err := fn.invoke(ctx, args)
+		if err != nil {
+			if frame != nil {
+				err = core.WithFrame(err, *frame)
+			}
+			return nil, err
+		}
+
+		tc, ok := res.(tailCall)
+		if !ok {
+			return res, nil
+		}
+		fn, args, frame = tc.fn, tc.args, &tc.frame
+
+		if err := contextErr(ctx); err != nil {
+			return nil, core.WithFrame(err, *frame)
+		}
+	}
+}
+
+func (fn Fn) invoke(ctx context.Context, args []core.Any) (core.Any, error) {
 	f, err := fn.selectFunc(args)
 	if err != nil {
 		return nil, err
 	}
 
-	env := fn.Env.Child(fn.Name, nil)
-	for i, p := range f.Params {
-		if err := env.Bind(p, args[i]); err != nil {
+	env := core.ContextChild(fn.Env, fn.Name, ctx)
+	if err := f.bindArgs(env, args); err != nil {
+		return nil, err
+	}
+
+	return fn.eval(ctx, f, env)
+}
+
+func (fn Fn) eval(ctx context.Context, f Func, env core.Env) (core.Any, error) {
+	for {
+		res, err := f.Body.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+
+		r, ok := res.(recur)
+		if !ok {
+			return res, nil
+		}
+
+		if err := step(ctx); err != nil {
 			return nil, err
 		}
-	}
 
-	return f.Body.Eval(env)
+		env = core.ContextChild(fn.Env, fn.Name, ctx)
+		if err := r.bind(env, f.Params); err != nil {
+			return nil, err
+		}
+	}
 }
 
 // Equals returns true if 'v' is also a MultiFn and all methods are
@@ -83,23 +141,60 @@ func (fn Fn) String() string {
 }
 
 func (fn Fn) selectFunc(args []core.Any) (Func, error) {
-	for _, f := range fn.Funcs {
+	var variadic *Func
+	for i, f := range fn.Funcs {
 		if f.matchArity(args) {
-			return f, nil
+			if !f.Variadic {
+				return f, nil
+			}
+			variadic = &fn.Funcs[i]
 		}
 	}
 
+	if variadic != nil {
+		return *variadic, nil
+	}
+
 	return Func{}, fmt.Errorf(
 		"%w (%d) to '%s'", core.ErrArity, len(args), fn.Name)
 }
 
-// Func represents a method of specific arity in Fn.
+// tailCall is the result of evaluating an InvokeExpr in tail position
+// with an Fn target.
+type tailCall struct {
+	fn    Fn
+	args  []core.Any
+	frame core.Frame
+}
+
+// Func represents a method of specific arity in Fn. If Variadic is true,
+// the last param is bound to a list of the remaining arguments (or nil if
+// there are none).
 type Func struct {
 	Body     core.Expr
 	Params   []string
 	Variadic bool
 }
 
+func (f Func) bindArgs(env core.Env, args []core.Any) error {
+	for i, p := range f.Params {
+		var val core.Any
+		if f.Variadic && i == len(f.Params)-1 {
+			val = Nil{}
+			if len(args) > i {
+				val = NewList(args[i:]...)
+			}
+		} else {
+			val = args[i]
+		}
+
+		if err := env.Bind(p, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (f Func) matchArity(args []core.Any) bool {
 	argc := len(args)
 	if f.Variadic {
@@ -128,3 +223,73 @@ func (f *Func) compare(other Func) (bool, error) {
 
 	return bodyEq, nil
 }
+
+// invokableOf returns fn as an Invokable. Go funcs are adapted reflectively:
+// the arguments are converted to the parameter types (numbers only if they
+// fit), the context of the call is passed if the first parameter is a
+// context.Context, and a trailing error result is returned as the error.
+func invokableOf(fn core.Any) (core.Invokable, error) {
+	if inv, ok := fn.(core.Invokable); ok {
+		return inv, nil
+	}
+
+	rv := reflect.ValueOf(fn)
+	if rv.Kind() != reflect.Func || rv.IsNil() {
+		return nil, fmt.Errorf("%w: value of type '%s'", core.ErrNotInvokable, reflect.TypeOf(fn))
+	}
+	return goFunc{rv: rv}, nil
+}
+
+var (
+	ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// goFunc adapts a Go func to Invokable.
+type goFunc struct{ rv reflect.Value }
+
+func (gf goFunc) Invoke(args ...core.Any) (core.Any, error) {
+	return gf.InvokeContext(context.Background(), args...)
+}
+
+func (gf goFunc) InvokeContext(ctx context.Context, args ...core.Any) (core.Any, error) {
+	rt := gf.rv.Type()
+
+	var in []reflect.Value
+	if rt.NumIn() > 0 && rt.In(0) == ctxType {
+		in = append(in, reflect.ValueOf(&ctx).Elem())
+	}
+
+	params := rt.NumIn() - len(in)
+	if rt.IsVariadic() && len(args) < params-1 || !rt.IsVariadic() && len(args) != params {
+		return nil, fmt.Errorf("%w: func '%s' called with %d argument(s)", core.ErrArity, rt, len(args))
+	}
+
+	for _, arg := range args {
+		var t reflect.Type
+		if last := rt.NumIn() - 1; rt.IsVariadic() && len(in) >= last {
+			t = rt.In(last).Elem()
+		} else {
+			t = rt.In(len(in))
+		}
+
+		v, err := toElem(arg, t)
+		if err != nil {
+			return nil, err
+		}
+		in = append(in, v)
+	}
+
+	out := gf.rv.Call(in)
+	if n := len(out); n > 0 && rt.Out(n-1) == errType {
+		if !out[n-1].IsNil() {
+			return nil, out[n-1].Interface().(error)
+		}
+		out = out[:n-1]
+	}
+
+	if len(out) == 0 {
+		return Nil{}, nil
+	}
+	return fromElem(out[0]), nil
+}
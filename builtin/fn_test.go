@@ -57,3 +57,97 @@ func TestFn_Invoke(t *testing.T) {
 		})
 	}
 }
+
+func TestFn_Invoke_Variadic(t *testing.T) {
+	t.Parallel()
+
+	specimen := Fn{
+		Env:  core.New(nil),
+		Name: "foo",
+		Funcs: []Func{
+			{
+				Variadic: true,
+				Params:   []string{"arg0", "rest"},
+				Body:     &ResolveExpr{"rest"},
+			},
+			{
+				Params: []string{"arg0"},
+				Body:   ConstExpr{Const: Keyword("fixed")},
+			},
+		},
+	}
+
+	table := []struct {
+		title   string
+		args    []core.Any
+		want    core.Any
+		wantErr bool
+	}{
+		{
+			title:   "InvalidArity",
+			args:    []core.Any{},
+			wantErr: true,
+		},
+		{
+			title: "PreferFixedArity",
+			args:  []core.Any{Int64(1)},
+			want:  Keyword("fixed"),
+		},
+		{
+			title: "RestArgs",
+			args:  []core.Any{Int64(1), Int64(2), Int64(3)},
+			want:  NewList(Int64(2), Int64(3)),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := specimen.Invoke(tt.args...)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFn_Invoke_TailCall(t *testing.T) {
+	t.Parallel()
+
+	const depth = 1000000
+
+	// countdown calls itself in tail position until n reaches 0.
+	countdown := Fn{Env: core.New(nil), Name: "countdown"}
+	countdown.Funcs = []Func{
+		{
+			Params: []string{"n"},
+			Body: IfExpr{
+				Test: InvokeExpr{
+					Target: ConstExpr{Const: fakeInvokable(func(args ...core.Any) (core.Any, error) {
+						return Bool(args[0].(Int64) > 0), nil
+					})},
+					Args: []core.Expr{ResolveExpr{Symbol: "n"}},
+				},
+				Then: InvokeExpr{
+					Target: ResolveExpr{Symbol: "countdown"},
+					Args: []core.Expr{InvokeExpr{
+						Target: ConstExpr{Const: fakeInvokable(func(args ...core.Any) (core.Any, error) {
+							return args[0].(Int64) - 1, nil
+						})},
+						Args: []core.Expr{ResolveExpr{Symbol: "n"}},
+					}},
+					Tail: true,
+				},
+				Else: ConstExpr{Const: Keyword("done")},
+			},
+		},
+	}
+	assert.NoError(t, countdown.Env.Bind("countdown", countdown))
+
+	got, err := countdown.Invoke(Int64(depth))
+	assert.NoError(t, err)
+	assert.Equal(t, Keyword("done"), got)
+}
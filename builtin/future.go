@@ -0,0 +1,150 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	_ core.Ref = (*Promise)(nil)
+	_ core.Ref = (*Future)(nil)
+)
+
+// Promise is a reference to a value that is delivered once, possibly from
+// another goroutine. Deref blocks until the value is delivered.
+type Promise struct {
+	once sync.Once
+	done chan struct{}
+	val  core.Any
+	err  error
+}
+
+// NewPromise returns a new promise that is yet to be delivered.
+func NewPromise() *Promise {
+	return &Promise{done: make(chan struct{})}
+}
+
+// Deliver sets the value of the promise and unblocks all the Deref calls.
+// Returns false if the promise was already delivered.
+func (p *Promise) Deliver(v core.Any) bool { return p.deliver(v, nil) }
+
+// Realized returns true if the promise has been delivered.
+func (p *Promise) Realized() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Deref blocks until the promise is delivered or the context is done and
+// returns the value delivered.
+func (p *Promise) Deref(ctx context.Context) (core.Any, error) {
+	select {
+	case <-p.done:
+		return p.val, p.err
+	case <-ctx.Done():
+		return nil, contextErr(ctx)
+	}
+}
+
+// SExpr returns a string representation of the promise.
+func (p *Promise) SExpr() (string, error) { return p.sexpr("promise") }
+
+func (p *Promise) deliver(v core.Any, err error) (delivered bool) {
+	p.once.Do(func() {
+		p.val, p.err = v, err
+		close(p.done)
+		delivered = true
+	})
+	return delivered
+}
+
+func (p *Promise) sexpr(kind string) (string, error) {
+	if !p.Realized() {
+		return fmt.Sprintf("#%s[pending]", kind), nil
+	} else if p.err != nil {
+		return fmt.Sprintf("#%s[failed]", kind), nil
+	}
+
+	s, err := toSExpr(p.val)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("#%s[%s]", kind, s), nil
+}
+
+// Future is a reference to the result of a computation running in another
+// goroutine (e.g., the go form). The error returned by the computation is
+// returned by Deref instead of the value.
+type Future struct{ p *Promise }
+
+// NewFuture runs fn in a new goroutine and returns a future of its result.
+// A panic in fn fails the future with an error.
+func NewFuture(fn func() (core.Any, error)) *Future {
+	f := &Future{p: NewPromise()}
+
+	go func() {
+		defer func() {
+			if v := recover(); v != nil {
+				f.p.deliver(nil, fmt.Errorf("panic: %v", v))
+			}
+		}()
+		f.p.deliver(fn())
+	}()
+
+	return f
+}
+
+// Realized returns true if the computation has completed.
+func (f *Future) Realized() bool { return f.p.Realized() }
+
+// Deref blocks until the computation completes or the context is done and
+// returns the result of the computation.
+func (f *Future) Deref(ctx context.Context) (core.Any, error) { return f.p.Deref(ctx) }
+
+// SExpr returns a string representation of the future.
+func (f *Future) SExpr() (string, error) { return f.p.sexpr("future") }
+
+// Deref returns the value referred to by the ref. With (deref ref timeout-ms
+// timeout-val) form, it waits at most timeout-ms milliseconds and returns
+// timeout-val if the value is not available by then.
+func Deref(ctx context.Context, ref core.Ref, timeout ...core.Any) (core.Any, error) {
+	if len(timeout) == 0 {
+		return ref.Deref(ctx)
+	} else if len(timeout) != 2 {
+		return nil, core.Error{
+			Cause:   core.ErrArity,
+			Message: fmt.Sprintf("deref requires 1 or 3 arguments, got %d", len(timeout)+1),
+		}
+	}
+
+	ms, ok := timeout[0].(Int64)
+	if !ok {
+		return nil, fmt.Errorf("deref: timeout must be Int64, not '%s'", reflect.TypeOf(timeout[0]))
+	}
+
+	tctx, cancel := context.WithTimeout(ctx, time.Duration(ms)*time.Millisecond)
+	defer cancel()
+
+	v, err := ref.Deref(tctx)
+	if err != nil && ctx.Err() == nil && tctx.Err() != nil {
+		return timeout[1], nil
+	}
+	return v, err
+}
+
+// Deliver delivers the value to the promise. Returns the promise if it was
+// delivered and nil if the promise was already delivered.
+func Deliver(p *Promise, v core.Any) core.Any {
+	if p.Deliver(v) {
+		return p
+	}
+	return Nil{}
+}
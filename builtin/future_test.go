@@ -0,0 +1,85 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromise(t *testing.T) {
+	t.Parallel()
+
+	p := NewPromise()
+	assert.False(t, p.Realized())
+	testSExpr(t, p, "#promise[pending]")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := p.Deref(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	assert.True(t, p.Deliver(Int64(1)))
+	assert.False(t, p.Deliver(Int64(2)), "promise must be delivered only once")
+	assert.True(t, p.Realized())
+	testSExpr(t, p, "#promise[1]")
+
+	v, err := p.Deref(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(1), v)
+
+	assert.Equal(t, Nil{}, Deliver(p, Int64(3)))
+}
+
+func TestDeref(t *testing.T) {
+	t.Parallel()
+
+	pending, delivered := NewPromise(), NewPromise()
+	delivered.Deliver(Keyword("done"))
+
+	table := []struct {
+		title   string
+		ref     core.Ref
+		timeout []core.Any
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "Delivered",
+			ref:   delivered,
+			want:  Keyword("done"),
+		},
+		{
+			title:   "DeliveredWithTimeout",
+			ref:     delivered,
+			timeout: []core.Any{Int64(10), Keyword("timeout")},
+			want:    Keyword("done"),
+		},
+		{
+			title:   "TimedOut",
+			ref:     pending,
+			timeout: []core.Any{Int64(10), Keyword("timeout")},
+			want:    Keyword("timeout"),
+		},
+		{
+			title:   "WrongArity",
+			ref:     pending,
+			timeout: []core.Any{Int64(10)},
+			wantErr: core.ErrArity,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := Deref(context.Background(), tt.ref, tt.timeout...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
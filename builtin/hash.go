@@ -0,0 +1,114 @@
+package builtin
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/spy16/slurp/core"
+)
+
+// hashOf returns a 32-bit hash of the value that is consistent with the
+// key equality used by hashed collections (i.e., values for which keyEq
+// returns true have the same hash).
+func hashOf(v core.Any) (uint32, error) {
+	switch val := v.(type) {
+	case nil, Nil:
+		return 0, nil
+
+	case Bool:
+		if val {
+			return 1231, nil
+		}
+		return 1237, nil
+
+	case Int64, Float64, BigInt, BigDecimal, Ratio:
+		return hashNumber(val), nil
+
+	case Char:
+		return hashString("c", string(val)), nil
+
+	case String:
+		return hashString("s", string(val)), nil
+
+	case Symbol:
+		return hashString("y", string(val)), nil
+
+	case MetaSymbol:
+		return hashString("y", string(val.Symbol)), nil
+
+	case Keyword:
+		return hashString("k", string(val)), nil
+
+	case core.Map:
+		seq, err := val.Seq()
+		if err != nil {
+			return 0, err
+		}
+		return hashUnordered(seq)
+
+	case core.Set:
+		seq, err := val.Seq()
+		if err != nil {
+			return 0, err
+		}
+		return hashUnordered(seq)
+
+	case core.Seq:
+		return hashOrdered(val)
+
+	case core.Seqable:
+		seq, err := val.Seq()
+		if err != nil {
+			return 0, err
+		}
+		return hashOrdered(seq)
+
+	default:
+		return hashString(fmt.Sprintf("%T", v), fmt.Sprintf("%v", v)), nil
+	}
+}
+
+// keyEq returns true if the two values should be considered the same key
+// in a hashed collection.
+func keyEq(a, b core.Any) (bool, error) {
+	eq, err := core.Eq(a, b)
+	if err != nil || eq {
+		return eq, err
+	}
+
+	// values that do not define equality (e.g., native Go values) are
+	// compared structurally.
+	return reflect.DeepEqual(a, b), nil
+}
+
+func hashOrdered(seq core.Seq) (uint32, error) {
+	h := uint32(1)
+	err := core.ForEach(seq, func(item core.Any) (bool, error) {
+		ih, err := hashOf(item)
+		h = 31*h + ih
+		return false, err
+	})
+	return h, err
+}
+
+func hashUnordered(seq core.Seq) (uint32, error) {
+	var h uint32
+	err := core.ForEach(seq, func(item core.Any) (bool, error) {
+		ih, err := hashOf(item)
+		h += ih
+		return false, err
+	})
+	return h, err
+}
+
+func hashString(tag, s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(tag))
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+func hashUint64(u uint64) uint32 {
+	return uint32(u ^ (u >> 32))
+}
@@ -0,0 +1,159 @@
+package builtin
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	_ core.Seq       = (*LazySeq)(nil)
+	_ core.Seqable   = (*LazySeq)(nil)
+	_ core.Uncounted = (*LazySeq)(nil)
+)
+
+// LazySeq is a sequence whose items are computed only when the sequence is
+// walked. The function of the sequence is called at most once (the first
+// time the sequence is accessed) and the result is cached. The function
+// returns nil (empty), a Seq or a Seqable value. Since the result may be
+// another LazySeq, an unbounded sequence can be built without realizing it.
+// The function must not access the LazySeq being realized.
+type LazySeq struct {
+	mu  sync.Mutex
+	fn  func() (core.Any, error)
+	seq core.Seq
+	err error
+}
+
+// NewLazySeq returns a LazySeq that calls fn to realize the sequence.
+func NewLazySeq(fn func() (core.Any, error)) *LazySeq {
+	return &LazySeq{fn: fn}
+}
+
+// Realized returns true if the sequence function has been called.
+func (ls *LazySeq) Realized() bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	return ls.fn == nil
+}
+
+// Seq realizes the sequence and returns the result. Returns nil if the
+// sequence is empty.
+func (ls *LazySeq) Seq() (core.Seq, error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.fn == nil {
+		return ls.seq, ls.err
+	}
+
+	// lazy sequences returned by the function are unwrapped in a loop so
+	// that deeply nested sequences do not grow the stack. each unwrapped
+	// sequence is kept locked until the result is known and then set to
+	// the same result.
+	pending := []*LazySeq{ls}
+	defer func() {
+		for _, p := range pending[1:] {
+			p.mu.Unlock()
+		}
+	}()
+	fn := ls.fn
+
+	var seq core.Seq
+	var err error
+	for {
+		v, fnErr := fn()
+		if fnErr != nil {
+			err = fnErr
+			break
+		}
+
+		inner, ok := v.(*LazySeq)
+		if !ok {
+			seq, err = toSeq(v)
+			break
+		}
+
+		inner.mu.Lock()
+		if inner.fn == nil {
+			seq, err = inner.seq, inner.err
+			inner.mu.Unlock()
+			break
+		}
+		pending = append(pending, inner)
+		fn = inner.fn
+	}
+
+	for _, p := range pending {
+		p.seq, p.err = seq, err
+		p.fn = nil // release the closure.
+	}
+	return seq, err
+}
+
+// First realizes the sequence and returns the first item.
+func (ls *LazySeq) First() (core.Any, error) {
+	seq, err := ls.Seq()
+	if err != nil || seq == nil {
+		return nil, err
+	}
+	return seq.First()
+}
+
+// Next realizes the sequence and returns the rest of it. The rest is not
+// realized.
+func (ls *LazySeq) Next() (core.Seq, error) {
+	seq, err := ls.Seq()
+	if err != nil || seq == nil {
+		return nil, err
+	}
+	return seq.Next()
+}
+
+// Count realizes the whole sequence to count the items. Count never
+// returns for an infinite sequence.
+func (ls *LazySeq) Count() (int, error) {
+	cnt := 0
+	err := core.ForEach(ls, func(_ core.Any) (bool, error) {
+		cnt++
+		return false, nil
+	})
+	return cnt, err
+}
+
+// CountUnknown always returns true.
+func (ls *LazySeq) CountUnknown() bool { return true }
+
+// Conj returns a new sequence with the items added at the head of the lazy
+// sequence. The lazy sequence is not realized.
+func (ls *LazySeq) Conj(items ...core.Any) (res core.Seq, err error) {
+	res = ls
+	for _, item := range items {
+		if res, err = Cons(item, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// SExpr realizes the whole sequence and returns a valid s-expression for it.
+func (ls *LazySeq) SExpr() (string, error) { return core.SeqString(ls, "(", ")", " ") }
+
+// toSeq returns the sequence of the value returned by the function of a
+// lazy sequence.
+func toSeq(v core.Any) (core.Seq, error) {
+	switch val := v.(type) {
+	case nil, Nil:
+		return nil, nil
+
+	case core.Seq:
+		return val, nil
+
+	case core.Seqable:
+		return val.Seq()
+	}
+
+	return nil, fmt.Errorf("lazy-seq: value of type '%s' is not a sequence", reflect.TypeOf(v))
+}
@@ -0,0 +1,145 @@
+package builtin
+
+import (
+	"errors"
+	"runtime/debug"
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazySeq(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	var naturals func(n int64) *LazySeq
+	naturals = func(n int64) *LazySeq {
+		return NewLazySeq(func() (core.Any, error) {
+			calls++
+			return Cons(Int64(n), naturals(n+1))
+		})
+	}
+
+	seq := naturals(0)
+	assert.False(t, seq.Realized())
+	assert.True(t, seq.CountUnknown())
+	assert.Equal(t, 0, calls)
+
+	var got []core.Any
+	err := core.ForEach(seq, func(item core.Any) (bool, error) {
+		got = append(got, item)
+		return len(got) == 3, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []core.Any{Int64(0), Int64(1), Int64(2)}, got)
+	assert.Equal(t, 3, calls)
+	assert.True(t, seq.Realized())
+
+	v, err := seq.First()
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(0), v)
+	assert.Equal(t, 3, calls, "realized items must be cached")
+
+	conj, err := seq.Conj(Int64(-1))
+	assert.NoError(t, err)
+	assert.False(t, core.IsCounted(conj))
+	v, err = conj.First()
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(-1), v)
+	assert.Equal(t, 3, calls)
+}
+
+func TestLazySeq_Values(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("failed")
+
+	table := []struct {
+		title   string
+		val     core.Any
+		err     error
+		want    string
+		wantCnt int
+		wantErr error
+	}{
+		{title: "Nil", val: nil, want: "()"},
+		{title: "NilValue", val: Nil{}, want: "()"},
+		{title: "EmptyList", val: NewList(), want: "()"},
+		{title: "List", val: NewList(Int64(1), Int64(2)), want: "(1 2)", wantCnt: 2},
+		{title: "Seqable", val: NewVector(Int64(1)), want: "(1)", wantCnt: 1},
+		{title: "Nested", val: NewLazySeq(func() (core.Any, error) { return NewList(Int64(1)), nil }), want: "(1)", wantCnt: 1},
+		{title: "NotSeq", val: Int64(1), wantErr: errors.New("lazy-seq: value of type 'builtin.Int64' is not a sequence")},
+		{title: "Error", err: errFailed, wantErr: errFailed},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			seq := NewLazySeq(func() (core.Any, error) { return tt.val, tt.err })
+
+			cnt, err := seq.Count()
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				if !errors.Is(err, tt.wantErr) {
+					assert.EqualError(t, err, tt.wantErr.Error())
+				}
+
+				_, err = seq.First()
+				assert.Error(t, err, "error must be cached")
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCnt, cnt)
+			testSExpr(t, seq, tt.want)
+		})
+	}
+}
+
+func TestLazySeq_DeeplyNested(t *testing.T) {
+	// not parallel: the stack limit applies to the whole process and must
+	// be small enough that realizing recursively would overflow it.
+	defer debug.SetMaxStack(debug.SetMaxStack(1 << 20))
+
+	seqs := []*LazySeq{}
+	var seq core.Any = NewList(Int64(1))
+	for i := 0; i < 100000; i++ {
+		inner := seq
+		ls := NewLazySeq(func() (core.Any, error) { return inner, nil })
+		seqs = append(seqs, ls)
+		seq = ls
+	}
+
+	v, err := seqs[len(seqs)-1].First()
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(1), v)
+
+	for _, ls := range []*LazySeq{seqs[0], seqs[len(seqs)/2]} {
+		assert.True(t, ls.Realized(), "nested sequences must be realized")
+		v, err := ls.First()
+		assert.NoError(t, err)
+		assert.Equal(t, Int64(1), v)
+	}
+}
+
+func TestLinkedList_Uncounted(t *testing.T) {
+	t.Parallel()
+
+	realized := false
+	lazy := NewLazySeq(func() (core.Any, error) {
+		realized = true
+		return NewList(Int64(2), Int64(3)), nil
+	})
+
+	seq, err := Cons(Int64(1), lazy)
+	assert.NoError(t, err)
+	assert.False(t, realized, "Cons must not realize the rest")
+	assert.False(t, core.IsCounted(seq))
+
+	cnt, err := seq.Count()
+	assert.NoError(t, err)
+	assert.Equal(t, 3, cnt)
+	assert.True(t, realized)
+
+	assert.True(t, core.IsCounted(NewList(Int64(1))))
+}
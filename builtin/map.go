@@ -0,0 +1,492 @@
+package builtin
+
+import (
+	"fmt"
+	mbits "math/bits"
+	"strings"
+
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	_ core.Map              = (*PersistentMap)(nil)
+	_ core.EqualityProvider = (*PersistentMap)(nil)
+	_ core.Meta             = (*PersistentMap)(nil)
+)
+
+const (
+	mapBits  = 5 // number of hash bits consumed per level of the trie.
+	mapWidth = 1 << mapBits
+	mapMask  = mapWidth - 1
+)
+
+// EmptyMap is the zero-value PersistentMap.
+var EmptyMap = PersistentMap{}
+
+// PersistentMap is an immutable core.Map implementation based on a hash
+// array mapped trie (HAMT) providing near-constant time lookup, insertion
+// and deletion.
+type PersistentMap struct {
+	cnt  int
+	root hamtNode
+	meta core.Map
+}
+
+// NewMap builds a PersistentMap from the given key-value pairs. Returns
+// error if the number of items is odd.
+func NewMap(kvs ...core.Any) (PersistentMap, error) {
+	if len(kvs)%2 != 0 {
+		return PersistentMap{}, fmt.Errorf(
+			"expecting even number of items, got %d", len(kvs))
+	}
+
+	m := EmptyMap
+	for i := 0; i < len(kvs); i += 2 {
+		var err error
+		if m, err = m.assoc(kvs[i], kvs[i+1]); err != nil {
+			return PersistentMap{}, err
+		}
+	}
+	return m, nil
+}
+
+// Count returns the number of entries in the Map.
+func (m PersistentMap) Count() (int, error) { return m.cnt, nil }
+
+// Meta returns the metadata of the map.
+func (m PersistentMap) Meta() core.Map { return m.meta }
+
+// WithMeta returns the map with the metadata replaced.
+func (m PersistentMap) WithMeta(meta core.Map) core.Any {
+	m.meta = meta
+	return m
+}
+
+// Assoc returns a new Map with the key associated with val.
+func (m PersistentMap) Assoc(key, val core.Any) (core.Map, error) {
+	res, err := m.assoc(key, val)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (m PersistentMap) assoc(key, val core.Any) (PersistentMap, error) {
+	h, err := hashOf(key)
+	if err != nil {
+		return PersistentMap{}, err
+	}
+
+	root := m.root
+	if root == nil {
+		root = &bitmapNode{}
+	}
+
+	newRoot, added, err := root.assoc(0, h, key, val)
+	if err != nil {
+		return PersistentMap{}, err
+	}
+
+	res := PersistentMap{cnt: m.cnt, root: newRoot, meta: m.meta}
+	if added {
+		res.cnt++
+	}
+	return res, nil
+}
+
+// Dissoc returns a new Map without the association for the key.
+func (m PersistentMap) Dissoc(key core.Any) (core.Map, error) {
+	if m.root == nil {
+		return m, nil
+	}
+
+	h, err := hashOf(key)
+	if err != nil {
+		return nil, err
+	}
+
+	newRoot, removed, err := m.root.dissoc(0, h, key)
+	if err != nil {
+		return nil, err
+	} else if !removed {
+		return m, nil
+	}
+
+	return PersistentMap{cnt: m.cnt - 1, root: newRoot, meta: m.meta}, nil
+}
+
+// EntryAt returns the value associated with the key. Returns ErrNotFound
+// if the key is not present.
+func (m PersistentMap) EntryAt(key core.Any) (core.Any, error) {
+	v, found, err := m.find(key)
+	if err != nil {
+		return nil, err
+	} else if !found {
+		return nil, fmt.Errorf("%w: key %v", core.ErrNotFound, key)
+	}
+	return v, nil
+}
+
+// HasKey returns true if the Map has an association for the key.
+func (m PersistentMap) HasKey(key core.Any) (bool, error) {
+	_, found, err := m.find(key)
+	return found, err
+}
+
+func (m PersistentMap) find(key core.Any) (core.Any, bool, error) {
+	if m.root == nil {
+		return nil, false, nil
+	}
+
+	h, err := hashOf(key)
+	if err != nil {
+		return nil, false, err
+	}
+	return m.root.find(0, h, key)
+}
+
+// Seq returns a sequence of [key value] entry vectors. Order of the
+// entries is not defined.
+func (m PersistentMap) Seq() (core.Seq, error) {
+	if m.cnt == 0 {
+		return NewList(), nil
+	}
+
+	entries := make([]core.Any, 0, m.cnt)
+	err := m.forEach(func(k, v core.Any) (bool, error) {
+		entries = append(entries, NewVector(k, v))
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(entries...), nil
+}
+
+// Equals returns true if other is also a Map with the same set of entries.
+func (m PersistentMap) Equals(other core.Any) (bool, error) {
+	om, ok := other.(core.Map)
+	if !ok {
+		return false, nil
+	}
+
+	cnt, err := om.Count()
+	if err != nil || cnt != m.cnt {
+		return false, err
+	}
+
+	eq := true
+	err = m.forEach(func(k, v core.Any) (bool, error) {
+		ov, err := om.EntryAt(k)
+		if err != nil {
+			eq = false
+			return true, nil
+		}
+
+		if eq, err = keyEq(v, ov); err != nil {
+			return true, err
+		}
+		return !eq, nil
+	})
+	return eq, err
+}
+
+// SExpr returns a parsable s-expression for the Map.
+func (m PersistentMap) SExpr() (string, error) {
+	var b strings.Builder
+	b.WriteString("{")
+	first := true
+	err := m.forEach(func(k, v core.Any) (bool, error) {
+		if !first {
+			b.WriteString(", ")
+		}
+		first = false
+
+		for i, item := range [2]core.Any{k, v} {
+			s, err := toSExpr(item)
+			if err != nil {
+				return true, err
+			}
+			if i > 0 {
+				b.WriteString(" ")
+			}
+			b.WriteString(s)
+		}
+		return false, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	b.WriteString("}")
+	return b.String(), nil
+}
+
+func (m PersistentMap) forEach(fn func(k, v core.Any) (bool, error)) error {
+	if m.root == nil {
+		return nil
+	}
+	_, err := m.root.forEach(fn)
+	return err
+}
+
+func toSExpr(v core.Any) (string, error) {
+	if se, ok := v.(core.SExpressable); ok {
+		return se.SExpr()
+	}
+	return fmt.Sprintf("%v", v), nil
+}
+
+// hamtNode is a node in the hash array mapped trie. All operations return
+// a new node and never modify the receiver.
+type hamtNode interface {
+	assoc(shift uint, hash uint32, key, val core.Any) (hamtNode, bool, error)
+	dissoc(shift uint, hash uint32, key core.Any) (hamtNode, bool, error)
+	find(shift uint, hash uint32, key core.Any) (core.Any, bool, error)
+	forEach(fn func(k, v core.Any) (bool, error)) (bool, error)
+}
+
+// hamtEntry is either a key-value pair or a sub-node (if node is not nil).
+type hamtEntry struct {
+	key, val core.Any
+	node     hamtNode
+}
+
+// bitmapNode stores upto 32 entries compactly. Presence of an entry for
+// a 5-bit hash fragment is indicated by the corresponding bit in bitmap.
+type bitmapNode struct {
+	bitmap  uint32
+	entries []hamtEntry
+}
+
+func (n *bitmapNode) index(bit uint32) int {
+	return mbits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *bitmapNode) assoc(shift uint, hash uint32, key, val core.Any) (hamtNode, bool, error) {
+	bit := bitFor(hash, shift)
+	idx := n.index(bit)
+
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry, len(n.entries)+1)
+		copy(entries, n.entries[:idx])
+		entries[idx] = hamtEntry{key: key, val: val}
+		copy(entries[idx+1:], n.entries[idx:])
+		return &bitmapNode{bitmap: n.bitmap | bit, entries: entries}, true, nil
+	}
+
+	e := n.entries[idx]
+	if e.node != nil {
+		sub, added, err := e.node.assoc(shift+mapBits, hash, key, val)
+		if err != nil {
+			return nil, false, err
+		}
+		return n.with(idx, hamtEntry{node: sub}), added, nil
+	}
+
+	eq, err := keyEq(e.key, key)
+	if err != nil {
+		return nil, false, err
+	} else if eq {
+		return n.with(idx, hamtEntry{key: e.key, val: val}), false, nil
+	}
+
+	// hash fragment collision at this level. push both entries into a
+	// new sub-node.
+	eHash, err := hashOf(e.key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sub, err := newHamtNode(shift+mapBits, e.key, e.val, eHash, key, val, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	return n.with(idx, hamtEntry{node: sub}), true, nil
+}
+
+func (n *bitmapNode) dissoc(shift uint, hash uint32, key core.Any) (hamtNode, bool, error) {
+	bit := bitFor(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false, nil
+	}
+	idx := n.index(bit)
+
+	e := n.entries[idx]
+	if e.node != nil {
+		sub, removed, err := e.node.dissoc(shift+mapBits, hash, key)
+		if err != nil || !removed {
+			return n, false, err
+		}
+
+		if sub != nil {
+			return n.with(idx, hamtEntry{node: sub}), true, nil
+		}
+		return n.without(idx, bit), true, nil
+	}
+
+	eq, err := keyEq(e.key, key)
+	if err != nil || !eq {
+		return n, false, err
+	}
+	return n.without(idx, bit), true, nil
+}
+
+func (n *bitmapNode) find(shift uint, hash uint32, key core.Any) (core.Any, bool, error) {
+	bit := bitFor(hash, shift)
+	if n.bitmap&bit == 0 {
+		return nil, false, nil
+	}
+
+	e := n.entries[n.index(bit)]
+	if e.node != nil {
+		return e.node.find(shift+mapBits, hash, key)
+	}
+
+	eq, err := keyEq(e.key, key)
+	if err != nil || !eq {
+		return nil, false, err
+	}
+	return e.val, true, nil
+}
+
+func (n *bitmapNode) forEach(fn func(k, v core.Any) (bool, error)) (bool, error) {
+	for _, e := range n.entries {
+		var done bool
+		var err error
+		if e.node != nil {
+			done, err = e.node.forEach(fn)
+		} else {
+			done, err = fn(e.key, e.val)
+		}
+
+		if err != nil || done {
+			return done, err
+		}
+	}
+	return false, nil
+}
+
+func (n *bitmapNode) with(idx int, e hamtEntry) *bitmapNode {
+	entries := make([]hamtEntry, len(n.entries))
+	copy(entries, n.entries)
+	entries[idx] = e
+	return &bitmapNode{bitmap: n.bitmap, entries: entries}
+}
+
+func (n *bitmapNode) without(idx int, bit uint32) hamtNode {
+	if len(n.entries) == 1 {
+		return nil
+	}
+
+	entries := make([]hamtEntry, len(n.entries)-1)
+	copy(entries, n.entries[:idx])
+	copy(entries[idx:], n.entries[idx+1:])
+	return &bitmapNode{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+// collisionNode holds entries whose keys have the same full hash.
+type collisionNode struct {
+	hash    uint32
+	entries []hamtEntry
+}
+
+func (n *collisionNode) assoc(shift uint, hash uint32, key, val core.Any) (hamtNode, bool, error) {
+	if hash != n.hash {
+		// key with a different hash reached this node. nest this node
+		// inside a bitmap node and retry.
+		bn := &bitmapNode{
+			bitmap:  bitFor(n.hash, shift),
+			entries: []hamtEntry{{node: n}},
+		}
+		return bn.assoc(shift, hash, key, val)
+	}
+
+	idx, err := n.indexOf(key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	entries := make([]hamtEntry, len(n.entries), len(n.entries)+1)
+	copy(entries, n.entries)
+	if idx >= 0 {
+		entries[idx] = hamtEntry{key: entries[idx].key, val: val}
+		return &collisionNode{hash: n.hash, entries: entries}, false, nil
+	}
+
+	entries = append(entries, hamtEntry{key: key, val: val})
+	return &collisionNode{hash: n.hash, entries: entries}, true, nil
+}
+
+func (n *collisionNode) dissoc(_ uint, hash uint32, key core.Any) (hamtNode, bool, error) {
+	if hash != n.hash {
+		return n, false, nil
+	}
+
+	idx, err := n.indexOf(key)
+	if err != nil || idx < 0 {
+		return n, false, err
+	}
+
+	if len(n.entries) == 1 {
+		return nil, true, nil
+	}
+
+	entries := make([]hamtEntry, 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:idx]...)
+	entries = append(entries, n.entries[idx+1:]...)
+	return &collisionNode{hash: n.hash, entries: entries}, true, nil
+}
+
+func (n *collisionNode) find(_ uint, hash uint32, key core.Any) (core.Any, bool, error) {
+	if hash != n.hash {
+		return nil, false, nil
+	}
+
+	idx, err := n.indexOf(key)
+	if err != nil || idx < 0 {
+		return nil, false, err
+	}
+	return n.entries[idx].val, true, nil
+}
+
+func (n *collisionNode) forEach(fn func(k, v core.Any) (bool, error)) (bool, error) {
+	for _, e := range n.entries {
+		if done, err := fn(e.key, e.val); err != nil || done {
+			return done, err
+		}
+	}
+	return false, nil
+}
+
+func (n *collisionNode) indexOf(key core.Any) (int, error) {
+	for i, e := range n.entries {
+		eq, err := keyEq(e.key, key)
+		if err != nil {
+			return -1, err
+		} else if eq {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+func newHamtNode(shift uint, k1, v1 core.Any, h1 uint32, k2, v2 core.Any, h2 uint32) (hamtNode, error) {
+	if h1 == h2 {
+		return &collisionNode{
+			hash:    h1,
+			entries: []hamtEntry{{key: k1, val: v1}, {key: k2, val: v2}},
+		}, nil
+	}
+
+	n, _, err := (&bitmapNode{}).assoc(shift, h1, k1, v1)
+	if err != nil {
+		return nil, err
+	}
+
+	n, _, err = n.assoc(shift, h2, k2, v2)
+	return n, err
+}
+
+func bitFor(hash uint32, shift uint) uint32 {
+	return 1 << ((hash >> shift) & mapMask)
+}
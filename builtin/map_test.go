@@ -0,0 +1,233 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapIsHashable(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Error("PersistentMap is not hashable.")
+		}
+	}()
+
+	m := make(map[core.Map]struct{})
+	m[EmptyMap] = struct{}{}
+}
+
+func TestNewMap(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMap(Keyword("a"), Int64(1), Keyword("b"), Int64(2))
+	require.NoError(t, err)
+
+	cnt, err := m.Count()
+	require.NoError(t, err)
+	assert.Equal(t, 2, cnt)
+
+	_, err = NewMap(Keyword("a"))
+	assert.Error(t, err)
+}
+
+func TestEmptyMap(t *testing.T) {
+	t.Parallel()
+
+	testSExpr(t, EmptyMap, "{}")
+
+	cnt, err := EmptyMap.Count()
+	assert.NoError(t, err)
+	assert.Zero(t, cnt)
+
+	_, err = EmptyMap.EntryAt(Keyword("foo"))
+	assert.ErrorIs(t, err, core.ErrNotFound)
+
+	m, err := EmptyMap.Dissoc(Keyword("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, EmptyMap, m)
+
+	seq, err := EmptyMap.Seq()
+	assert.NoError(t, err)
+	cnt, err = seq.Count()
+	assert.NoError(t, err)
+	assert.Zero(t, cnt)
+}
+
+func TestPersistentMap_Assoc(t *testing.T) {
+	t.Parallel()
+
+	var m core.Map = EmptyMap
+	for i := 0; i < size; i++ {
+		var err error
+		m, err = m.Assoc(Int64(i), String("value"))
+		require.NoError(t, err)
+	}
+
+	cnt, err := m.Count()
+	require.NoError(t, err)
+	require.Equal(t, size, cnt)
+
+	for i := 0; i < size; i++ {
+		v, err := m.EntryAt(Int64(i))
+		require.NoError(t, err, "key %d", i)
+		assert.Equal(t, String("value"), v)
+	}
+
+	t.Run("Replace", func(t *testing.T) {
+		m2, err := m.Assoc(Int64(10), Keyword("replaced"))
+		require.NoError(t, err)
+
+		cnt, err := m2.Count()
+		require.NoError(t, err)
+		assert.Equal(t, size, cnt, "replacing value must not change count")
+
+		v, err := m2.EntryAt(Int64(10))
+		require.NoError(t, err)
+		assert.Equal(t, Keyword("replaced"), v)
+
+		v, err = m.EntryAt(Int64(10))
+		require.NoError(t, err)
+		assert.Equal(t, String("value"), v, "original map must not change")
+	})
+}
+
+func TestPersistentMap_Dissoc(t *testing.T) {
+	t.Parallel()
+
+	var m core.Map = EmptyMap
+	for i := 0; i < size; i++ {
+		var err error
+		m, err = m.Assoc(Int64(i), Int64(i))
+		require.NoError(t, err)
+	}
+
+	orig := m
+	for i := 0; i < size; i += 2 {
+		var err error
+		m, err = m.Dissoc(Int64(i))
+		require.NoError(t, err)
+	}
+
+	cnt, err := m.Count()
+	require.NoError(t, err)
+	assert.Equal(t, size/2, cnt)
+
+	for i := 0; i < size; i++ {
+		found, err := m.HasKey(Int64(i))
+		require.NoError(t, err)
+		assert.Equal(t, i%2 != 0, found, "key %d", i)
+
+		found, err = orig.HasKey(Int64(i))
+		require.NoError(t, err)
+		assert.True(t, found, "original map must not change (key %d)", i)
+	}
+}
+
+func TestPersistentMap_Seq(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMap(Keyword("a"), Int64(1), Keyword("b"), Int64(2))
+	require.NoError(t, err)
+
+	seq, err := m.Seq()
+	require.NoError(t, err)
+
+	got := map[Keyword]core.Any{}
+	err = core.ForEach(seq, func(item core.Any) (bool, error) {
+		require.IsType(t, PersistentVector{}, item)
+		entry := item.(PersistentVector)
+
+		k, err := entry.EntryAt(0)
+		require.NoError(t, err)
+		v, err := entry.EntryAt(1)
+		require.NoError(t, err)
+
+		got[k.(Keyword)] = v
+		return false, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[Keyword]core.Any{"a": Int64(1), "b": Int64(2)}, got)
+}
+
+func TestPersistentMap_Equals(t *testing.T) {
+	t.Parallel()
+
+	m1, err := NewMap(Keyword("a"), Int64(1), Keyword("b"), Int64(2))
+	require.NoError(t, err)
+
+	m2, err := NewMap(Keyword("b"), Int64(2), Keyword("a"), Int64(1))
+	require.NoError(t, err)
+
+	m3, err := NewMap(Keyword("b"), Int64(2), Keyword("a"), Int64(3))
+	require.NoError(t, err)
+
+	eq, err := core.Eq(m1, m2)
+	assert.NoError(t, err)
+	assert.True(t, eq)
+
+	eq, err = core.Eq(m1, m3)
+	assert.NoError(t, err)
+	assert.False(t, eq)
+
+	eq, err = core.Eq(m1, EmptyMap)
+	assert.NoError(t, err)
+	assert.False(t, eq)
+}
+
+func TestPersistentMap_SExpr(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMap(Keyword("a"), String("hello"))
+	require.NoError(t, err)
+	testSExpr(t, m, `{:a "hello"}`)
+}
+
+func TestPersistentMap_CollectionKeys(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewMap(NewVector(Int64(1), Int64(2)), Keyword("vec"))
+	require.NoError(t, err)
+
+	v, err := m.EntryAt(NewVector(Int64(1), Int64(2)))
+	require.NoError(t, err)
+	assert.Equal(t, Keyword("vec"), v)
+}
+
+func Test_collisionNode(t *testing.T) {
+	t.Parallel()
+
+	const hash = 0xdeadbeef
+
+	n, err := newHamtNode(0, Keyword("a"), Int64(1), hash, Keyword("b"), Int64(2), hash)
+	require.NoError(t, err)
+	require.IsType(t, &collisionNode{}, n)
+
+	v, found, err := n.find(0, hash, Keyword("b"))
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Int64(2), v)
+
+	// a key with different hash must split the collision node.
+	n, added, err := n.assoc(0, 0x1, Keyword("c"), Int64(3))
+	require.NoError(t, err)
+	assert.True(t, added)
+	require.IsType(t, &bitmapNode{}, n)
+
+	for k, want := range map[Keyword]uint32{"a": hash, "b": hash, "c": 0x1} {
+		_, found, err := n.find(0, want, k)
+		require.NoError(t, err)
+		assert.True(t, found, "key %s", k)
+	}
+
+	n, removed, err := n.dissoc(0, hash, Keyword("a"))
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	_, found, err = n.find(0, hash, Keyword("a"))
+	require.NoError(t, err)
+	assert.False(t, found)
+}
@@ -0,0 +1,145 @@
+package builtin
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/slurp/core"
+)
+
+// Keys of the source position metadata attached to the collection forms
+// by the reader.
+const (
+	MetaFile = Keyword("file")
+	MetaLine = Keyword("line")
+	MetaCol  = Keyword("col")
+)
+
+var (
+	_ core.Meta             = MetaSymbol{}
+	_ core.EqualityProvider = MetaSymbol{}
+)
+
+// MetaSymbol is a Symbol with metadata (e.g., ^:private foo). Analyzer
+// treats MetaSymbol the same as the Symbol it wraps.
+type MetaSymbol struct {
+	Symbol Symbol
+	meta   core.Map
+}
+
+// Meta returns the metadata of the symbol.
+func (ms MetaSymbol) Meta() core.Map { return ms.meta }
+
+// WithMeta returns the symbol with the metadata replaced.
+func (ms MetaSymbol) WithMeta(meta core.Map) core.Any {
+	return MetaSymbol{Symbol: ms.Symbol, meta: meta}
+}
+
+// SExpr returns the s-expression of the symbol.
+func (ms MetaSymbol) SExpr() (string, error) { return ms.Symbol.SExpr() }
+
+// Equals returns true if the other value is the same symbol (with or
+// without metadata).
+func (ms MetaSymbol) Equals(other core.Any) (bool, error) { return ms.Symbol.Equals(other) }
+
+func (ms MetaSymbol) String() string { return ms.Symbol.String() }
+
+// SymbolOf returns the symbol if the form is a Symbol or a MetaSymbol.
+func SymbolOf(form core.Any) (Symbol, bool) {
+	switch f := form.(type) {
+	case Symbol:
+		return f, true
+
+	case MetaSymbol:
+		return f.Symbol, true
+
+	default:
+		return "", false
+	}
+}
+
+// MetaOf returns the metadata of the value. Returns Nil if the value has
+// no metadata.
+func MetaOf(v core.Any) core.Any {
+	if m, ok := v.(core.Meta); ok && m.Meta() != nil {
+		return m.Meta()
+	}
+	return Nil{}
+}
+
+// WithMeta returns the value with its metadata replaced by meta, which
+// must be a map or nil. Symbols are wrapped in MetaSymbol.
+func WithMeta(v core.Any, meta core.Any) (core.Any, error) {
+	var m core.Map
+	if !IsNil(meta) {
+		var ok bool
+		if m, ok = meta.(core.Map); !ok {
+			return nil, fmt.Errorf("metadata must be a map, not '%s'", reflect.TypeOf(meta))
+		}
+	}
+
+	switch val := v.(type) {
+	case Symbol:
+		return MetaSymbol{Symbol: val, meta: m}, nil
+
+	case core.Meta:
+		return val.WithMeta(m), nil
+
+	default:
+		return nil, fmt.Errorf("value of type '%s' does not support metadata", reflect.TypeOf(v))
+	}
+}
+
+// PositionOf returns the source position recorded in the metadata of the
+// form (See MetaFile, MetaLine and MetaCol). Returns zero value if the
+// form has no position.
+func PositionOf(form core.Any) core.Position {
+	m, ok := form.(core.Meta)
+	if !ok || m.Meta() == nil {
+		return core.Position{}
+	}
+
+	var pos core.Position
+	meta := m.Meta()
+	if v, err := meta.EntryAt(MetaFile); err == nil {
+		if file, ok := v.(String); ok {
+			pos.File = string(file)
+		}
+	}
+	if v, err := meta.EntryAt(MetaLine); err == nil {
+		if ln, ok := v.(Int64); ok {
+			pos.Ln = int(ln)
+		}
+	}
+	if v, err := meta.EntryAt(MetaCol); err == nil {
+		if col, ok := v.(Int64); ok {
+			pos.Col = int(col)
+		}
+	}
+	return pos
+}
+
+// withoutPosition returns the value with the source position keys removed
+// from its metadata. Used by collection literals so that evaluated values
+// do not carry the position of the form.
+func withoutPosition(v core.Any) (core.Any, error) {
+	m, ok := v.(core.Meta)
+	if !ok || m.Meta() == nil {
+		return v, nil
+	}
+
+	meta := m.Meta()
+	for _, key := range []core.Any{MetaFile, MetaLine, MetaCol} {
+		var err error
+		if meta, err = meta.Dissoc(key); err != nil {
+			return nil, err
+		}
+	}
+
+	if cnt, err := meta.Count(); err != nil {
+		return nil, err
+	} else if cnt == 0 {
+		meta = nil
+	}
+	return m.WithMeta(meta), nil
+}
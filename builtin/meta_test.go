@@ -0,0 +1,148 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithMeta(t *testing.T) {
+	t.Parallel()
+
+	meta, err := NewMap(Keyword("doc"), String("hello"))
+	require.NoError(t, err)
+
+	values := []core.Any{
+		Symbol("foo"),
+		NewList(Int64(1)),
+		NewList(),
+		NewVector(Int64(1)),
+		EmptyMap,
+		EmptySet,
+	}
+
+	for _, v := range values {
+		assert.Equal(t, Nil{}, MetaOf(v), "%#v", v)
+
+		got, err := WithMeta(v, meta)
+		require.NoError(t, err, "%#v", v)
+		assert.Equal(t, meta, MetaOf(got), "%#v", v)
+
+		eq, err := core.Eq(got, v)
+		require.NoError(t, err)
+		assert.True(t, eq, "metadata must not affect equality of %#v", v)
+
+		got, err = WithMeta(got, Nil{})
+		require.NoError(t, err, "%#v", v)
+		assert.Equal(t, Nil{}, MetaOf(got), "%#v", v)
+	}
+
+	_, err = WithMeta(Int64(1), meta)
+	assert.Error(t, err)
+
+	_, err = WithMeta(Symbol("foo"), Int64(1))
+	assert.Error(t, err)
+}
+
+func TestMeta_Preserved(t *testing.T) {
+	t.Parallel()
+
+	meta, err := NewMap(Keyword("a"), Int64(1))
+	require.NoError(t, err)
+
+	vec := NewVector(Int64(1)).WithMeta(meta).(PersistentVector)
+	conj, err := vec.Conj(Int64(2))
+	require.NoError(t, err)
+	assoc, err := vec.Assoc(0, Int64(3))
+	require.NoError(t, err)
+	pop, err := vec.Pop()
+	require.NoError(t, err)
+
+	m := EmptyMap.WithMeta(meta).(PersistentMap)
+	mAssoc, err := m.Assoc(Keyword("k"), Int64(1))
+	require.NoError(t, err)
+	mDissoc, err := mAssoc.Dissoc(Keyword("k"))
+	require.NoError(t, err)
+
+	set := EmptySet.WithMeta(meta).(PersistentSet)
+	sConj, err := set.Conj(Int64(1))
+	require.NoError(t, err)
+	sDisj, err := sConj.Disj(Int64(1))
+	require.NoError(t, err)
+
+	list := NewList(Int64(1)).(*LinkedList).WithMeta(meta).(*LinkedList)
+	lConj, err := list.Conj(Int64(0))
+	require.NoError(t, err)
+
+	for _, v := range []core.Any{conj, assoc, pop, mAssoc, mDissoc, sConj, sDisj, lConj} {
+		assert.Equal(t, meta, MetaOf(v), "%#v", v)
+	}
+}
+
+func TestMetaSymbol(t *testing.T) {
+	t.Parallel()
+
+	meta, err := NewMap(Keyword("dynamic"), Bool(true))
+	require.NoError(t, err)
+
+	v, err := WithMeta(Symbol("foo"), meta)
+	require.NoError(t, err)
+
+	ms, ok := v.(MetaSymbol)
+	require.True(t, ok)
+	testSExpr(t, ms, "foo")
+
+	sym, ok := SymbolOf(ms)
+	assert.True(t, ok)
+	assert.Equal(t, Symbol("foo"), sym)
+
+	for _, pair := range [][2]core.Any{{ms, Symbol("foo")}, {Symbol("foo"), ms}} {
+		eq, err := core.Eq(pair[0], pair[1])
+		assert.NoError(t, err)
+		assert.True(t, eq)
+	}
+
+	m, err := NewMap(Symbol("foo"), Int64(1))
+	require.NoError(t, err)
+	got, err := m.EntryAt(ms)
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(1), got)
+
+	expr, err := Analyzer{}.Analyze(nil, ms)
+	assert.NoError(t, err)
+	assert.Equal(t, ResolveExpr{Symbol: "foo"}, expr)
+}
+
+func TestPositionOf(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, core.Position{}, PositionOf(Int64(1)))
+	assert.Equal(t, core.Position{}, PositionOf(NewList(Int64(1))))
+
+	meta, err := NewMap(MetaFile, String("main.slurp"), MetaLine, Int64(2), MetaCol, Int64(3))
+	require.NoError(t, err)
+
+	form := NewList(Symbol("foo")).(*LinkedList).WithMeta(meta)
+	assert.Equal(t, core.Position{File: "main.slurp", Ln: 2, Col: 3}, PositionOf(form))
+
+	expr, err := Analyzer{}.Analyze(core.New(nil), form)
+	require.NoError(t, err)
+	assert.Equal(t, core.Position{File: "main.slurp", Ln: 2, Col: 3}, expr.(InvokeExpr).Position)
+}
+
+func TestVectorExpr_Eval_Meta(t *testing.T) {
+	t.Parallel()
+
+	meta, err := NewMap(MetaLine, Int64(1), Keyword("doc"), String("hello"))
+	require.NoError(t, err)
+
+	vec := NewVector(Int64(1)).WithMeta(meta).(core.Vector)
+	got, err := VectorExpr{Vector: vec, Analyzer: Analyzer{}}.Eval(core.New(nil))
+	require.NoError(t, err)
+
+	want, err := NewMap(Keyword("doc"), String("hello"))
+	require.NoError(t, err)
+	assert.Equal(t, want, MetaOf(got), "position must be removed")
+}
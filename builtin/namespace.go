@@ -0,0 +1,262 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/spy16/slurp/core"
+)
+
+// CurrentNSVar is the name of the root env binding that holds the current
+// namespace (i.e., *Namespace).
+const CurrentNSVar = "*ns*"
+
+// ErrNoNamespace is returned when a namespace is not found.
+var ErrNoNamespace = errors.New("namespace not found")
+
+var _ core.Any = (*Namespace)(nil)
+
+// Namespace holds the global bindings (defined using def) of a namespace
+// and the references to other namespaces (refers and aliases). All the
+// namespaces reachable from an env share a common registry.
+type Namespace struct {
+	name string
+	reg  *nsRegistry
+
+	mu       sync.RWMutex
+	vars     map[string]core.Any
+	refers   map[string]*Namespace
+	referAll []*Namespace
+	aliases  map[string]*Namespace
+}
+
+// CurrentNS returns the current namespace set in the context of the env (See
+// WithCurrentNS) or in the root of the env. Returns nil if namespaces are
+// not in use.
+func CurrentNS(env core.Env) *Namespace {
+	if cur, ok := core.ContextOf(env).Value(currentNSKey{}).(*currentNS); ok {
+		cur.mu.RLock()
+		defer cur.mu.RUnlock()
+		return cur.ns
+	}
+
+	v, err := core.Root(env).Resolve(CurrentNSVar)
+	if err != nil {
+		return nil
+	}
+	ns, _ := v.(*Namespace)
+	return ns
+}
+
+// InNS sets the namespace with given name as the current namespace in the
+// context of the env (See WithCurrentNS) or in the root of the env. The
+// namespace is created if it does not exist.
+func InNS(env core.Env, name string) (*Namespace, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("%w: '%s'", core.ErrInvalidName, name)
+	}
+
+	reg := &nsRegistry{namespaces: map[string]*Namespace{}}
+	if cur := CurrentNS(env); cur != nil {
+		reg = cur.reg
+	}
+
+	ns := reg.get(name, true)
+	if cur, ok := core.ContextOf(env).Value(currentNSKey{}).(*currentNS); ok {
+		cur.mu.Lock()
+		defer cur.mu.Unlock()
+		cur.ns = ns
+		return ns, nil
+	}
+
+	if err := core.Root(env).Bind(CurrentNSVar, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// WithCurrentNS returns a copy of the context in which the namespace is the
+// current namespace. Switching namespaces (See InNS) in an env carrying the
+// context changes the current namespace of the context only. This allows an
+// evaluation (e.g., loading a file) to switch namespaces without affecting
+// the other evaluations sharing the root env.
+func WithCurrentNS(ctx context.Context, ns *Namespace) context.Context {
+	return context.WithValue(ctx, currentNSKey{}, &currentNS{ns: ns})
+}
+
+// FindNS returns the namespace with given name or alias (in the current
+// namespace). Returns ErrNoNamespace if not found.
+func FindNS(env core.Env, name string) (*Namespace, error) {
+	cur := CurrentNS(env)
+	if cur == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoNamespace, name)
+	}
+
+	cur.mu.RLock()
+	ns, found := cur.aliases[name]
+	cur.mu.RUnlock()
+	if found {
+		return ns, nil
+	}
+
+	if ns := cur.reg.get(name, false); ns != nil {
+		return ns, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrNoNamespace, name)
+}
+
+// Name returns the name of the namespace.
+func (ns *Namespace) Name() string { return ns.name }
+
+// Bind creates or replaces the global binding in the namespace.
+func (ns *Namespace) Bind(name string, val core.Any) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: %s", core.ErrInvalidName, name)
+	}
+
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.vars[name] = val
+	return nil
+}
+
+// Resolve returns the value bound to the name in the namespace or the
+// value referred from other namespaces. Returns ErrNotFound otherwise.
+func (ns *Namespace) Resolve(name string) (core.Any, error) {
+	ns.mu.RLock()
+	v, found := ns.vars[name]
+	other := ns.refers[name]
+	all := ns.referAll
+	ns.mu.RUnlock()
+
+	if found {
+		return v, nil
+	} else if other != nil {
+		return other.resolveOwn(name)
+	}
+
+	for _, other := range all {
+		if v, err := other.resolveOwn(name); err == nil {
+			return v, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s/%s", core.ErrNotFound, ns.name, name)
+}
+
+// Refer makes the bindings of the other namespace resolvable by their
+// names in this namespace. All bindings are referred if no names are
+// given.
+func (ns *Namespace) Refer(other *Namespace, names ...string) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+
+	if len(names) == 0 {
+		for _, existing := range ns.referAll {
+			if existing == other {
+				return
+			}
+		}
+		ns.referAll = append(ns.referAll, other)
+		return
+	}
+
+	for _, name := range names {
+		ns.refers[name] = other
+	}
+}
+
+// Alias makes the other namespace resolvable by the alias in qualified
+// symbols (e.g., alias/name) within this namespace.
+func (ns *Namespace) Alias(alias string, other *Namespace) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	ns.aliases[alias] = other
+}
+
+// Owner returns the name of the namespace that owns the binding resolved
+// by name in this namespace. Returns false if the name is not resolvable
+// in this namespace.
+func (ns *Namespace) Owner(name string) (string, bool) {
+	ns.mu.RLock()
+	_, found := ns.vars[name]
+	other := ns.refers[name]
+	all := ns.referAll
+	ns.mu.RUnlock()
+
+	if found {
+		return ns.name, true
+	} else if other != nil {
+		return other.name, true
+	}
+
+	for _, other := range all {
+		if _, err := other.resolveOwn(name); err == nil {
+			return other.name, true
+		}
+	}
+	return "", false
+}
+
+// SExpr returns a string representation of the namespace.
+func (ns *Namespace) SExpr() (string, error) { return ns.String(), nil }
+
+func (ns *Namespace) String() string { return fmt.Sprintf("#namespace[%s]", ns.name) }
+
+func (ns *Namespace) resolveOwn(name string) (core.Any, error) {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+
+	v, found := ns.vars[name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s/%s", core.ErrNotFound, ns.name, name)
+	}
+	return v, nil
+}
+
+type nsRegistry struct {
+	mu         sync.Mutex
+	namespaces map[string]*Namespace
+}
+
+type currentNSKey struct{}
+
+// currentNS holds the current namespace of the evaluations carrying the
+// context created by WithCurrentNS.
+type currentNS struct {
+	mu sync.RWMutex
+	ns *Namespace
+}
+
+func (reg *nsRegistry) get(name string, create bool) *Namespace {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	ns, found := reg.namespaces[name]
+	if !found && create {
+		ns = &Namespace{
+			name:    name,
+			reg:     reg,
+			vars:    map[string]core.Any{},
+			refers:  map[string]*Namespace{},
+			aliases: map[string]*Namespace{},
+		}
+		reg.namespaces[name] = ns
+	}
+	return ns
+}
+
+// splitQualified splits the symbol of the form ns/name into namespace
+// and name. Returns false if the symbol is not qualified.
+func splitQualified(sym Symbol) (string, string, bool) {
+	s := string(sym)
+	idx := strings.Index(s, "/")
+	if idx <= 0 || idx == len(s)-1 {
+		return "", "", false
+	}
+	return s[:idx], s[idx+1:], true
+}
@@ -0,0 +1,179 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInNS(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(nil)
+	assert.Nil(t, CurrentNS(env))
+
+	user, err := InNS(env.Child("<let>", nil), "user")
+	require.NoError(t, err)
+	assert.Equal(t, "user", user.Name())
+	assert.Equal(t, user, CurrentNS(env), "current ns must be set in root env")
+
+	other, err := InNS(env, "other")
+	require.NoError(t, err)
+	assert.Equal(t, other, CurrentNS(env))
+
+	again, err := InNS(env, "user")
+	require.NoError(t, err)
+	assert.Same(t, user, again, "existing namespace must be reused")
+
+	_, err = InNS(env, "a/b")
+	assert.ErrorIs(t, err, core.ErrInvalidName)
+}
+
+func TestWithCurrentNS(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(nil)
+	user, err := InNS(env, "user")
+	require.NoError(t, err)
+
+	ctxEnv := core.ContextChild(env, "<eval>", WithCurrentNS(context.Background(), user))
+	assert.Same(t, user, CurrentNS(ctxEnv.Child("<let>", nil)))
+
+	other, err := InNS(ctxEnv, "other")
+	require.NoError(t, err)
+	assert.Same(t, other, CurrentNS(ctxEnv), "current ns of the context must be switched")
+	assert.Same(t, user, CurrentNS(env), "current ns of the root env must not change")
+
+	got, err := FindNS(env, "other")
+	require.NoError(t, err)
+	assert.Same(t, other, got, "namespaces must share the registry")
+
+	require.NoError(t, other.Bind("x", Int64(1)))
+	v, err := ResolveExpr{Symbol: "x"}.Eval(ctxEnv.Child("<let>", nil))
+	require.NoError(t, err)
+	assert.Equal(t, Int64(1), v)
+}
+
+func TestFindNS(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(nil)
+	_, err := FindNS(env, "user")
+	assert.ErrorIs(t, err, ErrNoNamespace)
+
+	other, err := InNS(env, "other")
+	require.NoError(t, err)
+
+	user, err := InNS(env, "user")
+	require.NoError(t, err)
+	user.Alias("o", other)
+
+	got, err := FindNS(env, "other")
+	require.NoError(t, err)
+	assert.Same(t, other, got)
+
+	got, err = FindNS(env, "o")
+	require.NoError(t, err)
+	assert.Same(t, other, got)
+
+	_, err = FindNS(env, "unknown")
+	assert.ErrorIs(t, err, ErrNoNamespace)
+}
+
+func TestNamespace_Resolve(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(nil)
+	a, err := InNS(env, "a")
+	require.NoError(t, err)
+	require.NoError(t, a.Bind("x", Int64(1)))
+	require.NoError(t, a.Bind("y", Int64(2)))
+
+	b, err := InNS(env, "b")
+	require.NoError(t, err)
+	require.NoError(t, b.Bind("z", Int64(3)))
+
+	ns, err := InNS(env, "user")
+	require.NoError(t, err)
+	require.NoError(t, ns.Bind("x", Int64(10)))
+
+	_, err = ns.Resolve("y")
+	assert.ErrorIs(t, err, core.ErrNotFound)
+
+	ns.Refer(a, "y")
+	ns.Refer(b)
+
+	for name, want := range map[string]core.Any{"x": Int64(10), "y": Int64(2), "z": Int64(3)} {
+		v, err := ns.Resolve(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, v, name)
+	}
+
+	for name, want := range map[string]string{"x": "user", "y": "a", "z": "b"} {
+		owner, found := ns.Owner(name)
+		assert.True(t, found, name)
+		assert.Equal(t, want, owner, name)
+	}
+
+	_, found := ns.Owner("unknown")
+	assert.False(t, found)
+
+	testSExpr(t, ns, "#namespace[user]")
+}
+
+func TestResolveExpr_Eval_Namespace(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(map[string]core.Any{"global": Int64(0)})
+	other, err := InNS(env, "other")
+	require.NoError(t, err)
+	require.NoError(t, other.Bind("x", Int64(1)))
+
+	user, err := InNS(env, "user")
+	require.NoError(t, err)
+	require.NoError(t, user.Bind("x", Int64(2)))
+	user.Alias("o", other)
+
+	local := env.Child("<let>", map[string]core.Any{"y": Int64(3)})
+
+	table := map[Symbol]core.Any{
+		"x":       Int64(2),
+		"y":       Int64(3),
+		"global":  Int64(0),
+		"other/x": Int64(1),
+		"o/x":     Int64(1),
+		"user/x":  Int64(2),
+	}
+	for sym, want := range table {
+		got, err := ResolveExpr{Symbol: sym}.Eval(local)
+		assert.NoError(t, err, sym)
+		assert.Equal(t, want, got, sym)
+	}
+
+	_, err = ResolveExpr{Symbol: "other/y"}.Eval(local)
+	assert.ErrorIs(t, err, core.ErrNotFound)
+
+	_, err = ResolveExpr{Symbol: "unknown/x"}.Eval(local)
+	assert.ErrorIs(t, err, core.ErrNotFound)
+}
+
+func TestDefExpr_Eval_Namespace(t *testing.T) {
+	t.Parallel()
+
+	env := core.New(nil)
+	ns, err := InNS(env, "user")
+	require.NoError(t, err)
+
+	_, err = DefExpr{Name: "x", Value: ConstExpr{Const: Int64(1)}}.Eval(env.Child("<let>", nil))
+	require.NoError(t, err)
+
+	v, err := ns.Resolve("x")
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(1), v)
+
+	_, err = env.Resolve("x")
+	assert.ErrorIs(t, err, core.ErrNotFound, "def must not bind in root env")
+}
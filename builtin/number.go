@@ -0,0 +1,519 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	// ErrNotNumber is returned by the arithmetic functions when an operand
+	// is not one of the number types.
+	ErrNotNumber = errors.New("not a number")
+
+	// ErrArithmetic is returned for invalid arithmetic operations such as
+	// integer division by zero.
+	ErrArithmetic = errors.New("arithmetic error")
+)
+
+// numKind is the rank of a number type in the numeric tower. Operations on
+// numbers of different types are performed on the higher ranked type:
+//
+//	Int64 < BigInt < Ratio < BigDecimal < Float64
+//
+// Int64 operations that overflow are promoted to BigInt. Ratio results that
+// are integral are returned as Int64 (or BigInt if they do not fit).
+type numKind int
+
+const (
+	kindInt64 numKind = iota
+	kindBigInt
+	kindRatio
+	kindBigDecimal
+	kindFloat64
+)
+
+func kindOf(v core.Any) (numKind, bool) {
+	switch v.(type) {
+	case Int64:
+		return kindInt64, true
+	case BigInt:
+		return kindBigInt, true
+	case Ratio:
+		return kindRatio, true
+	case BigDecimal:
+		return kindBigDecimal, true
+	case Float64:
+		return kindFloat64, true
+	}
+	return 0, false
+}
+
+// IsNumber returns true if v is one of the number types (Int64, BigInt,
+// Ratio, BigDecimal or Float64).
+func IsNumber(v core.Any) bool {
+	_, ok := kindOf(v)
+	return ok
+}
+
+// Add returns a + b.
+func Add(a, b core.Any) (core.Any, error) {
+	return arith("+", a, b, arithOps{
+		i64: func(x, y int64) (int64, bool) {
+			z := x + y
+			return z, (z > x) == (y > 0)
+		},
+		bigInt: func(x, y *big.Int) (core.Any, error) { return BigInt{v: new(big.Int).Add(x, y)}, nil },
+		ratio:  func(x, y *big.Rat) (core.Any, error) { return ratioOrInt(new(big.Rat).Add(x, y)), nil },
+		dec: func(x, y BigDecimal) (core.Any, error) {
+			x, y = alignScale(x, y)
+			return BigDecimal{unscaled: new(big.Int).Add(x.val(), y.val()), scale: x.scale}, nil
+		},
+		f64: func(x, y float64) (core.Any, error) { return Float64(x + y), nil },
+	})
+}
+
+// Sub returns a - b.
+func Sub(a, b core.Any) (core.Any, error) {
+	return arith("-", a, b, arithOps{
+		i64: func(x, y int64) (int64, bool) {
+			z := x - y
+			return z, (z < x) == (y > 0)
+		},
+		bigInt: func(x, y *big.Int) (core.Any, error) { return BigInt{v: new(big.Int).Sub(x, y)}, nil },
+		ratio:  func(x, y *big.Rat) (core.Any, error) { return ratioOrInt(new(big.Rat).Sub(x, y)), nil },
+		dec: func(x, y BigDecimal) (core.Any, error) {
+			x, y = alignScale(x, y)
+			return BigDecimal{unscaled: new(big.Int).Sub(x.val(), y.val()), scale: x.scale}, nil
+		},
+		f64: func(x, y float64) (core.Any, error) { return Float64(x - y), nil },
+	})
+}
+
+// Mul returns a * b.
+func Mul(a, b core.Any) (core.Any, error) {
+	return arith("*", a, b, arithOps{
+		i64: func(x, y int64) (int64, bool) {
+			if x == 0 || y == 0 {
+				return 0, true
+			}
+			z := x * y
+			return z, z/y == x && !(x == -1 && y == math.MinInt64) && !(y == -1 && x == math.MinInt64)
+		},
+		bigInt: func(x, y *big.Int) (core.Any, error) { return BigInt{v: new(big.Int).Mul(x, y)}, nil },
+		ratio:  func(x, y *big.Rat) (core.Any, error) { return ratioOrInt(new(big.Rat).Mul(x, y)), nil },
+		dec: func(x, y BigDecimal) (core.Any, error) {
+			return BigDecimal{unscaled: new(big.Int).Mul(x.val(), y.val()), scale: x.scale + y.scale}, nil
+		},
+		f64: func(x, y float64) (core.Any, error) { return Float64(x * y), nil },
+	})
+}
+
+// Div returns a / b. Division of integers that is not exact returns Ratio
+// (e.g., 1/3). Decimal division must have an exact decimal result (i.e.,
+// 1M / 3 fails). Division by zero fails with ErrArithmetic unless one of
+// the operands is Float64.
+func Div(a, b core.Any) (core.Any, error) {
+	return arith("/", a, b, arithOps{
+		ratio: func(x, y *big.Rat) (core.Any, error) {
+			if y.Sign() == 0 {
+				return nil, errDivideByZero()
+			}
+			return ratioOrInt(new(big.Rat).Quo(x, y)), nil
+		},
+		dec: func(x, y BigDecimal) (core.Any, error) {
+			if y.val().Sign() == 0 {
+				return nil, errDivideByZero()
+			}
+			return ratToDecimal(new(big.Rat).Quo(x.Rat(), y.Rat()), x.scale-y.scale)
+		},
+		f64: func(x, y float64) (core.Any, error) { return Float64(x / y), nil },
+	})
+}
+
+// Quot returns the quotient of a / b truncated towards zero.
+func Quot(a, b core.Any) (core.Any, error) {
+	return arith("quot", a, b, arithOps{
+		i64: func(x, y int64) (int64, bool) {
+			if y == 0 {
+				return 0, false
+			}
+			return x / y, !(x == math.MinInt64 && y == -1)
+		},
+		bigInt: func(x, y *big.Int) (core.Any, error) {
+			if y.Sign() == 0 {
+				return nil, errDivideByZero()
+			}
+			return BigInt{v: new(big.Int).Quo(x, y)}, nil
+		},
+		ratio: func(x, y *big.Rat) (core.Any, error) {
+			if y.Sign() == 0 {
+				return nil, errDivideByZero()
+			}
+			return intOf(truncRat(new(big.Rat).Quo(x, y))), nil
+		},
+		dec: func(x, y BigDecimal) (core.Any, error) {
+			if y.val().Sign() == 0 {
+				return nil, errDivideByZero()
+			}
+			return BigDecimal{unscaled: truncRat(new(big.Rat).Quo(x.Rat(), y.Rat()))}, nil
+		},
+		f64: func(x, y float64) (core.Any, error) { return Float64(math.Trunc(x / y)), nil },
+	})
+}
+
+// Rem returns the remainder of a / b. The result has the sign of a.
+func Rem(a, b core.Any) (core.Any, error) {
+	if x, ok := a.(Int64); ok {
+		if y, ok := b.(Int64); ok && y != 0 {
+			if y == -1 {
+				return Int64(0), nil
+			}
+			return x % y, nil
+		}
+	}
+
+	q, err := Quot(a, b)
+	if err != nil {
+		return nil, err
+	} else if _, isFloat := q.(Float64); isFloat {
+		return Float64(math.Mod(toFloat64(a), toFloat64(b))), nil
+	}
+
+	qb, err := Mul(q, b)
+	if err != nil {
+		return nil, err
+	}
+	return Sub(a, qb)
+}
+
+// Mod returns the modulus of a / b. The result has the sign of b.
+func Mod(a, b core.Any) (core.Any, error) {
+	r, err := Rem(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	rs, _ := sign(r)
+	bs, _ := sign(b)
+	if rs != 0 && rs != bs {
+		return Add(r, b)
+	}
+	return r, nil
+}
+
+// Neg returns -a.
+func Neg(a core.Any) (core.Any, error) {
+	if !IsNumber(a) {
+		return nil, notNumber("-", a)
+	}
+	return Sub(Int64(0), a)
+}
+
+// compareNumbers compares the numbers a and b of any number type. Numbers
+// are compared by their exact value (i.e., 1, 1N, 1.0M, 2/2 and 1.0 are
+// all equal). Returns ErrIncomparable if one of them is not a number or is
+// NaN.
+func compareNumbers(a, b core.Any) (int, error) {
+	ka, okA := kindOf(a)
+	kb, okB := kindOf(b)
+	if !okA || !okB {
+		return 0, core.ErrIncomparable
+	}
+
+	switch {
+	case ka == kindInt64 && kb == kindInt64:
+		return compareInt64(int64(a.(Int64)), int64(b.(Int64))), nil
+
+	case ka == kindFloat64 && kb == kindFloat64:
+		x, y := a.(Float64), b.(Float64)
+		switch {
+		case math.IsNaN(float64(x)) || math.IsNaN(float64(y)):
+			return 0, core.ErrIncomparable
+		case x > y:
+			return 1, nil
+		case x < y:
+			return -1, nil
+		default:
+			return 0, nil
+		}
+
+	case ka == kindFloat64 || kb == kindFloat64:
+		if f, ok := a.(Float64); ok && !isFinite(float64(f)) {
+			return compareNonFinite(float64(f))
+		} else if f, ok := b.(Float64); ok && !isFinite(float64(f)) {
+			c, err := compareNonFinite(float64(f))
+			return -c, err
+		}
+	}
+
+	return toRat(a).Cmp(toRat(b)), nil
+}
+
+// hashNumber returns a hash of the number such that numbers which compare
+// equal have the same hash.
+func hashNumber(v core.Any) uint32 {
+	switch n := v.(type) {
+	case Int64:
+		return hashUint64(uint64(n))
+
+	case Float64:
+		f := float64(n)
+		if !isFinite(f) {
+			return hashUint64(math.Float64bits(f))
+		} else if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return hashUint64(uint64(int64(f)))
+		}
+	}
+
+	r := toRat(v)
+	if !r.IsInt() {
+		return hashString("r", r.RatString())
+	} else if r.Num().IsInt64() {
+		return hashUint64(uint64(r.Num().Int64()))
+	}
+	return hashString("n", r.Num().String())
+}
+
+// arithOps are the implementations of an arithmetic operation for each of
+// the number types. If i64 is nil or overflows, bigInt is used for Int64
+// operands. If bigInt is nil, ratio is used for BigInt operands.
+type arithOps struct {
+	i64    func(x, y int64) (res int64, ok bool)
+	bigInt func(x, y *big.Int) (core.Any, error)
+	ratio  func(x, y *big.Rat) (core.Any, error)
+	dec    func(x, y BigDecimal) (core.Any, error)
+	f64    func(x, y float64) (core.Any, error)
+}
+
+// arith performs the operation on a and b in the higher ranked type of the
+// two.
+func arith(name string, a, b core.Any, ops arithOps) (core.Any, error) {
+	ka, ok := kindOf(a)
+	if !ok {
+		return nil, notNumber(name, a)
+	}
+
+	kb, ok := kindOf(b)
+	if !ok {
+		return nil, notNumber(name, b)
+	}
+
+	kind := ka
+	if kb > kind {
+		kind = kb
+	}
+
+	switch kind {
+	case kindInt64:
+		if ops.i64 != nil {
+			if res, ok := ops.i64(int64(a.(Int64)), int64(b.(Int64))); ok {
+				return Int64(res), nil
+			}
+		}
+		fallthrough
+
+	case kindBigInt:
+		if ops.bigInt != nil {
+			res, err := ops.bigInt(toBigInt(a), toBigInt(b))
+			if err != nil || kind == kindBigInt {
+				return res, err
+			}
+			return demote(res), nil
+		}
+		res, err := ops.ratio(toRat(a), toRat(b))
+		if i, isInt := res.(Int64); isInt && kind == kindBigInt {
+			return BigInt{v: big.NewInt(int64(i))}, err
+		}
+		return res, err
+
+	case kindRatio:
+		return ops.ratio(toRat(a), toRat(b))
+
+	case kindBigDecimal:
+		x, err := toDecimal(a)
+		if err != nil {
+			return nil, err
+		}
+		y, err := toDecimal(b)
+		if err != nil {
+			return nil, err
+		}
+		return ops.dec(x, y)
+
+	default:
+		return ops.f64(toFloat64(a), toFloat64(b))
+	}
+}
+
+// demote returns the BigInt as Int64 if it fits.
+func demote(v core.Any) core.Any {
+	if bi, ok := v.(BigInt); ok && bi.val().IsInt64() {
+		return Int64(bi.val().Int64())
+	}
+	return v
+}
+
+func ratioOrInt(r *big.Rat) core.Any {
+	if r.IsInt() {
+		return intOf(r.Num())
+	}
+	return Ratio{v: r}
+}
+
+func intOf(i *big.Int) core.Any {
+	if i.IsInt64() {
+		return Int64(i.Int64())
+	}
+	return BigInt{v: i}
+}
+
+func truncRat(r *big.Rat) *big.Int {
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+func toBigInt(v core.Any) *big.Int {
+	switch n := v.(type) {
+	case Int64:
+		return big.NewInt(int64(n))
+	case BigInt:
+		return n.val()
+	}
+	return nil
+}
+
+// toRat returns the exact value of the number as big.Rat. v must be a finite
+// number.
+func toRat(v core.Any) *big.Rat {
+	switch n := v.(type) {
+	case Int64:
+		return new(big.Rat).SetInt64(int64(n))
+	case BigInt:
+		return new(big.Rat).SetInt(n.val())
+	case Ratio:
+		return n.val()
+	case BigDecimal:
+		return n.Rat()
+	case Float64:
+		return new(big.Rat).SetFloat64(float64(n))
+	}
+	return nil
+}
+
+func toDecimal(v core.Any) (BigDecimal, error) {
+	switch n := v.(type) {
+	case BigDecimal:
+		return n, nil
+	case Int64, BigInt:
+		return BigDecimal{unscaled: toBigInt(n)}, nil
+	}
+	return ratToDecimal(toRat(v), 0)
+}
+
+func toFloat64(v core.Any) float64 {
+	if f, ok := v.(Float64); ok {
+		return float64(f)
+	}
+	f, _ := toRat(v).Float64()
+	return f
+}
+
+// ratToDecimal returns the exact decimal value of r with at least minScale
+// digits after the decimal point. Fails if r has no exact decimal value
+// (e.g., 1/3).
+func ratToDecimal(r *big.Rat, minScale int) (BigDecimal, error) {
+	d := new(big.Int).Set(r.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+
+	// the decimal expansion terminates only if the denominator has no prime
+	// factors other than 2 and 5.
+	var twos, fives int
+	q, m := new(big.Int), new(big.Int)
+	for q.QuoRem(d, two, m); m.Sign() == 0; q.QuoRem(d, two, m) {
+		d.Set(q)
+		twos++
+	}
+	for q.QuoRem(d, five, m); m.Sign() == 0; q.QuoRem(d, five, m) {
+		d.Set(q)
+		fives++
+	}
+	if d.Cmp(big.NewInt(1)) != 0 {
+		return BigDecimal{}, core.Error{
+			Cause:   ErrArithmetic,
+			Message: fmt.Sprintf("non-terminating decimal expansion of %s", r.RatString()),
+		}
+	}
+
+	scale := twos
+	if fives > scale {
+		scale = fives
+	}
+	if minScale > scale {
+		scale = minScale
+	}
+
+	u := new(big.Int).Mul(r.Num(), pow10(scale))
+	return BigDecimal{unscaled: u.Quo(u, r.Denom()), scale: scale}, nil
+}
+
+// alignScale returns x and y with the same scale (the larger of the two).
+func alignScale(x, y BigDecimal) (BigDecimal, BigDecimal) {
+	if x.scale < y.scale {
+		x = BigDecimal{unscaled: new(big.Int).Mul(x.val(), pow10(y.scale-x.scale)), scale: y.scale}
+	} else if y.scale < x.scale {
+		y = BigDecimal{unscaled: new(big.Int).Mul(y.val(), pow10(x.scale-y.scale)), scale: x.scale}
+	}
+	return x, y
+}
+
+func sign(v core.Any) (int, error) {
+	switch n := v.(type) {
+	case Int64:
+		return compareInt64(int64(n), 0), nil
+	case Float64:
+		return compareNumbers(n, Float64(0))
+	}
+	if !IsNumber(v) {
+		return 0, notNumber("sign", v)
+	}
+	return toRat(v).Sign(), nil
+}
+
+func compareInt64(x, y int64) int {
+	switch {
+	case x > y:
+		return 1
+	case x < y:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func compareNonFinite(f float64) (int, error) {
+	switch {
+	case math.IsInf(f, 1):
+		return 1, nil
+	case math.IsInf(f, -1):
+		return -1, nil
+	default:
+		return 0, core.ErrIncomparable
+	}
+}
+
+func isFinite(f float64) bool { return !math.IsInf(f, 0) && !math.IsNaN(f) }
+
+func errDivideByZero() error {
+	return core.Error{Cause: ErrArithmetic, Message: "divide by zero"}
+}
+
+func notNumber(op string, v core.Any) error {
+	return core.Error{
+		Cause:   ErrNotNumber,
+		Message: fmt.Sprintf("%s: value of type '%s' is not a number", op, reflect.TypeOf(v)),
+	}
+}
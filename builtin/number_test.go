@@ -0,0 +1,163 @@
+package builtin
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArithmetic(t *testing.T) {
+	t.Parallel()
+
+	bigInt := func(s string) BigInt {
+		v, _ := new(big.Int).SetString(s, 10)
+		return NewBigInt(v)
+	}
+	ratio := func(a, b int64) Ratio { return NewRatio(big.NewRat(a, b)) }
+	dec := func(s string) BigDecimal { return mustDecimal(t, s) }
+
+	table := []struct {
+		title   string
+		fn      func(a, b core.Any) (core.Any, error)
+		a, b    core.Any
+		want    core.Any
+		wantErr error
+	}{
+		{title: "Add_Int64", fn: Add, a: Int64(1), b: Int64(2), want: Int64(3)},
+		{title: "Add_Int64Overflow", fn: Add, a: Int64(math.MaxInt64), b: Int64(1), want: bigInt("9223372036854775808")},
+		{title: "Add_BigIntContagion", fn: Add, a: Int64(1), b: bigInt("1"), want: bigInt("2")},
+		{title: "Add_Ratio", fn: Add, a: ratio(1, 3), b: ratio(1, 6), want: ratio(1, 2)},
+		{title: "Add_RatioToInt", fn: Add, a: ratio(1, 2), b: ratio(1, 2), want: Int64(1)},
+		{title: "Add_Decimal", fn: Add, a: dec("1.50"), b: Int64(2), want: dec("3.50")},
+		{title: "Add_DecimalAndRatio", fn: Add, a: dec("1.5"), b: ratio(1, 4), want: dec("1.75")},
+		{title: "Add_Float", fn: Add, a: Int64(1), b: Float64(0.5), want: Float64(1.5)},
+		{title: "Add_NotNumber", fn: Add, a: Int64(1), b: String("1"), wantErr: ErrNotNumber},
+		{title: "Sub_Int64Overflow", fn: Sub, a: Int64(math.MinInt64), b: Int64(1), want: bigInt("-9223372036854775809")},
+		{title: "Sub_Decimal", fn: Sub, a: dec("0.30"), b: dec("0.1"), want: dec("0.20")},
+		{title: "Sub_BigIntContagion", fn: Sub, a: bigInt("9223372036854775808"), b: Int64(1), want: bigInt("9223372036854775807")},
+		{title: "Mul_Int64", fn: Mul, a: Int64(-3), b: Int64(4), want: Int64(-12)},
+		{title: "Mul_Int64Overflow", fn: Mul, a: Int64(math.MinInt64), b: Int64(-1), want: bigInt("9223372036854775808")},
+		{title: "Mul_Decimal", fn: Mul, a: dec("1.5"), b: dec("0.25"), want: dec("0.375")},
+		{title: "Mul_Float", fn: Mul, a: ratio(1, 2), b: Float64(3), want: Float64(1.5)},
+		{title: "Div_Exact", fn: Div, a: Int64(6), b: Int64(3), want: Int64(2)},
+		{title: "Div_Ratio", fn: Div, a: Int64(1), b: Int64(3), want: ratio(1, 3)},
+		{title: "Div_BigInt", fn: Div, a: bigInt("6"), b: Int64(3), want: bigInt("2")},
+		{title: "Div_ByZero", fn: Div, a: Int64(1), b: Int64(0), wantErr: ErrArithmetic},
+		{title: "Div_Decimal", fn: Div, a: dec("10.00"), b: Int64(4), want: dec("2.50")},
+		{title: "Div_DecimalNonTerminating", fn: Div, a: dec("1"), b: Int64(3), wantErr: ErrArithmetic},
+		{title: "Div_DecimalByZero", fn: Div, a: dec("1"), b: dec("0.0"), wantErr: ErrArithmetic},
+		{title: "Div_FloatByZero", fn: Div, a: Float64(1), b: Int64(0), want: Float64(math.Inf(1))},
+		{title: "Quot_Int64", fn: Quot, a: Int64(-7), b: Int64(2), want: Int64(-3)},
+		{title: "Quot_ByZero", fn: Quot, a: Int64(7), b: Int64(0), wantErr: ErrArithmetic},
+		{title: "Quot_Ratio", fn: Quot, a: ratio(7, 2), b: Int64(2), want: Int64(1)},
+		{title: "Quot_Float", fn: Quot, a: Float64(7.5), b: Int64(2), want: Float64(3)},
+		{title: "Rem_Int64", fn: Rem, a: Int64(-7), b: Int64(2), want: Int64(-1)},
+		{title: "Rem_MinInt64", fn: Rem, a: Int64(math.MinInt64), b: Int64(-1), want: Int64(0)},
+		{title: "Rem_Decimal", fn: Rem, a: dec("7.5"), b: Int64(2), want: dec("1.5")},
+		{title: "Rem_Float", fn: Rem, a: Float64(7.5), b: Int64(2), want: Float64(1.5)},
+		{title: "Mod_Int64", fn: Mod, a: Int64(-7), b: Int64(2), want: Int64(1)},
+		{title: "Mod_NegativeDivisor", fn: Mod, a: Int64(7), b: Int64(-2), want: Int64(-1)},
+		{title: "Mod_Ratio", fn: Mod, a: ratio(-1, 2), b: Int64(1), want: ratio(1, 2)},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := tt.fn(tt.a, tt.b)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), "wantErr=%#v\ngotErr=%#v", tt.wantErr, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.IsType(t, tt.want, got)
+			eq, err := core.Eq(tt.want, got)
+			assert.NoError(t, err)
+			assert.True(t, eq, "want=%v\ngot=%v", tt.want, got)
+			if d, ok := tt.want.(BigDecimal); ok {
+				assert.Equal(t, d.Scale(), got.(BigDecimal).Scale())
+			}
+		})
+	}
+}
+
+func TestNeg(t *testing.T) {
+	v, err := Neg(Int64(math.MinInt64))
+	assert.NoError(t, err)
+	testSExpr(t, v.(BigInt), "9223372036854775808N")
+
+	v, err = Neg(mustDecimal(t, "1.50"))
+	assert.NoError(t, err)
+	testSExpr(t, v.(BigDecimal), "-1.50M")
+
+	_, err = Neg(Keyword("a"))
+	assert.True(t, errors.Is(err, ErrNotNumber))
+}
+
+func TestNumberEquality(t *testing.T) {
+	t.Parallel()
+
+	one := []core.Any{
+		Int64(1),
+		Float64(1),
+		NewBigInt(big.NewInt(1)),
+		mustDecimal(t, "1.00"),
+		NewRatio(big.NewRat(2, 2)),
+	}
+	half := []core.Any{
+		Float64(0.5),
+		mustDecimal(t, "0.50"),
+		NewRatio(big.NewRat(1, 2)),
+	}
+
+	for _, group := range [][]core.Any{one, half} {
+		for _, a := range group {
+			for _, b := range group {
+				eq, err := core.Eq(a, b)
+				assert.NoError(t, err)
+				assert.True(t, eq, "%#v == %#v", a, b)
+
+				ha, err := hashOf(a)
+				assert.NoError(t, err)
+				hb, err := hashOf(b)
+				assert.NoError(t, err)
+				assert.Equal(t, ha, hb, "hash(%#v) == hash(%#v)", a, b)
+			}
+		}
+	}
+
+	for _, a := range one {
+		for _, b := range half {
+			eq, err := core.Eq(a, b)
+			assert.NoError(t, err)
+			assert.False(t, eq, "%#v != %#v", a, b)
+		}
+	}
+
+	eq, err := core.Eq(Float64(0.1), mustDecimal(t, "0.1"))
+	assert.NoError(t, err)
+	assert.False(t, eq, "float 0.1 is not exactly 0.1")
+
+	testComp(t, Int64(1), Float64(math.Inf(1)), -1, nil)
+	testComp(t, Float64(math.Inf(-1)), NewBigInt(big.NewInt(1)), -1, nil)
+	testComp(t, Int64(1), Float64(math.NaN()), 0, core.ErrIncomparable)
+	testComp(t, Float64(math.NaN()), Float64(5), 0, core.ErrIncomparable)
+	testComp(t, Float64(1), Float64(math.NaN()), 0, core.ErrIncomparable)
+	testComp(t, Float64(math.Inf(1)), Float64(math.Inf(1)), 0, nil)
+
+	for _, other := range []core.Any{Float64(5), Float64(math.NaN()), Int64(5)} {
+		eq, err = core.Eq(Float64(math.NaN()), other)
+		assert.NoError(t, err)
+		assert.False(t, eq, "NaN != %#v", other)
+	}
+	testComp(t, NewRatio(big.NewRat(1, 3)), Float64(0.3), 1, nil)
+
+	m, err := NewMap(Int64(1), String("one"))
+	assert.NoError(t, err)
+	v, err := m.EntryAt(Float64(1))
+	assert.NoError(t, err)
+	assert.Equal(t, String("one"), v)
+}
@@ -0,0 +1,121 @@
+package builtin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spy16/slurp/core"
+)
+
+// ErrNotAllowed is returned by Analyzer when a form uses a special form or
+// a global binding that is not allowed by its Profile.
+var ErrNotAllowed = errors.New("not allowed")
+
+// Profile restricts the special forms and the global bindings (i.e., root
+// bindings and namespace vars) that can be used by the forms analyzed by an
+// Analyzer. Profiles can be used to make
+// sure untrusted code cannot spawn goroutines, mutate globals etc.
+type Profile struct {
+	// Name of the profile used in the errors.
+	Name string
+
+	// Specials are the names of the special forms allowed. All special
+	// forms are allowed if nil.
+	Specials []string
+
+	// Bindings are the names of the global bindings that can be reached.
+	// Namespace vars can be allowed using the name or the qualified name
+	// (e.g., user/x). All global bindings can be reached if nil.
+	Bindings []string
+}
+
+// WithBindings returns a copy of the profile that allows reaching the global
+// bindings with given names in addition to the ones already allowed.
+func (p Profile) WithBindings(names ...string) Profile {
+	p.Bindings = append(append([]string{}, p.Bindings...), names...)
+	return p
+}
+
+// AllowsSpecial returns true if the special form with given name is allowed.
+func (p Profile) AllowsSpecial(name string) bool {
+	return p.Specials == nil || contains(p.Specials, name)
+}
+
+// AllowsBinding returns true if the global binding with given name can be
+// reached.
+func (p Profile) AllowsBinding(name string) bool {
+	return p.Bindings == nil || contains(p.Bindings, name)
+}
+
+// checkSpecial returns error if the special form is not allowed by the
+// profile (if any) of the analyzer.
+func (ba Analyzer) checkSpecial(name string) error {
+	if ba.Profile == nil || ba.Profile.AllowsSpecial(name) {
+		return nil
+	}
+
+	return core.Error{
+		Cause:   ErrNotAllowed,
+		Message: fmt.Sprintf("special form '%s' is not allowed in profile '%s'", name, ba.Profile.Name),
+	}
+}
+
+// checkBinding returns error if the symbol refers to a global binding that
+// is not allowed by the profile (if any) of the analyzer. Symbols bound in
+// the local scope (e.g., using let) are always allowed.
+func (ba Analyzer) checkBinding(env core.Env, sym Symbol) error {
+	if ba.Profile == nil || env == nil || ba.Profile.AllowsBinding(string(sym)) {
+		return nil
+	}
+
+	name, global := globalName(env, sym)
+	if !global || ba.Profile.AllowsBinding(name) {
+		return nil
+	}
+
+	return core.Error{
+		Cause:   ErrNotAllowed,
+		Message: fmt.Sprintf("binding '%s' is not allowed in profile '%s'", sym, ba.Profile.Name),
+	}
+}
+
+// globalName returns the qualified name (e.g., user/x) of the namespace var
+// or the name of the root binding the symbol refers to. Returns false if
+// the symbol is bound in the local scope or not bound at all.
+func globalName(env core.Env, sym Symbol) (string, bool) {
+	if nsName, name, ok := splitQualified(sym); ok {
+		if ns, err := FindNS(env, nsName); err == nil {
+			if owner, found := ns.Owner(name); found {
+				return owner + "/" + name, true
+			}
+			return "", false
+		}
+		// not a namespace. resolve as a regular symbol.
+	}
+
+	for local := env; local.Parent() != nil; local = local.Parent() {
+		if _, err := local.Resolve(string(sym)); err == nil {
+			return "", false
+		}
+	}
+
+	if ns := CurrentNS(env); ns != nil {
+		if owner, found := ns.Owner(string(sym)); found {
+			return owner + "/" + string(sym), true
+		}
+	}
+
+	if _, err := core.Root(env).Resolve(string(sym)); err != nil {
+		return "", false
+	}
+	return string(sym), true
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -5,16 +5,23 @@ import (
 )
 
 var (
-	_ core.Any = (*LinkedList)(nil)
-	_ core.Seq = (*LinkedList)(nil)
+	_ core.Any  = (*LinkedList)(nil)
+	_ core.Seq  = (*LinkedList)(nil)
+	_ core.Meta = (*LinkedList)(nil)
+
+	_ core.Uncounted = (*LinkedList)(nil)
+	_ core.Reducible = (*LinkedList)(nil)
 )
 
 // Cons returns a new seq with `v` added as the first and `seq` as the rest.
-// seq can be nil as well.
+// seq can be nil as well. If the count of seq is unknown (e.g., a LazySeq),
+// the count of the new seq is unknown as well and seq is not realized.
 func Cons(v core.Any, seq core.Seq) (core.Seq, error) {
 	newSeq := &LinkedList{first: v, rest: seq, count: 1}
 
-	if seq != nil {
+	if !core.IsCounted(seq) {
+		newSeq.count = -1
+	} else if seq != nil {
 		cnt, err := seq.Count()
 		if err != nil {
 			return nil, err
@@ -44,9 +51,27 @@ func NewList(items ...core.Any) core.Seq {
 
 // LinkedList implements an immutable Seq using linked-list data structure.
 type LinkedList struct {
-	count int
+	count int // -1 if the rest is not counted.
 	first core.Any
 	rest  core.Seq
+	meta  core.Map
+}
+
+// Meta returns the metadata of the list.
+func (ll *LinkedList) Meta() core.Map {
+	if ll == nil {
+		return nil
+	}
+	return ll.meta
+}
+
+// WithMeta returns a copy of the list with the metadata replaced.
+func (ll *LinkedList) WithMeta(meta core.Map) core.Any {
+	clone := LinkedList{meta: meta}
+	if ll != nil {
+		clone.count, clone.first, clone.rest = ll.count, ll.first, ll.rest
+	}
+	return &clone
 }
 
 // SExpr returns a valid s-expression for LinkedList.
@@ -66,11 +91,14 @@ func (ll *LinkedList) Conj(items ...core.Any) (res core.Seq, err error) {
 
 	for _, item := range items {
 		if res, err = Cons(item, res); err != nil {
-			break
+			return nil, err
 		}
 	}
 
-	return res, err
+	if ll.Meta() != nil {
+		res = res.(*LinkedList).WithMeta(ll.meta).(core.Seq)
+	}
+	return res, nil
 }
 
 // First returns the head or first item of the list.
@@ -89,10 +117,81 @@ func (ll *LinkedList) Next() (core.Seq, error) {
 	return ll.rest, nil
 }
 
-// Count returns the number of the list.
+// Count returns the number of the list. If the rest of the list is not
+// counted, it is realized to count the items.
 func (ll *LinkedList) Count() (int, error) {
 	if ll == nil {
 		return 0, nil
+	} else if ll.count >= 0 {
+		return ll.count, nil
+	}
+
+	cnt, err := ll.rest.Count()
+	return cnt + 1, err
+}
+
+// CountUnknown returns true if the rest of the list is not counted.
+func (ll *LinkedList) CountUnknown() bool { return ll != nil && ll.count < 0 }
+
+// Reduce reduces the items of the list in order using f. If the rest of the
+// list is not a LinkedList (e.g., a LazySeq), it is reduced using core.Reduce.
+func (ll *LinkedList) Reduce(init core.Any, f core.ReduceFunc) (core.Any, error) {
+	acc := init
+	for ll != nil && ll.first != nil {
+		var done bool
+		var err error
+		if acc, done, err = f(acc, ll.first); err != nil {
+			return nil, err
+		} else if done {
+			return acc, nil
+		}
+
+		next, ok := ll.rest.(*LinkedList)
+		if !ok {
+			return core.Reduce(ll.rest, acc, f)
+		}
+		ll = next
+	}
+	return acc, nil
+}
+
+// Iterator returns an iterator over the items of the list.
+func (ll *LinkedList) Iterator() core.Iterator { return &listIterator{ll: ll} }
+
+// listIterator walks the nodes of a list. If the rest of a node is not a
+// LinkedList, the iteration continues with an iterator of the rest.
+type listIterator struct {
+	ll   *LinkedList
+	rest core.Iterator
+	item core.Any
+}
+
+func (it *listIterator) Next() bool {
+	if it.rest != nil {
+		ok := it.rest.Next()
+		it.item = it.rest.Item()
+		return ok
+	}
+
+	if it.ll == nil || it.ll.first == nil {
+		it.item = nil
+		return false
+	}
+
+	it.item = it.ll.first
+	if next, ok := it.ll.rest.(*LinkedList); ok {
+		it.ll = next
+	} else {
+		it.ll, it.rest = nil, core.IteratorOf(it.ll.rest)
+	}
+	return true
+}
+
+func (it *listIterator) Item() core.Any { return it.item }
+
+func (it *listIterator) Err() error {
+	if it.rest != nil {
+		return it.rest.Err()
 	}
-	return ll.count, nil
+	return nil
 }
@@ -55,3 +55,44 @@ func TestCons(t *testing.T) {
 		})
 	}
 }
+
+func TestLinkedList_Reduce(t *testing.T) {
+	t.Parallel()
+
+	lazy := NewLazySeq(func() (core.Any, error) {
+		return NewList(Int64(3), Int64(4)), nil
+	})
+	list, err := Cons(Int64(1), lazy)
+	assert.NoError(t, err)
+	list, err = Cons(Int64(0), list)
+	assert.NoError(t, err)
+	want := []core.Any{Int64(0), Int64(1), Int64(3), Int64(4)}
+
+	var got []core.Any
+	res, err := list.(core.Reducible).Reduce(Int64(0), func(acc, item core.Any) (core.Any, bool, error) {
+		got = append(got, item)
+		return acc.(Int64) + item.(Int64), false, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(8), res)
+	assert.Equal(t, want, got)
+
+	res, err = list.(core.Reducible).Reduce(nil, func(_, item core.Any) (core.Any, bool, error) {
+		return item, true, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(0), res)
+
+	got = nil
+	it := list.(core.Reducible).Iterator()
+	for it.Next() {
+		got = append(got, it.Item())
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, want, got)
+
+	res, err = NewList().(core.Reducible).Reduce(Int64(1), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Int64(1), res)
+	assert.False(t, NewList().(core.Reducible).Iterator().Next())
+}
@@ -0,0 +1,126 @@
+package builtin
+
+import (
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	_ core.Set              = (*PersistentSet)(nil)
+	_ core.EqualityProvider = (*PersistentSet)(nil)
+	_ core.Meta             = (*PersistentSet)(nil)
+)
+
+// EmptySet is the zero-value PersistentSet.
+var EmptySet = PersistentSet{}
+
+// PersistentSet is an immutable core.Set implementation backed by a
+// PersistentMap.
+type PersistentSet struct {
+	m    PersistentMap
+	meta core.Map
+}
+
+// NewSet builds a PersistentSet from the given values. Duplicate values
+// are ignored.
+func NewSet(items ...core.Any) (PersistentSet, error) {
+	return EmptySet.conj(items...)
+}
+
+// Count returns the number of elements in the Set.
+func (s PersistentSet) Count() (int, error) { return s.m.cnt, nil }
+
+// Meta returns the metadata of the set.
+func (s PersistentSet) Meta() core.Map { return s.meta }
+
+// WithMeta returns the set with the metadata replaced.
+func (s PersistentSet) WithMeta(meta core.Map) core.Any {
+	s.meta = meta
+	return s
+}
+
+// Conj returns a new Set with the given values added.
+func (s PersistentSet) Conj(vs ...core.Any) (core.Set, error) {
+	res, err := s.conj(vs...)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s PersistentSet) conj(vs ...core.Any) (PersistentSet, error) {
+	m := s.m
+	for _, v := range vs {
+		var err error
+		if m, err = m.assoc(v, v); err != nil {
+			return PersistentSet{}, err
+		}
+	}
+	return PersistentSet{m: m, meta: s.meta}, nil
+}
+
+// Disj returns a new Set with the given values removed.
+func (s PersistentSet) Disj(vs ...core.Any) (core.Set, error) {
+	var m core.Map = s.m
+	for _, v := range vs {
+		var err error
+		if m, err = m.Dissoc(v); err != nil {
+			return nil, err
+		}
+	}
+	return PersistentSet{m: m.(PersistentMap), meta: s.meta}, nil
+}
+
+// Contains returns true if the value is a member of the Set.
+func (s PersistentSet) Contains(v core.Any) (bool, error) { return s.m.HasKey(v) }
+
+// Seq returns a sequence of the elements in the Set. Order of the elements
+// is not defined.
+func (s PersistentSet) Seq() (core.Seq, error) {
+	if s.m.cnt == 0 {
+		return NewList(), nil
+	}
+
+	items := make([]core.Any, 0, s.m.cnt)
+	err := s.m.forEach(func(k, _ core.Any) (bool, error) {
+		items = append(items, k)
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return NewList(items...), nil
+}
+
+// Equals returns true if other is also a Set with the same elements.
+func (s PersistentSet) Equals(other core.Any) (bool, error) {
+	os, ok := other.(core.Set)
+	if !ok {
+		return false, nil
+	}
+
+	cnt, err := os.Count()
+	if err != nil || cnt != s.m.cnt {
+		return false, err
+	}
+
+	eq := true
+	err = s.m.forEach(func(k, _ core.Any) (bool, error) {
+		found, err := os.Contains(k)
+		eq = eq && found
+		return !eq, err
+	})
+	return eq, err
+}
+
+// SExpr returns a parsable s-expression for the Set.
+func (s PersistentSet) SExpr() (string, error) {
+	if s.m.cnt == 0 {
+		return "#{}", nil
+	}
+
+	seq, err := s.Seq()
+	if err != nil {
+		return "", err
+	}
+	return core.SeqString(seq, "#{", "}", " ")
+}
@@ -0,0 +1,107 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetIsHashable(t *testing.T) {
+	t.Parallel()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Error("PersistentSet is not hashable.")
+		}
+	}()
+
+	m := make(map[core.Set]struct{})
+	m[EmptySet] = struct{}{}
+}
+
+func TestEmptySet(t *testing.T) {
+	t.Parallel()
+
+	testSExpr(t, EmptySet, "#{}")
+
+	cnt, err := EmptySet.Count()
+	assert.NoError(t, err)
+	assert.Zero(t, cnt)
+
+	found, err := EmptySet.Contains(Keyword("foo"))
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestPersistentSet_Conj_Disj(t *testing.T) {
+	t.Parallel()
+
+	var s core.Set = EmptySet
+	for i := 0; i < size; i++ {
+		var err error
+		s, err = s.Conj(Int64(i), Int64(i))
+		require.NoError(t, err)
+	}
+
+	cnt, err := s.Count()
+	require.NoError(t, err)
+	require.Equal(t, size, cnt, "duplicate values must be ignored")
+
+	orig := s
+	for i := 0; i < size; i += 2 {
+		s, err = s.Disj(Int64(i))
+		require.NoError(t, err)
+	}
+
+	cnt, err = s.Count()
+	require.NoError(t, err)
+	assert.Equal(t, size/2, cnt)
+
+	for i := 0; i < size; i++ {
+		found, err := s.Contains(Int64(i))
+		require.NoError(t, err)
+		assert.Equal(t, i%2 != 0, found, "value %d", i)
+
+		found, err = orig.Contains(Int64(i))
+		require.NoError(t, err)
+		assert.True(t, found, "original set must not change (value %d)", i)
+	}
+}
+
+func TestPersistentSet_Seq(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewSet(Keyword("a"), Keyword("b"))
+	require.NoError(t, err)
+
+	seq, err := s.Seq()
+	require.NoError(t, err)
+
+	items, err := core.ToSlice(seq)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []core.Any{Keyword("a"), Keyword("b")}, items)
+}
+
+func TestPersistentSet_Equals(t *testing.T) {
+	t.Parallel()
+
+	s1, err := NewSet(Keyword("a"), Int64(1))
+	require.NoError(t, err)
+
+	s2, err := NewSet(Int64(1), Keyword("a"))
+	require.NoError(t, err)
+
+	s3, err := NewSet(Int64(1), Keyword("b"))
+	require.NoError(t, err)
+
+	eq, err := core.Eq(s1, s2)
+	assert.NoError(t, err)
+	assert.True(t, eq)
+
+	eq, err = core.Eq(s1, s3)
+	assert.NoError(t, err)
+	assert.False(t, eq)
+
+	testSExpr(t, mustSet(t, String("a")), `#{"a"}`)
+}
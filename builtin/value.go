@@ -47,21 +47,9 @@ type Int64 int64
 // SExpr returns a valid s-expression representing Int64.
 func (i64 Int64) SExpr() (string, error) { return i64.String(), nil }
 
-// Comp performs comparison against another Int64.
-func (i64 Int64) Comp(other core.Any) (int, error) {
-	if n, ok := other.(Int64); ok {
-		switch {
-		case i64 > n:
-			return 1, nil
-		case i64 < n:
-			return -1, nil
-		default:
-			return 0, nil
-		}
-	}
-
-	return 0, core.ErrIncomparable
-}
+// Comp performs comparison against another number. Numbers of different
+// types are compared by their exact values (i.e., 1 equals 1.0).
+func (i64 Int64) Comp(other core.Any) (int, error) { return compareNumbers(i64, other) }
 
 func (i64 Int64) String() string { return strconv.Itoa(int(i64)) }
 
@@ -71,21 +59,9 @@ type Float64 float64
 // SExpr returns a valid s-expression representing Float64.
 func (f64 Float64) SExpr() (string, error) { return f64.String(), nil }
 
-// Comp performs comparison against another Float64.
-func (f64 Float64) Comp(other core.Any) (int, error) {
-	if n, ok := other.(Float64); ok {
-		switch {
-		case f64 > n:
-			return 1, nil
-		case f64 < n:
-			return -1, nil
-		default:
-			return 0, nil
-		}
-	}
-
-	return 0, core.ErrIncomparable
-}
+// Comp performs comparison against another number. Numbers of different
+// types are compared by their exact values (i.e., 1.0 equals 1).
+func (f64 Float64) Comp(other core.Any) (int, error) { return compareNumbers(f64, other) }
 
 func (f64 Float64) String() string {
 	if math.Abs(float64(f64)) >= 1e16 {
@@ -151,7 +127,7 @@ func (sym Symbol) SExpr() (string, error) { return string(sym), nil }
 
 // Equals returns true if the other Value is also a symbol and has same Value.
 func (sym Symbol) Equals(other core.Any) (bool, error) {
-	otherSym, isSym := other.(Symbol)
+	otherSym, isSym := SymbolOf(other)
 	return isSym && (sym == otherSym), nil
 }
 
@@ -6,6 +6,7 @@ import (
 
 	"github.com/spy16/slurp/core"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNil(t *testing.T) {
@@ -22,6 +23,8 @@ func TestInt64(t *testing.T) {
 	testComp(t, v, v, 0, nil)
 	testComp(t, v, Int64(1), 1, nil)
 	testComp(t, v, Int64(10000), -1, nil)
+	testComp(t, v, Float64(100), 0, nil)
+	testComp(t, v, Float64(100.5), -1, nil)
 }
 
 func TestFloat64(t *testing.T) {
@@ -34,6 +37,8 @@ func TestFloat64(t *testing.T) {
 	testComp(t, v, v, 0, nil)
 	testComp(t, v, Float64(1), 1, nil)
 	testComp(t, v, Float64(10000), -1, nil)
+	testComp(t, v, Int64(100), 0, nil)
+	testComp(t, v, Int64(99), 1, nil)
 }
 
 func TestIsTruthy(t *testing.T) {
@@ -59,3 +64,15 @@ func testComp(t *testing.T, v core.Comparable, other core.Any, want int, wantErr
 
 	assert.Equal(t, want, got)
 }
+
+func mustDecimal(t *testing.T, s string) BigDecimal {
+	d, err := ParseBigDecimal(s)
+	require.NoError(t, err)
+	return d
+}
+
+func mustSet(t *testing.T, items ...core.Any) PersistentSet {
+	s, err := NewSet(items...)
+	require.NoError(t, err)
+	return s
+}
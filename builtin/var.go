@@ -0,0 +1,89 @@
+package builtin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/slurp/core"
+)
+
+var _ core.Ref = (*Var)(nil)
+
+// ErrNotDynamic is returned when rebinding a symbol that does not refer to
+// a dynamic var.
+var ErrNotDynamic = errors.New("not a dynamic var")
+
+// Var is a dynamic var. Value of a dynamic var can be rebound for the
+// dynamic extent of an evaluation (See WithBindings). ResolveExpr resolves
+// symbols referring to a var to the value bound in the context of the env
+// or to the root value if there is no binding.
+type Var struct {
+	name string
+	root core.Any
+}
+
+// NewVar returns a new dynamic var with the given name and root value.
+func NewVar(name string, root core.Any) *Var {
+	if root == nil {
+		root = Nil{}
+	}
+	return &Var{name: name, root: root}
+}
+
+// Name returns the name of the var.
+func (v *Var) Name() string { return v.name }
+
+// Root returns the root value of the var.
+func (v *Var) Root() core.Any { return v.root }
+
+// Deref returns the value bound to the var in the context or the root value
+// if the var is not bound in the context.
+func (v *Var) Deref(ctx context.Context) (core.Any, error) {
+	for f, _ := ctx.Value(bindingsKey{}).(*bindings); f != nil; f = f.parent {
+		if val, found := f.vals[v]; found {
+			return val, nil
+		}
+	}
+	return v.root, nil
+}
+
+// SExpr returns the s-expression for the var.
+func (v *Var) SExpr() (string, error) { return "#'" + v.name, nil }
+
+// WithBindings returns a copy of the context in which the vars are bound
+// to the given values. Bindings in the parent context that are not in vals
+// are retained. Since the context of the env is conveyed to the goroutines
+// started by the go form, the bindings are visible to them as well.
+func WithBindings(ctx context.Context, vals map[*Var]core.Any) context.Context {
+	parent, _ := ctx.Value(bindingsKey{}).(*bindings)
+	return context.WithValue(ctx, bindingsKey{}, &bindings{vals: vals, parent: parent})
+}
+
+// ResolveVar resolves the symbol in the env and returns the dynamic var it
+// refers to. Returns ErrNotDynamic if the symbol refers to some other value.
+func ResolveVar(env core.Env, sym Symbol) (*Var, error) {
+	v, err := ResolveExpr{Symbol: sym}.resolve(env)
+	if err != nil {
+		return nil, err
+	}
+
+	dv, ok := v.(*Var)
+	if !ok {
+		return nil, core.Error{
+			Cause:   ErrNotDynamic,
+			Message: fmt.Sprintf("cannot rebind '%s' of type '%s'", sym, reflect.TypeOf(v)),
+		}
+	}
+	return dv, nil
+}
+
+type bindingsKey struct{}
+
+// bindings is a frame of dynamic var bindings. Frames are never modified
+// once created and hence can be shared between goroutines.
+type bindings struct {
+	vals   map[*Var]core.Any
+	parent *bindings
+}
@@ -0,0 +1,52 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVar(t *testing.T) {
+	t.Parallel()
+
+	x, y := NewVar("user/x", Int64(1)), NewVar("user/y", nil)
+	testSExpr(t, x, "#'user/x")
+	assert.Equal(t, "user/x", x.Name())
+	assert.Equal(t, Nil{}, y.Root())
+
+	outer := WithBindings(context.Background(), map[*Var]core.Any{x: Int64(2), y: Int64(3)})
+	inner := WithBindings(outer, map[*Var]core.Any{x: Int64(4)})
+
+	for ctx, want := range map[context.Context][2]core.Any{
+		context.Background(): {Int64(1), Nil{}},
+		outer:                {Int64(2), Int64(3)},
+		inner:                {Int64(4), Int64(3)},
+	} {
+		got, err := x.Deref(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, want[0], got)
+
+		got, err = y.Deref(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, want[1], got)
+	}
+}
+
+func TestResolveVar(t *testing.T) {
+	t.Parallel()
+
+	x := NewVar("x", Int64(1))
+	env := core.New(map[string]core.Any{"x": x, "y": Int64(1)})
+
+	got, err := ResolveVar(env, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, x, got)
+
+	_, err = ResolveVar(env, "y")
+	assert.ErrorIs(t, err, ErrNotDynamic)
+
+	_, err = ResolveVar(env, "z")
+	assert.ErrorIs(t, err, core.ErrNotFound)
+}
@@ -10,6 +10,13 @@ import (
 var (
 	_ core.Vector = (*PersistentVector)(nil)
 	_ core.Vector = (*TransientVector)(nil)
+	_ core.Meta   = (*PersistentVector)(nil)
+
+	_ core.EqualityProvider = (*PersistentVector)(nil)
+
+	_ core.Reducible = (*PersistentVector)(nil)
+	_ core.Reducible = (*TransientVector)(nil)
+	_ core.Reducible = (*chunkedSeq)(nil)
 )
 
 const (
@@ -37,6 +44,7 @@ var (
 type PersistentVector struct {
 	cnt, shift int
 	root, tail *node
+	meta       core.Map
 }
 
 // NewVector builds a PersistentVector efficiently.
@@ -76,6 +84,47 @@ func (v PersistentVector) Transient() *TransientVector {
 // Count returns the number of elements contained in the Vector.
 func (v PersistentVector) Count() (int, error) { return v.cnt, nil }
 
+// Meta returns the metadata of the vector.
+func (v PersistentVector) Meta() core.Map { return v.meta }
+
+// WithMeta returns the vector with the metadata replaced.
+func (v PersistentVector) WithMeta(meta core.Map) core.Any {
+	v.meta = meta
+	return v
+}
+
+// Equals returns true if the other value is also a vector with the same
+// entries in the same order.
+func (v PersistentVector) Equals(other core.Any) (bool, error) {
+	ov, ok := other.(core.Vector)
+	if !ok {
+		return false, nil
+	}
+
+	cnt, err := ov.Count()
+	if err != nil || cnt != v.cnt {
+		return false, err
+	}
+
+	for i := 0; i < v.cnt; i++ {
+		a, err := v.EntryAt(i)
+		if err != nil {
+			return false, err
+		}
+
+		b, err := ov.EntryAt(i)
+		if err != nil {
+			return false, err
+		}
+
+		if eq, err := keyEq(a, b); err != nil || !eq {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
 // SExpr returns a parsable s-expression for the Vector.
 func (v PersistentVector) SExpr() (string, error) {
 	if v.cnt == 0 {
@@ -129,6 +178,7 @@ func (v PersistentVector) Assoc(i int, val core.Any) (core.Vector, error) {
 		return nil, err
 	}
 
+	vec.meta = v.meta
 	return vec, nil
 }
 
@@ -177,12 +227,13 @@ func (v PersistentVector) Conj(vs ...core.Any) (core.Vector, error) { return v.C
 
 // Cons appends a value to the Vector.
 func (v PersistentVector) Cons(vs ...core.Any) (core.Vector, error) {
+	var res PersistentVector
 	switch len(vs) {
 	case 0:
 		return v, nil
 
 	case 1:
-		return v.cons(vs[0]), nil
+		res = v.cons(vs[0])
 
 	default:
 		head, vs := vs[0], vs[1:]
@@ -190,9 +241,11 @@ func (v PersistentVector) Cons(vs ...core.Any) (core.Vector, error) {
 		for _, val := range vs {
 			_ = t.cons(val)
 		}
-		return t.Persistent(), nil
-
+		res = t.Persistent()
 	}
+
+	res.meta = v.meta
+	return res, nil
 }
 
 func (v PersistentVector) cons(val core.Any) PersistentVector {
@@ -268,8 +321,18 @@ func (v PersistentVector) pushTail(level int, parent, tailNode *node) *node {
 
 // Pop returns a copy of the Vector without its last element.
 func (v PersistentVector) Pop() (core.Vector, error) {
+	res, err := v.pop()
+	if err != nil {
+		return nil, err
+	}
+
+	res.meta = v.meta
+	return res, nil
+}
+
+func (v PersistentVector) pop() (PersistentVector, error) {
 	if v.cnt == 0 {
-		return nil, errors.New("cannot pop from empty vector")
+		return PersistentVector{}, errors.New("cannot pop from empty vector")
 	}
 
 	if v.cnt == 1 {
@@ -343,6 +406,61 @@ func (v PersistentVector) popTail(level int, n *node) *node {
 // Note that the resulting Seq type has LinkedList semantics for Conj().
 func (v PersistentVector) Seq() (core.Seq, error) { return newChunkedSeq(v, 0, 0), nil }
 
+// Reduce reduces the entries of the vector in order using f. Entries are
+// read directly from the leaf nodes of the vector.
+func (v PersistentVector) Reduce(init core.Any, f core.ReduceFunc) (core.Any, error) {
+	return v.reduceFrom(0, init, f)
+}
+
+// Iterator returns an iterator over the entries of the vector.
+func (v PersistentVector) Iterator() core.Iterator { return &vectorIterator{vec: v} }
+
+func (v PersistentVector) reduceFrom(i int, acc core.Any, f core.ReduceFunc) (core.Any, error) {
+	for i < v.cnt {
+		n, err := v.nodeFor(i)
+		if err != nil {
+			return nil, err
+		}
+
+		for j := i & mask; j < width && i < v.cnt; j, i = j+1, i+1 {
+			var done bool
+			if acc, done, err = f(acc, n.array[j]); err != nil {
+				return nil, err
+			} else if done {
+				return acc, nil
+			}
+		}
+	}
+	return acc, nil
+}
+
+// vectorIterator iterates over the entries of a vector starting at index i.
+// The leaf node is looked up only when the iteration enters it.
+type vectorIterator struct {
+	vec  PersistentVector
+	node *node
+	i    int
+	item core.Any
+}
+
+func (it *vectorIterator) Next() bool {
+	if it.i >= it.vec.cnt {
+		it.item = nil
+		return false
+	}
+
+	if it.node == nil || it.i&mask == 0 {
+		it.node, _ = it.vec.nodeFor(it.i)
+	}
+	it.item = it.node.array[it.i&mask]
+	it.i++
+	return true
+}
+
+func (it *vectorIterator) Item() core.Any { return it.item }
+
+func (it *vectorIterator) Err() error { return nil }
+
 type node struct {
 	len   int
 	array [width]interface{}
@@ -384,6 +502,9 @@ func newChunkedSeq(v PersistentVector, i, offset int) chunkedSeq {
 	}
 }
 
+// SExpr returns a list-like s-expression for the remaining items.
+func (cs chunkedSeq) SExpr() (string, error) { return core.SeqString(cs, "(", ")", " ") }
+
 func (cs chunkedSeq) Count() (int, error) { return cs.vec.cnt - (cs.i + cs.offset), nil }
 
 func (cs chunkedSeq) First() (core.Any, error) { return cs.node.array[cs.offset], nil }
@@ -409,6 +530,14 @@ func (cs chunkedSeq) chunkedNext() (core.Seq, error) {
 	return nil, nil
 }
 
+func (cs chunkedSeq) Reduce(init core.Any, f core.ReduceFunc) (core.Any, error) {
+	return cs.vec.reduceFrom(cs.i+cs.offset, init, f)
+}
+
+func (cs chunkedSeq) Iterator() core.Iterator {
+	return &vectorIterator{vec: cs.vec, i: cs.i + cs.offset}
+}
+
 func (cs chunkedSeq) Conj(items ...core.Any) (_ core.Seq, err error) {
 	i := cs.vec.cnt
 
@@ -439,6 +568,16 @@ func (t TransientVector) Count() (int, error) { return t.cnt, nil }
 // Seq returns a list-like representation of the vector.  Conj has LinkedList semantics.
 func (t TransientVector) Seq() (core.Seq, error) { return PersistentVector(t).Seq() }
 
+// Reduce reduces the entries of the vector in order using f. The vector must
+// not be modified during the reduction.
+func (t TransientVector) Reduce(init core.Any, f core.ReduceFunc) (core.Any, error) {
+	return PersistentVector(t).Reduce(init, f)
+}
+
+// Iterator returns an iterator over the entries of the vector. The vector
+// must not be modified during the iteration.
+func (t TransientVector) Iterator() core.Iterator { return PersistentVector(t).Iterator() }
+
 // Conj conjoins a set of values by repeatedly calling t.Cons.
 func (t *TransientVector) Conj(vs ...core.Any) (core.Vector, error) { return t.Cons(vs...) }
 
@@ -405,6 +405,106 @@ func TestTransientVector(t *testing.T) {
 	})
 }
 
+func TestPersistentVector_Reduce(t *testing.T) {
+	t.Parallel()
+
+	const n = 100 // spans multiple leaf nodes and the tail.
+	items := make([]core.Any, n)
+	for i := range items {
+		items[i] = Int64(i)
+	}
+	vec := NewVector(items...)
+
+	sum := func(acc, item core.Any) (core.Any, bool, error) {
+		return acc.(Int64) + item.(Int64), false, nil
+	}
+
+	t.Run("Reduce", func(t *testing.T) {
+		got, err := vec.Reduce(Int64(0), sum)
+		assert.NoError(t, err)
+		assert.Equal(t, Int64(n*(n-1)/2), got)
+
+		got, err = vec.Transient().Reduce(Int64(0), sum)
+		assert.NoError(t, err)
+		assert.Equal(t, Int64(n*(n-1)/2), got)
+
+		got, err = EmptyVector.Reduce(Int64(0), sum)
+		assert.NoError(t, err)
+		assert.Equal(t, Int64(0), got)
+	})
+
+	t.Run("EarlyTermination", func(t *testing.T) {
+		calls := 0
+		got, err := vec.Reduce(nil, func(acc, item core.Any) (core.Any, bool, error) {
+			calls++
+			return item, item == Int64(40), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, Int64(40), got)
+		assert.Equal(t, 41, calls)
+	})
+
+	t.Run("Iterator", func(t *testing.T) {
+		var got []core.Any
+		it := vec.Iterator()
+		for it.Next() {
+			got = append(got, it.Item())
+		}
+		assert.NoError(t, it.Err())
+		assert.Equal(t, items, got)
+		assert.False(t, it.Next())
+	})
+
+	t.Run("SeqSExpr", func(t *testing.T) {
+		seq, _ := NewVector(Int64(1), Int64(2), Int64(3)).Seq()
+		seq, _ = seq.Next()
+		testSExpr(t, seq.(core.SExpressable), "(2 3)")
+	})
+
+	t.Run("Seq", func(t *testing.T) {
+		var seq core.Seq
+		seq, _ = vec.Seq()
+		for i := 0; i < 35; i++ {
+			seq, _ = seq.Next()
+		}
+
+		got, err := core.ToSlice(seq)
+		assert.NoError(t, err)
+		assert.Equal(t, items[35:], got)
+
+		got = nil
+		it := core.IteratorOf(seq)
+		for it.Next() {
+			got = append(got, it.Item())
+		}
+		assert.Equal(t, items[35:], got)
+	})
+}
+
+func BenchmarkVector_Iteration(b *testing.B) {
+	items := make([]core.Any, size)
+	for i := range items {
+		items[i] = Int64(i)
+	}
+	seq, _ := NewVector(items...).Seq()
+
+	b.Run("SeqWalk", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for s := seq; s != nil; s, _ = s.Next() {
+				_, _ = s.First()
+			}
+		}
+	})
+
+	b.Run("ForEach", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = core.ForEach(seq, func(_ core.Any) (bool, error) { return false, nil })
+		}
+	})
+}
+
 func BenchmarkVector(b *testing.B) {
 	for name, runner := range map[string]func(*testing.B){
 		"PersistentVector_NoTransient":   runBenchmarks(b, new(persistentUnoptimized)),
@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrBudgetExceeded is returned when an evaluation exceeds one of the limits
+// of its Budget.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Budget limits the resources an evaluation can consume. A zero value for
+// any of the limits means no limit.
+type Budget struct {
+	// MaxSteps is the maximum number of invocations and loop iterations.
+	MaxSteps int
+
+	// MaxDepth is the maximum depth of nested invocations. Tail calls and
+	// recur do not add to the depth.
+	MaxDepth int
+
+	// MaxLength is the maximum length of the collections created.
+	MaxLength int
+}
+
+// Usage is the resources consumed by an evaluation.
+type Usage struct {
+	Steps  int // number of invocations and loop iterations.
+	Depth  int // deepest nesting of invocations reached.
+	Length int // length of the longest collection created.
+}
+
+// Meter measures the resources consumed by an evaluation against a Budget.
+// Methods of Meter are safe for concurrent use and are no-op on nil Meter.
+type Meter struct {
+	budget Budget
+
+	steps, depth, maxDepth, length int64
+}
+
+// NewMeter returns a new Meter that enforces the budget.
+func NewMeter(budget Budget) *Meter {
+	return &Meter{budget: budget}
+}
+
+// Step records a step (e.g., an invocation or loop iteration). Returns error
+// if the step exceeds Budget.MaxSteps.
+func (m *Meter) Step() error {
+	if m == nil {
+		return nil
+	}
+
+	steps := atomic.AddInt64(&m.steps, 1)
+	return m.check("steps", steps, m.budget.MaxSteps)
+}
+
+// Enter records entering a nested invocation. Returns error if the depth
+// exceeds Budget.MaxDepth. Every Enter must be followed by Leave.
+func (m *Meter) Enter() error {
+	if m == nil {
+		return nil
+	}
+
+	depth := atomic.AddInt64(&m.depth, 1)
+	storeMax(&m.maxDepth, depth)
+	return m.check("depth", depth, m.budget.MaxDepth)
+}
+
+// Leave records leaving a nested invocation entered using Enter.
+func (m *Meter) Leave() {
+	if m == nil {
+		return
+	}
+	atomic.AddInt64(&m.depth, -1)
+}
+
+// Length records creation of a collection of given length. Returns error if
+// the length exceeds Budget.MaxLength.
+func (m *Meter) Length(n int) error {
+	if m == nil {
+		return nil
+	}
+
+	storeMax(&m.length, int64(n))
+	return m.check("length", int64(n), m.budget.MaxLength)
+}
+
+// Usage returns the resources consumed so far.
+func (m *Meter) Usage() Usage {
+	if m == nil {
+		return Usage{}
+	}
+
+	return Usage{
+		Steps:  int(atomic.LoadInt64(&m.steps)),
+		Depth:  int(atomic.LoadInt64(&m.maxDepth)),
+		Length: int(atomic.LoadInt64(&m.length)),
+	}
+}
+
+func (m *Meter) check(resource string, used int64, limit int) error {
+	if limit > 0 && used > int64(limit) {
+		return Error{
+			Cause:   ErrBudgetExceeded,
+			Message: fmt.Sprintf("%s exceeds the limit %d", resource, limit),
+		}
+	}
+	return nil
+}
+
+// WithMeter returns a copy of the context that carries the meter.
+func WithMeter(ctx context.Context, m *Meter) context.Context {
+	return context.WithValue(ctx, meterKey{}, m)
+}
+
+// MeterOf returns the meter carried by the context. Returns nil if there
+// is none.
+func MeterOf(ctx context.Context) *Meter {
+	m, _ := ctx.Value(meterKey{}).(*Meter)
+	return m
+}
+
+type meterKey struct{}
+
+func storeMax(addr *int64, v int64) {
+	for {
+		cur := atomic.LoadInt64(addr)
+		if v <= cur || atomic.CompareAndSwapInt64(addr, cur, v) {
+			return
+		}
+	}
+}
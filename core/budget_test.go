@@ -0,0 +1,44 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMeter(t *testing.T) {
+	m := NewMeter(Budget{MaxSteps: 2, MaxDepth: 1, MaxLength: 3})
+
+	assert(t, m.Step() == nil, "first step must be within budget")
+	assert(t, m.Enter() == nil, "first level must be within budget")
+	m.Leave()
+	assert(t, m.Enter() == nil, "depth must be reduced by Leave")
+	assert(t, m.Length(3) == nil, "length must be within budget")
+	m.Leave()
+
+	err := m.Step()
+	assert(t, err == nil, "second step must be within budget, got %v", err)
+	err = m.Enter()
+	assert(t, err == nil, "first level must be within budget, got %v", err)
+
+	for _, err := range []error{m.Step(), m.Enter(), m.Length(4)} {
+		assert(t, errors.Is(err, ErrBudgetExceeded), "want ErrBudgetExceeded, got %v", err)
+		assert(t, errors.As(err, &Error{}), "want core.Error, got %v", err)
+	}
+	m.Leave()
+	m.Leave()
+
+	want := Usage{Steps: 3, Depth: 2, Length: 4}
+	assert(t, m.Usage() == want, "want=%+v\ngot=%+v", want, m.Usage())
+}
+
+func TestMeterOf(t *testing.T) {
+	var m *Meter
+	assert(t, MeterOf(context.Background()) == nil, "want nil meter for context without one")
+	assert(t, m.Step() == nil && m.Enter() == nil && m.Length(100) == nil, "nil meter must not fail")
+	assert(t, m.Usage() == Usage{}, "nil meter must have zero usage")
+
+	m = NewMeter(Budget{})
+	ctx := WithMeter(context.Background(), m)
+	assert(t, MeterOf(ctx) == m, "want the meter from the context")
+}
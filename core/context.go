@@ -0,0 +1,39 @@
+package core
+
+import "context"
+
+// ContextInvokable is implemented by Invokable values that accept a context
+// for the invocation. InvokeExpr uses InvokeContext with the context of the
+// env (See ContextOf) if the target implements this.
+type ContextInvokable interface {
+	Invokable
+
+	// InvokeContext is same as Invoke except that the invocation should be
+	// cancelled if the context is done.
+	InvokeContext(ctx context.Context, args ...Any) (Any, error)
+}
+
+// ContextChild returns a new child env of the parent (similar to Env.Child)
+// that carries the context. The context is returned by ContextOf for the
+// returned env and all its descendants.
+func ContextChild(parent Env, name string, ctx context.Context) Env {
+	return &mapEnv{
+		name:   name,
+		parent: parent,
+		vars:   map[string]Any{},
+		ctx:    ctx,
+	}
+}
+
+// ContextOf returns the context carried by the nearest env in the hierarchy
+// that was created using ContextChild. Returns context.Background() if none
+// of them carry a context.
+func ContextOf(env Env) context.Context {
+	for env != nil {
+		if me, ok := env.(*mapEnv); ok && me.ctx != nil {
+			return me.ctx
+		}
+		env = env.Parent()
+	}
+	return context.Background()
+}
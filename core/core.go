@@ -3,6 +3,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -99,6 +100,7 @@ type mapEnv struct {
 	name   string
 	mu     sync.RWMutex
 	vars   map[string]Any
+	ctx    context.Context
 }
 
 func (env *mapEnv) Name() string { return env.name }
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -68,6 +69,17 @@ func TestRoot(t *testing.T) {
 	assert(t, env.Name() == got.Name(), "want='%s', got='%s'", env.Name(), got.Name())
 }
 
+func TestContextOf(t *testing.T) {
+	env := New(nil)
+	assert(t, ContextOf(env) == context.Background(), "want background context for env without context")
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "foo")
+	child := ContextChild(env, "ctx", ctx).Child("foo", nil).Child("bar", nil)
+	assert(t, ContextOf(child) == ctx, "want context of the nearest ancestor, got %v", ContextOf(child))
+	assert(t, Root(child) == env, "context child must be part of the env hierarchy")
+}
+
 func Test_mapEnv_Bind_Resolve(t *testing.T) {
 	var v Any
 	var err error
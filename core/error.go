@@ -3,13 +3,48 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
+// ErrThrown is the cause of errors created by throwing a value that is not
+// an error itself.
+var ErrThrown = errors.New("thrown")
+
 // Error is returned by all slurp operations. Cause indicates the underlying
-// error type. Use errors.Is() with Cause to check for specific errors.
+// error type. Use errors.Is() with Cause to check for specific errors. Value
+// is set to the thrown value if the error was created by throwing a value.
+// Stack holds the invocations the error unwound through, innermost first.
 type Error struct {
 	Cause   error
 	Message string
+	Value   Any
+	Stack   []Frame
+}
+
+// Frame represents an invocation in the stack trace of an Error.
+type Frame struct {
+	Name     string
+	Form     Any
+	Position Position
+}
+
+// Position represents the location of a form in the source.
+type Position struct {
+	File string
+	Ln   int
+	Col  int
+}
+
+// WithFrame returns the error with the frame pushed to its stack trace.
+// If err is not an Error, it is wrapped in one.
+func WithFrame(err error, f Frame) error {
+	e, ok := err.(Error)
+	if !ok {
+		e = Error{Cause: err}
+	}
+
+	e.Stack = append(append([]Frame(nil), e.Stack...), f)
+	return e
 }
 
 // With returns a clone of the error with message set to given value.
@@ -17,6 +52,8 @@ func (e Error) With(msg string) Error {
 	return Error{
 		Cause:   e.Cause,
 		Message: msg,
+		Value:   e.Value,
+		Stack:   e.Stack,
 	}
 }
 
@@ -27,18 +64,55 @@ func (e Error) Is(other error) bool { return errors.Is(e.Cause, other) }
 func (e Error) Unwrap() error { return e.Cause }
 
 func (e Error) Error() string {
-	if e.Cause != nil {
+	switch {
+	case e.Cause == nil:
+		return fmt.Sprintf("EvalError: %s", e.Message)
+
+	case e.Message == "":
+		return fmt.Sprintf("EvalError: %v", e.Cause)
+
+	default:
 		return fmt.Sprintf("EvalError: %v: %s", e.Cause, e.Message)
 	}
-
-	return fmt.Sprintf("EvalError: %s", e.Message)
 }
 
+// Format renders the error message. With '%+v' or '%#v', the stack trace
+// is rendered as well.
 func (e Error) Format(s fmt.State, verb rune) {
-	if !s.Flag('#') {
-		fmt.Fprint(s, e.Error())
+	fmt.Fprint(s, e.Error())
+	if verb != 'v' || !(s.Flag('+') || s.Flag('#')) {
+		return
 	}
 
-	// TODO:  render the offending form.
-	fmt.Fprint(s, e.Error())
+	for _, f := range e.Stack {
+		fmt.Fprintf(s, "\n    at %s", f)
+	}
+}
+
+func (f Frame) String() string {
+	var b strings.Builder
+	b.WriteString(f.Name)
+
+	if f.Form != nil {
+		form := fmt.Sprintf("%v", f.Form)
+		if sxp, ok := f.Form.(SExpressable); ok {
+			if str, err := sxp.SExpr(); err == nil {
+				form = str
+			}
+		}
+		b.WriteString(" " + form)
+	}
+
+	if f.Position.Ln > 0 {
+		b.WriteString(" (" + f.Position.String() + ")")
+	}
+
+	return b.String()
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		p.File = "<unknown>"
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Ln, p.Col)
 }
@@ -1 +1,57 @@
 package core
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithFrame(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("failed")
+	err := WithFrame(cause, Frame{Name: "inner"})
+	err = WithFrame(err, Frame{Name: "outer"})
+
+	e, ok := err.(Error)
+	assert(t, ok, "want Error, got '%#v'", err)
+	assert(t, errors.Is(err, cause), "want cause '%v', got '%v'", cause, err)
+	assert(t, len(e.Stack) == 2, "want 2 frames, got %d", len(e.Stack))
+	assert(t, e.Stack[0].Name == "inner" && e.Stack[1].Name == "outer",
+		"frames must be innermost first, got %v", e.Stack)
+
+	thrown := Error{Cause: ErrThrown, Value: 10}
+	err = WithFrame(thrown, Frame{Name: "f"})
+	assert(t, err.(Error).Value == 10, "want value preserved, got '%#v'", err)
+	assert(t, len(thrown.Stack) == 0, "original error must not be modified")
+}
+
+func TestError_Format(t *testing.T) {
+	t.Parallel()
+
+	err := Error{
+		Cause:   ErrNotFound,
+		Message: "foo",
+		Stack: []Frame{
+			{Name: "bar", Form: "(bar)", Position: Position{File: "main.slurp", Ln: 3, Col: 5}},
+			{Name: "main", Form: "(main)"},
+		},
+	}
+
+	table := map[string]string{
+		"%v": "EvalError: not found: foo",
+		"%s": "EvalError: not found: foo",
+		"%+v": "EvalError: not found: foo\n" +
+			"    at bar (bar) (main.slurp:3:5)\n" +
+			"    at main (main)",
+	}
+	table["%#v"] = table["%+v"]
+
+	for format, want := range table {
+		got := fmt.Sprintf(format, err)
+		assert(t, got == want, "%s: want '%s', got '%s'", format, want, got)
+	}
+
+	got := Error{Cause: ErrNotFound}.Error()
+	assert(t, got == "EvalError: not found", "want no trailing separator, got '%s'", got)
+}
@@ -0,0 +1,26 @@
+package core
+
+// Map is an unordered collection of key-value pairs providing fast lookup
+// by key.
+type Map interface {
+	// Count returns the number of entries contained in the Map.
+	Count() (int, error)
+
+	// Assoc returns a new Map with the key associated with val. Existing
+	// association for the key (if any) is replaced.
+	Assoc(key, val Any) (Map, error)
+
+	// Dissoc returns a new Map without the association for the key.
+	Dissoc(key Any) (Map, error)
+
+	// EntryAt returns the value associated with the key. Returns ErrNotFound
+	// if the key is not present in the Map.
+	EntryAt(key Any) (Any, error)
+
+	// HasKey returns true if the Map has an association for the key.
+	HasKey(key Any) (bool, error)
+
+	// Seq returns a sequence of the entries in the Map. Each entry is a
+	// Vector of the form [key value].
+	Seq() (Seq, error)
+}
@@ -0,0 +1,14 @@
+package core
+
+// Meta is implemented by values that can carry metadata (e.g., source
+// position of forms read by a reader, type hints etc.). Metadata is not
+// considered for equality of the values.
+type Meta interface {
+	// Meta returns the metadata of the value. Returns nil if the value has
+	// no metadata.
+	Meta() Map
+
+	// WithMeta returns a copy of the value with the metadata replaced by
+	// the given map.
+	WithMeta(meta Map) Any
+}
@@ -0,0 +1,175 @@
+package core
+
+// ReduceFunc is a reducing function. It returns the accumulated value after
+// adding the item to acc. Returning true stops the reduction after the item.
+type ReduceFunc func(acc, item Any) (Any, bool, error)
+
+// Reducible is implemented by collections that can be traversed natively
+// without creating a Seq value for each item (e.g., vectors).
+type Reducible interface {
+	// Reduce calls f with init and the first item, then with the result
+	// and the second item and so on. Returns the last result. Reduction
+	// stops early if f returns true or an error.
+	Reduce(init Any, f ReduceFunc) (Any, error)
+
+	// Iterator returns an Iterator over the items.
+	Iterator() Iterator
+}
+
+// Iterator iterates over the items of a collection.
+//
+//	for it.Next() {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator interface {
+	// Next advances the iterator to the next item. Returns false if there
+	// are no more items or the iteration failed.
+	Next() bool
+
+	// Item returns the current item.
+	Item() Any
+
+	// Err returns the error that stopped the iteration (if any).
+	Err() error
+}
+
+// Reduce reduces the items of the sequence using f. Uses Reduce of the seq
+// if it is a Reducible and walks it using First and Next otherwise.
+func Reduce(seq Seq, init Any, f ReduceFunc) (Any, error) {
+	if r, ok := seq.(Reducible); ok {
+		return r.Reduce(init, f)
+	}
+
+	acc := init
+	for seq != nil {
+		v, err := seq.First()
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			break
+		}
+
+		var done bool
+		if acc, done, err = f(acc, v); err != nil {
+			return nil, err
+		} else if done {
+			break
+		}
+
+		if seq, err = seq.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// IteratorOf returns an Iterator over the items of the sequence. Returns the
+// Iterator of the seq if it is a Reducible. Otherwise, the iterator walks
+// the seq using First and Next.
+func IteratorOf(seq Seq) Iterator {
+	if r, ok := seq.(Reducible); ok {
+		return r.Iterator()
+	}
+	return &seqIterator{seq: seq}
+}
+
+type seqIterator struct {
+	seq     Seq
+	item    Any
+	err     error
+	started bool
+}
+
+func (it *seqIterator) Next() bool {
+	if it.seq == nil || it.err != nil {
+		return false
+	}
+
+	// the rest is not realized until the next item is requested.
+	if it.started {
+		if it.seq, it.err = it.seq.Next(); it.err != nil || it.seq == nil {
+			it.item = nil
+			return false
+		}
+	}
+	it.started = true
+
+	if it.item, it.err = it.seq.First(); it.err != nil || it.item == nil {
+		it.seq, it.item = nil, nil
+		return false
+	}
+	return true
+}
+
+func (it *seqIterator) Item() Any { return it.item }
+
+func (it *seqIterator) Err() error { return it.err }
+
+// Transducer transforms a reducing function into another one (e.g., to
+// map or filter the items before they are reduced). Transducers compose
+// with Comp and are independent of the source of the items.
+type Transducer func(rf ReduceFunc) ReduceFunc
+
+// Mapping returns a Transducer that replaces each item with the result of
+// f for the item.
+func Mapping(f func(item Any) (Any, error)) Transducer {
+	return func(rf ReduceFunc) ReduceFunc {
+		return func(acc, item Any) (Any, bool, error) {
+			v, err := f(item)
+			if err != nil {
+				return nil, false, err
+			}
+			return rf(acc, v)
+		}
+	}
+}
+
+// Filtering returns a Transducer that keeps only the items for which pred
+// returns true.
+func Filtering(pred func(item Any) (bool, error)) Transducer {
+	return func(rf ReduceFunc) ReduceFunc {
+		return func(acc, item Any) (Any, bool, error) {
+			ok, err := pred(item)
+			if err != nil || !ok {
+				return acc, false, err
+			}
+			return rf(acc, item)
+		}
+	}
+}
+
+// Taking returns a Transducer that stops the reduction after n items.
+func Taking(n int) Transducer {
+	return func(rf ReduceFunc) ReduceFunc {
+		taken := 0
+		return func(acc, item Any) (Any, bool, error) {
+			if taken >= n {
+				return acc, true, nil
+			}
+			taken++
+
+			acc, done, err := rf(acc, item)
+			return acc, done || taken >= n, err
+		}
+	}
+}
+
+// Comp composes the transducers. Items flow through the transducers from
+// left to right (i.e., Comp(Mapping(f), Taking(2)) maps the items before
+// taking 2 of them).
+func Comp(xfs ...Transducer) Transducer {
+	return func(rf ReduceFunc) ReduceFunc {
+		for i := len(xfs) - 1; i >= 0; i-- {
+			rf = xfs[i](rf)
+		}
+		return rf
+	}
+}
+
+// Transduce reduces the items of the sequence using the reducing function
+// transformed by xf.
+func Transduce(seq Seq, xf Transducer, init Any, rf ReduceFunc) (Any, error) {
+	return Reduce(seq, init, xf(rf))
+}
@@ -0,0 +1,121 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+var errFailed = errors.New("failed")
+
+func TestReduce(t *testing.T) {
+	t.Parallel()
+
+	ints := func(vs ...int64) []core.Any {
+		res := make([]core.Any, len(vs))
+		for i, v := range vs {
+			res[i] = builtin.Int64(v)
+		}
+		return res
+	}
+	vecSeq, _ := builtin.NewVector(ints(1, 2, 3)...).Seq()
+	lazy := builtin.NewLazySeq(func() (core.Any, error) {
+		return builtin.NewList(ints(1, 2, 3)...), nil
+	})
+	failing := builtin.NewLazySeq(func() (core.Any, error) {
+		return nil, errFailed
+	})
+	sum := func(acc, item core.Any) (core.Any, bool, error) {
+		return acc.(builtin.Int64) + item.(builtin.Int64), false, nil
+	}
+
+	table := []struct {
+		title   string
+		seq     core.Seq
+		f       core.ReduceFunc
+		want    core.Any
+		wantErr error
+	}{
+		{title: "NilSeq", seq: nil, f: sum, want: builtin.Int64(0)},
+		{title: "Vector", seq: vecSeq, f: sum, want: builtin.Int64(6)},
+		{title: "List", seq: builtin.NewList(ints(1, 2, 3)...), f: sum, want: builtin.Int64(6)},
+		{title: "Seq", seq: lazy, f: sum, want: builtin.Int64(6)},
+		{
+			title: "EarlyTermination",
+			seq:   lazy,
+			f: func(acc, item core.Any) (core.Any, bool, error) {
+				return item, item == builtin.Int64(2), nil
+			},
+			want: builtin.Int64(2),
+		},
+		{
+			title: "FuncError",
+			seq:   vecSeq,
+			f: func(acc, item core.Any) (core.Any, bool, error) {
+				return nil, false, errFailed
+			},
+			wantErr: errFailed,
+		},
+		{title: "SeqError", seq: failing, f: sum, wantErr: errFailed},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := core.Reduce(tt.seq, builtin.Int64(0), tt.f)
+			if tt.wantErr != nil {
+				assert(t, errors.Is(err, tt.wantErr), "wantErr=%#v\ngotErr=%#v", tt.wantErr, err)
+				return
+			}
+			assert(t, err == nil, "unexpected err: %#v", err)
+			assert(t, got == tt.want, "want=%#v, got=%#v", tt.want, got)
+
+			var items []core.Any
+			it := core.IteratorOf(tt.seq)
+			for it.Next() {
+				items = append(items, it.Item())
+			}
+			assert(t, it.Err() == nil, "unexpected err: %#v", it.Err())
+
+			want, _ := core.ToSlice(tt.seq)
+			assert(t, len(items) == len(want), "want=%#v, got=%#v", want, items)
+		})
+	}
+}
+
+func TestTransduce(t *testing.T) {
+	t.Parallel()
+
+	var items []core.Any
+	for i := 0; i < 10; i++ {
+		items = append(items, builtin.Int64(i))
+	}
+	seq, _ := builtin.NewVector(items...).Seq()
+
+	calls := 0
+	xf := core.Comp(
+		core.Mapping(func(item core.Any) (core.Any, error) {
+			calls++
+			return item.(builtin.Int64) * 10, nil
+		}),
+		core.Filtering(func(item core.Any) (bool, error) {
+			return item.(builtin.Int64)%20 == 0, nil
+		}),
+		core.Taking(3),
+	)
+
+	got, err := core.Transduce(seq, xf, builtin.NewVector(), func(acc, item core.Any) (core.Any, bool, error) {
+		v, err := acc.(core.Vector).Conj(item)
+		return v, false, err
+	})
+	assert(t, err == nil, "unexpected err: %#v", err)
+
+	want := builtin.NewVector(builtin.Int64(0), builtin.Int64(20), builtin.Int64(40))
+	eq, err := core.Eq(want, got)
+	assert(t, err == nil && eq, "want=%#v, got=%#v", want, got)
+	assert(t, calls == 5, "want 5 items mapped, got %d", calls)
+
+	got, err = core.Transduce(seq, core.Taking(0), builtin.Int64(-1), nil)
+	assert(t, err == nil && got == builtin.Int64(-1), "want=-1, got=%#v (err=%v)", got, err)
+}
@@ -0,0 +1,12 @@
+package core
+
+import "context"
+
+// Ref is implemented by values that refer to another value (e.g., futures,
+// promises). The value is retrieved using Deref.
+type Ref interface {
+	// Deref returns the value referred to. Deref may block until the value
+	// is available (e.g., future of a computation), in which case it must
+	// return when the context is done.
+	Deref(ctx context.Context) (Any, error)
+}
@@ -27,9 +27,33 @@ type Seqable interface {
 	Seq() (Seq, error)
 }
 
+// Uncounted is implemented by sequences that cannot count their items
+// without realizing them (e.g., lazy or infinite sequences).
+type Uncounted interface {
+	// CountUnknown returns true if Count has to walk the sequence to count
+	// its items.
+	CountUnknown() bool
+}
+
+// IsCounted returns true if the count of the sequence is known without
+// walking (and realizing) it.
+func IsCounted(seq Seq) bool {
+	u, ok := seq.(Uncounted)
+	return !ok || !u.CountUnknown()
+}
+
 // ToSlice converts the given sequence into a slice.
 func ToSlice(seq Seq) ([]Any, error) {
 	var sl []Any
+	if seq != nil && IsCounted(seq) {
+		cnt, err := seq.Count()
+		if err != nil {
+			return nil, err
+		} else if cnt > 0 {
+			sl = make([]Any, 0, cnt)
+		}
+	}
+
 	err := ForEach(seq, func(item Any) (bool, error) {
 		sl = append(sl, item)
 		return false, nil
@@ -38,8 +62,20 @@ func ToSlice(seq Seq) ([]Any, error) {
 }
 
 // ForEach reads from the sequence and calls the given function for each item.
-// Function can return true to stop the iteration.
+// Function can return true to stop the iteration. Uses Reduce of the seq if
+// it is a Reducible. Like a seq walk, the iteration stops at a nil item.
 func ForEach(seq Seq, call func(item Any) (bool, error)) (err error) {
+	if r, ok := seq.(Reducible); ok {
+		_, err = r.Reduce(nil, func(_, item Any) (Any, bool, error) {
+			if item == nil {
+				return nil, true, nil
+			}
+			done, err := call(item)
+			return nil, done, err
+		})
+		return err
+	}
+
 	var v Any
 	var done bool
 	for seq != nil {
@@ -101,38 +137,54 @@ func seqEq(s1, s2 Seq) (bool, error) {
 		return false, nil
 	}
 
-	c1, err := s1.Count()
-	if err != nil {
-		return false, err
-	}
+	if IsCounted(s1) && IsCounted(s2) {
+		c1, err := s1.Count()
+		if err != nil {
+			return false, err
+		}
 
-	c2, err := s2.Count()
-	if err != nil {
-		return false, err
-	}
+		c2, err := s2.Count()
+		if err != nil {
+			return false, err
+		}
 
-	if c1 != c2 {
-		return false, nil
+		if c1 != c2 {
+			return false, nil
+		}
 	}
 
-	bothEqual := true
-	for i := 0; i < c1; i++ {
-		v1, err := s1.First()
+	for {
+		v1, err := seqFirst(s1)
 		if err != nil {
 			return false, err
 		}
 
-		v2, err := s2.First()
+		v2, err := seqFirst(s2)
 		if err != nil {
 			return false, err
 		}
 
-		eq, err := Eq(v1, v2)
-		if err != nil {
+		if v1 == nil || v2 == nil {
+			return v1 == nil && v2 == nil, nil
+		}
+
+		if eq, err := Eq(v1, v2); err != nil || !eq {
+			return false, err
+		}
+
+		if s1, err = s1.Next(); err != nil {
+			return false, err
+		}
+
+		if s2, err = s2.Next(); err != nil {
 			return false, err
 		}
-		bothEqual = bothEqual && eq
 	}
+}
 
-	return bothEqual, nil
+func seqFirst(seq Seq) (Any, error) {
+	if seq == nil {
+		return nil, nil
+	}
+	return seq.First()
 }
@@ -0,0 +1,19 @@
+package core
+
+// Set is an unordered collection of distinct values.
+type Set interface {
+	// Count returns the number of elements contained in the Set.
+	Count() (int, error)
+
+	// Conj returns a new Set with the given values added.
+	Conj(vs ...Any) (Set, error)
+
+	// Disj returns a new Set with the given values removed.
+	Disj(vs ...Any) (Set, error)
+
+	// Contains returns true if the value is a member of the Set.
+	Contains(v Any) (bool, error)
+
+	// Seq returns a sequence of the elements in the Set.
+	Seq() (Seq, error)
+}
@@ -117,6 +117,31 @@ func TestEq(t *testing.T) {
 			want:    true,
 			wantErr: nil,
 		},
+		{
+			title:   "SeqNotEqual",
+			a:       builtin.NewList(builtin.Int64(1), builtin.Symbol("foo")),
+			b:       builtin.NewList(builtin.Int64(1), builtin.Symbol("bar")),
+			want:    false,
+			wantErr: nil,
+		},
+		{
+			title: "SeqUncounted",
+			a:     builtin.NewList(builtin.Int64(1), builtin.Int64(2)),
+			b: builtin.NewLazySeq(func() (core.Any, error) {
+				return builtin.NewVector(builtin.Int64(1), builtin.Int64(2)), nil
+			}),
+			want:    true,
+			wantErr: nil,
+		},
+		{
+			title: "SeqUncountedShorter",
+			a:     builtin.NewList(builtin.Int64(1), builtin.Int64(2)),
+			b: builtin.NewLazySeq(func() (core.Any, error) {
+				return builtin.NewList(builtin.Int64(1)), nil
+			}),
+			want:    false,
+			wantErr: nil,
+		},
 	}
 
 	for _, tt := range table {
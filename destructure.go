@@ -0,0 +1,443 @@
+package slurp
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+var (
+	gensymCounter uint64
+
+	kwKeys = builtin.Keyword("keys")
+	kwStrs = builtin.Keyword("strs")
+	kwSyms = builtin.Keyword("syms")
+	kwOr   = builtin.Keyword("or")
+	kwAs   = builtin.Keyword("as")
+)
+
+// gensym returns a new symbol name with the given prefix that is unique
+// within the process.
+func gensym(prefix string) string {
+	return fmt.Sprintf("%s__%d", prefix, atomic.AddUint64(&gensymCounter, 1))
+}
+
+// destructure compiles the binding form into sequential name-value bindings
+// that are appended to the let. Binding form can be a symbol, a vector for
+// sequential destructuring (e.g., [a b & rest :as all]) or a map for assoc
+// destructuring (e.g., {:keys [a b] :or {a 1} :as m}). All the names bound
+// are also declared in env so that the body can be analyzed against it.
+func destructure(a core.Analyzer, env core.Env, form core.Any, val core.Expr, let *builtin.LetExpr) error {
+	switch f := form.(type) {
+	case builtin.Symbol:
+		if f == restSymbol {
+			return bindingErr("unexpected '&'")
+		}
+		let.Names = append(let.Names, string(f))
+		let.Values = append(let.Values, val)
+		return env.Bind(string(f), nil)
+
+	case builtin.MetaSymbol:
+		return destructure(a, env, f.Symbol, val, let)
+
+	case core.Vector:
+		return destructureSeq(a, env, f, val, let)
+
+	case core.Map:
+		return destructureMap(a, env, f, val, let)
+
+	default:
+		return bindingErr(fmt.Sprintf(
+			"expecting symbol, vector or map binding form, got '%s'", reflect.TypeOf(form)))
+	}
+}
+
+func destructureSeq(a core.Analyzer, env core.Env, vec core.Vector, val core.Expr, let *builtin.LetExpr) error {
+	tmp := gensym("vec")
+	if err := destructure(a, env, builtin.Symbol(tmp), val, let); err != nil {
+		return err
+	}
+	coll := builtin.ResolveExpr{Symbol: builtin.Symbol(tmp)}
+
+	cnt, err := vec.Count()
+	if err != nil {
+		return err
+	}
+
+	pos := 0
+	for i := 0; i < cnt; i++ {
+		item, err := vec.EntryAt(i)
+		if err != nil {
+			return err
+		}
+
+		switch item {
+		case restSymbol:
+			if i+1 >= cnt {
+				return bindingErr("expecting binding form after '&'")
+			}
+			i++
+
+			rest, _ := vec.EntryAt(i)
+			if err := destructure(a, env, rest, nthExpr{Coll: coll, Index: pos, Rest: true}, let); err != nil {
+				return err
+			}
+
+		case kwAs:
+			if i+1 != cnt-1 {
+				return bindingErr("expecting exactly one symbol after :as at the end")
+			}
+			i++
+
+			name, _ := vec.EntryAt(i)
+			if _, ok := name.(builtin.Symbol); !ok {
+				return bindingErr(fmt.Sprintf(
+					"expecting symbol after :as, got '%s'", reflect.TypeOf(name)))
+			}
+
+			if err := destructure(a, env, name, coll, let); err != nil {
+				return err
+			}
+
+		default:
+			if err := destructure(a, env, item, nthExpr{Coll: coll, Index: pos}, let); err != nil {
+				return err
+			}
+			pos++
+		}
+	}
+
+	return nil
+}
+
+func destructureMap(a core.Analyzer, env core.Env, m core.Map, val core.Expr, let *builtin.LetExpr) error {
+	tmp := gensym("map")
+	if err := destructure(a, env, builtin.Symbol(tmp), val, let); err != nil {
+		return err
+	}
+	coll := builtin.ResolveExpr{Symbol: builtin.Symbol(tmp)}
+
+	// a sequence value is converted to a map once for all the keys.
+	tmpMap := gensym("map")
+	if err := destructure(a, env, builtin.Symbol(tmpMap), mapExpr{Coll: coll}, let); err != nil {
+		return err
+	}
+	collMap := builtin.ResolveExpr{Symbol: builtin.Symbol(tmpMap)}
+
+	defaults := map[builtin.Symbol]core.Any{}
+	if found, err := m.HasKey(kwOr); err != nil {
+		return err
+	} else if found {
+		orForm, _ := m.EntryAt(kwOr)
+		orMap, ok := orForm.(core.Map)
+		if !ok {
+			return bindingErr(fmt.Sprintf(
+				"expecting map after :or, got '%s'", reflect.TypeOf(orForm)))
+		}
+
+		err := forEachEntry(orMap, func(k, v core.Any) error {
+			sym, ok := k.(builtin.Symbol)
+			if !ok {
+				return bindingErr(fmt.Sprintf(
+					"expecting symbol keys in :or, got '%s'", reflect.TypeOf(k)))
+			}
+
+			defaults[sym] = v
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// defaults are analyzed when the name is bound so that a default can
+	// refer to the names bound before it.
+	bindKey := func(target core.Any, key core.Any) error {
+		var def core.Expr
+		if sym, ok := target.(builtin.Symbol); ok && defaults[sym] != nil {
+			var err error
+			if def, err = a.Analyze(env, defaults[sym]); err != nil {
+				return err
+			}
+		}
+		return destructure(a, env, target, getExpr{Map: collMap, Key: key, Default: def}, let)
+	}
+
+	bindEntry := func(k, v core.Any) error {
+		switch k {
+		case kwOr:
+			return nil
+
+		case kwAs:
+			if _, ok := v.(builtin.Symbol); !ok {
+				return bindingErr(fmt.Sprintf(
+					"expecting symbol after :as, got '%s'", reflect.TypeOf(v)))
+			}
+			return destructure(a, env, v, coll, let)
+
+		case kwKeys, kwStrs, kwSyms:
+			names, ok := v.(core.Vector)
+			if !ok {
+				return bindingErr(fmt.Sprintf(
+					"expecting vector of symbols after %s, got '%s'", k, reflect.TypeOf(v)))
+			}
+
+			cnt, err := names.Count()
+			if err != nil {
+				return err
+			}
+
+			for i := 0; i < cnt; i++ {
+				item, err := names.EntryAt(i)
+				if err != nil {
+					return err
+				}
+
+				sym, ok := item.(builtin.Symbol)
+				if !ok {
+					return bindingErr(fmt.Sprintf(
+						"expecting symbol in %s, got '%s'", k, reflect.TypeOf(item)))
+				}
+
+				var key core.Any
+				switch k {
+				case kwKeys:
+					key = builtin.Keyword(sym)
+				case kwStrs:
+					key = builtin.String(sym)
+				default:
+					key = sym
+				}
+
+				if err := bindKey(sym, key); err != nil {
+					return err
+				}
+			}
+			return nil
+
+		default:
+			return bindKey(k, v)
+		}
+	}
+
+	entries, err := sortedEntries(m)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := bindEntry(entry[0], entry[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedEntries returns the key-value pairs of the map binding form in a
+// deterministic order: :as, :keys, :strs and :syms first, followed by the
+// other binding forms sorted by their key. :or is skipped since defaults
+// are applied when the names are bound.
+func sortedEntries(m core.Map) ([][2]core.Any, error) {
+	var special, others [][2]core.Any
+	err := forEachEntry(m, func(k, v core.Any) error {
+		switch k {
+		case kwOr:
+		case kwAs, kwKeys, kwStrs, kwSyms:
+			special = append(special, [2]core.Any{k, v})
+		default:
+			others = append(others, [2]core.Any{k, v})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rank := map[core.Any]int{kwAs: 0, kwKeys: 1, kwStrs: 2, kwSyms: 3}
+	sort.Slice(special, func(i, j int) bool {
+		return rank[special[i][0]] < rank[special[j][0]]
+	})
+
+	keys := make([]string, len(others))
+	for i, entry := range others {
+		keys[i] = fmt.Sprint(entry[0])
+		if se, ok := entry[0].(core.SExpressable); ok {
+			s, err := se.SExpr()
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = s
+		}
+	}
+	sort.Sort(entriesByKey{entries: others, keys: keys})
+
+	return append(special, others...), nil
+}
+
+type entriesByKey struct {
+	entries [][2]core.Any
+	keys    []string
+}
+
+func (e entriesByKey) Len() int           { return len(e.entries) }
+func (e entriesByKey) Less(i, j int) bool { return e.keys[i] < e.keys[j] }
+func (e entriesByKey) Swap(i, j int) {
+	e.entries[i], e.entries[j] = e.entries[j], e.entries[i]
+	e.keys[i], e.keys[j] = e.keys[j], e.keys[i]
+}
+
+func forEachEntry(m core.Map, fn func(k, v core.Any) error) error {
+	seq, err := m.Seq()
+	if err != nil {
+		return err
+	}
+
+	return core.ForEach(seq, func(item core.Any) (bool, error) {
+		entry := item.(core.Vector)
+		k, err := entry.EntryAt(0)
+		if err != nil {
+			return true, err
+		}
+
+		v, err := entry.EntryAt(1)
+		if err != nil {
+			return true, err
+		}
+		return false, fn(k, v)
+	})
+}
+
+func bindingErr(msg string) error {
+	return core.Error{
+		Cause:   fmt.Errorf("%w: destructure", ErrParseSpecial),
+		Message: msg,
+	}
+}
+
+// nthExpr evaluates to the item at Index in the sequential value of Coll.
+// If Rest is true, evaluates to the sequence of items starting at Index
+// instead. Evaluates to nil if the index is out of range.
+type nthExpr struct {
+	Coll  core.Expr
+	Index int
+	Rest  bool
+}
+
+func (ne nthExpr) Eval(env core.Env) (core.Any, error) {
+	coll, err := ne.Coll.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if vec, ok := coll.(core.Vector); ok && !ne.Rest {
+		cnt, err := vec.Count()
+		if err != nil || ne.Index >= cnt {
+			return builtin.Nil{}, err
+		}
+		return vec.EntryAt(ne.Index)
+	}
+
+	seq, err := toSeq(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < ne.Index && seq != nil; i++ {
+		if seq, err = seq.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if seq == nil {
+		return builtin.Nil{}, nil
+	}
+
+	v, err := seq.First()
+	if err != nil || v == nil {
+		return builtin.Nil{}, err
+	}
+
+	if ne.Rest {
+		return seq, nil
+	}
+	return v, nil
+}
+
+// mapExpr evaluates to the map value of Coll. A sequence value (e.g., rest
+// args) is treated as key-value pairs and converted to a map. Other values
+// are returned as is.
+type mapExpr struct {
+	Coll core.Expr
+}
+
+func (me mapExpr) Eval(env core.Env) (core.Any, error) {
+	v, err := me.Coll.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, ok := v.(core.Seq)
+	if !ok {
+		return v, nil
+	}
+
+	kvs, err := core.ToSlice(seq)
+	if err != nil {
+		return nil, err
+	}
+	return builtin.NewMap(kvs...)
+}
+
+// getExpr evaluates to the value associated with Key in the map value of
+// Map. Evaluates to the value of Default (or nil) if the key is not found.
+type getExpr struct {
+	Map     core.Expr
+	Key     core.Any
+	Default core.Expr
+}
+
+func (ge getExpr) Eval(env core.Env) (core.Any, error) {
+	v, err := ge.Map.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	if m, ok := v.(core.Map); ok {
+		val, err := m.EntryAt(ge.Key)
+		if err == nil {
+			return val, nil
+		} else if !errors.Is(err, core.ErrNotFound) {
+			return nil, err
+		}
+	} else if !builtin.IsNil(v) {
+		return nil, fmt.Errorf(
+			"cannot destructure value of type '%s' as map", reflect.TypeOf(v))
+	}
+
+	if ge.Default == nil {
+		return builtin.Nil{}, nil
+	}
+	return ge.Default.Eval(env)
+}
+
+func toSeq(v core.Any) (core.Seq, error) {
+	switch val := v.(type) {
+	case nil, builtin.Nil:
+		return nil, nil
+
+	case core.Seq:
+		return val, nil
+
+	case core.Seqable:
+		return val.Seq()
+
+	default:
+		return nil, fmt.Errorf(
+			"value of type '%s' is not a sequence", reflect.TypeOf(v))
+	}
+}
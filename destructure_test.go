@@ -0,0 +1,189 @@
+package slurp
+
+import (
+	"testing"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestructure(t *testing.T) {
+	t.Parallel()
+
+	kw := func(s string) builtin.Keyword { return builtin.Keyword(s) }
+	i := func(v int64) builtin.Int64 { return builtin.Int64(v) }
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "SequentialLet",
+			src:   `(let [[a b] [1 2]] [b a])`,
+			want:  builtin.NewVector(i(2), i(1)),
+		},
+		{
+			title: "SequentialLet_List",
+			src:   `(let [[a b] '(1 2)] [b a])`,
+			want:  builtin.NewVector(i(2), i(1)),
+		},
+		{
+			title: "SequentialLet_Missing",
+			src:   `(let [[a b c] [1 2]] c)`,
+			want:  builtin.Nil{},
+		},
+		{
+			title: "SequentialLet_Rest",
+			src:   `(let [[a & more] '(1 2 3)] more)`,
+			want:  builtin.NewList(i(2), i(3)),
+		},
+		{
+			title: "SequentialLet_RestPattern",
+			src:   `(let [[a & [b c]] [1 2 3]] [b c])`,
+			want:  builtin.NewVector(i(2), i(3)),
+		},
+		{
+			title: "SequentialLet_RestEmpty",
+			src:   `(let [[a & more] [1]] more)`,
+			want:  builtin.Nil{},
+		},
+		{
+			title: "SequentialLet_As",
+			src:   `(let [[a :as all] [1 2]] [a all])`,
+			want:  builtin.NewVector(i(1), builtin.NewVector(i(1), i(2))),
+		},
+		{
+			title: "SequentialLet_Nested",
+			src:   `(let [[a [b c]] [1 [2 3]]] [a b c])`,
+			want:  builtin.NewVector(i(1), i(2), i(3)),
+		},
+		{
+			title: "SequentialLet_RefersPrevious",
+			src:   `(let [a [1 2] [x y] a] [y x])`,
+			want:  builtin.NewVector(i(2), i(1)),
+		},
+		{
+			title: "AssocLet_Keys",
+			src:   `(let [{:keys [a b]} {:a 1 :b 2}] [a b])`,
+			want:  builtin.NewVector(i(1), i(2)),
+		},
+		{
+			title: "AssocLet_StrsAndSyms",
+			src:   `(let [{:strs [a] :syms [b]} {"a" 1 'b 2}] [a b])`,
+			want:  builtin.NewVector(i(1), i(2)),
+		},
+		{
+			title: "AssocLet_Or",
+			src:   `(let [{:keys [a b] :or {b 10}} {:a 1}] [a b])`,
+			want:  builtin.NewVector(i(1), i(10)),
+		},
+		{
+			title: "AssocLet_OrRefersPrevious",
+			src:   `(let [{:keys [a b] :or {b a}} {:a 1}] [a b])`,
+			want:  builtin.NewVector(i(1), i(1)),
+		},
+		{
+			title: "AssocLet_SortedBindings",
+			src:   `(let [{c :c b :b a :a :or {c b b a}} {:a 1}] [a b c])`,
+			want:  builtin.NewVector(i(1), i(1), i(1)),
+		},
+		{
+			title: "AssocLet_As",
+			src:   `(let [{a :a :as m} {:a 1}] [a m])`,
+			want:  builtin.NewVector(i(1), mustMap(t, kw("a"), i(1))),
+		},
+		{
+			title: "AssocLet_NestedSequential",
+			src:   `(let [{[x y] :point} {:point [1 2]}] [x y])`,
+			want:  builtin.NewVector(i(1), i(2)),
+		},
+		{
+			title: "AssocLet_Nil",
+			src:   `(let [{:keys [a]} nil] a)`,
+			want:  builtin.Nil{},
+		},
+		{
+			title: "FnParams",
+			src:   `((fn [[a b] {:keys [c]}] [a b c]) [1 2] {:c 3})`,
+			want:  builtin.NewVector(i(1), i(2), i(3)),
+		},
+		{
+			title: "FnParams_RestMap",
+			src:   `((fn [a & {:keys [b]}] [a b]) 1 :b 2)`,
+			want:  builtin.NewVector(i(1), i(2)),
+		},
+		{
+			title: "FnParams_RestMapManyKeys",
+			src:   `((fn [& {:keys [a b c d] :as m}] [a b c d m]) :a 1 :b 2 :c 3 :d 4)`,
+			want: builtin.NewVector(i(1), i(2), i(3), i(4),
+				builtin.NewList(kw("a"), i(1), kw("b"), i(2), kw("c"), i(3), kw("d"), i(4))),
+		},
+		{
+			title: "FnParams_MultiArity",
+			src:   `((fn ([a] a) ([[a] b] [a b])) [1] 2)`,
+			want:  builtin.NewVector(i(1), i(2)),
+		},
+		{
+			title:   "InvalidBindingForm",
+			src:     `(let [1 2] 1)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "OddBindings",
+			src:     `(let [a] a)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "MissingRest",
+			src:     `(let [[a &] [1]] a)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "AsNotLast",
+			src:     `(let [[:as all a] [1]] a)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "InvalidKeys",
+			src:     `(let [{:keys a} {}] a)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "InvalidOr",
+			src:     `(let [{:keys [a] :or [a 1]} {}] a)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "InvalidFnParam",
+			src:     `(fn [1] 1)`,
+			wantErr: ErrParseSpecial,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := New().EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDestructure_RestSExpr(t *testing.T) {
+	t.Parallel()
+
+	got, err := New().EvalStr(`(let [[a b & r] [1 2 3 4]] [a b r])`)
+	require.NoError(t, err)
+
+	s, err := got.(core.SExpressable).SExpr()
+	require.NoError(t, err)
+	assert.Equal(t, "[1 2 (3 4)]", s)
+}
@@ -22,17 +22,6 @@ var globals = map[string]core.Any{
 	"version": builtin.String("1.0"),
 
 	// custom Go functions.
-	"=": slurp.Func("=", core.Eq),
-	"+": slurp.Func("sum", func(a ...int) int {
-		sum := 0
-		for _, item := range a {
-			sum += item
-		}
-		return sum
-	}),
-	">": slurp.Func(">", func(a, b builtin.Int64) bool {
-		return a > b
-	}),
 	"conj": slurp.Func("conj", conj),
 }
 
@@ -64,7 +53,7 @@ func conj(vs ...core.Any) (core.Any, error) {
 }
 
 func main() {
-	env := slurp.New()
+	env := slurp.New(slurp.WithCoreLib())
 	if err := env.Bind(globals); err != nil {
 		fmt.Printf("bind failed: %+v\n", err)
 		os.Exit(1)
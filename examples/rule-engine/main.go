@@ -41,7 +41,22 @@ func runDiscountingRule(rule string, user string) (bool, error) {
 		"current-user":        user,
 	}
 
-	ins := slurp.New()
+	// Rules are supplied by users and cannot be trusted. So limit the
+	// resources the evaluation of a rule can consume and allow only the
+	// globals defined above to be used.
+	var names []string
+	for name := range globals {
+		names = append(names, name)
+	}
+
+	ins := slurp.New(
+		slurp.WithProfile(slurp.ProfileRules.WithBindings(names...)),
+		slurp.WithBudget(core.Budget{
+			MaxSteps:  1000,
+			MaxDepth:  100,
+			MaxLength: 1000,
+		}),
+	)
 	_ = ins.Bind(globals)
 	shouldDiscount, err := ins.EvalStr(rule)
 	return builtin.IsTruthy(shouldDiscount), err
@@ -17,23 +17,10 @@ var globals = map[string]core.Any{
 	"true":    builtin.Bool(true),
 	"false":   builtin.Bool(false),
 	"version": builtin.String("1.0"),
-
-	// custom Go functions.
-	"=": slurp.Func("=", core.Eq),
-	"+": slurp.Func("sum", func(a ...int) int {
-		sum := 0
-		for _, item := range a {
-			sum += item
-		}
-		return sum
-	}),
-	">": slurp.Func(">", func(a, b builtin.Int64) bool {
-		return a > b
-	}),
 }
 
 func main() {
-	env := slurp.New()
+	env := slurp.New(slurp.WithCoreLib())
 	if err := env.Bind(globals); err != nil {
 		fmt.Printf("bind failed: %+v\n", err)
 		os.Exit(1)
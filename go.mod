@@ -1,5 +1,5 @@
 module github.com/spy16/slurp
 
-go 1.15
+go 1.16
 
 require github.com/stretchr/testify v1.7.0
@@ -0,0 +1,144 @@
+// Package core provides the standard library of arithmetic, comparison and
+// sequence functions for slurp. The functions are implemented directly on
+// the core contracts (core.Seq, core.Vector etc.) instead of using the
+// reflection based slurp.Func. Use slurp.WithCoreLib to install them in an
+// Interpreter or bind the values returned by Bindings in any core.Env.
+//
+// map, filter, range, iterate, repeat, cycle and concat return lazy
+// sequences (See builtin.LazySeq). The items are realized with the context
+// of the call that created the sequence and each realized item is counted
+// as a step against the budget of that context.
+//
+// map, filter and take called without a collection return an Xform (a
+// transducer) that can be composed with comp and applied to a collection
+// with transduce or into without creating intermediate sequences.
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+var _ core.ContextInvokable = Fn{}
+
+// Bindings returns the functions of the library by their names.
+func Bindings() map[string]core.Any {
+	fns := []Fn{
+		{Name: "+", Max: -1, Func: add},
+		{Name: "-", Min: 1, Max: -1, Func: sub},
+		{Name: "*", Max: -1, Func: mul},
+		{Name: "/", Min: 1, Max: -1, Func: div},
+		{Name: "mod", Min: 2, Max: 2, Func: mod},
+		{Name: "inc", Min: 1, Max: 1, Func: inc},
+		{Name: "dec", Min: 1, Max: 1, Func: dec},
+		{Name: "<", Min: 1, Max: -1, Func: compareAll(func(c int) bool { return c < 0 })},
+		{Name: ">", Min: 1, Max: -1, Func: compareAll(func(c int) bool { return c > 0 })},
+		{Name: "<=", Min: 1, Max: -1, Func: compareAll(func(c int) bool { return c <= 0 })},
+		{Name: ">=", Min: 1, Max: -1, Func: compareAll(func(c int) bool { return c >= 0 })},
+		{Name: "=", Min: 1, Max: -1, Func: equals},
+		{Name: "not=", Min: 1, Max: -1, Func: notEquals},
+		{Name: "not", Min: 1, Max: 1, Func: not},
+		{Name: "first", Min: 1, Max: 1, Func: first},
+		{Name: "rest", Min: 1, Max: 1, Func: rest},
+		{Name: "cons", Min: 2, Max: 2, Func: cons},
+		{Name: "conj", Min: 1, Max: -1, Func: conj},
+		{Name: "count", Min: 1, Max: 1, Func: count},
+		{Name: "nth", Min: 2, Max: 3, Func: nth},
+		{Name: "get", Min: 2, Max: 3, Func: get},
+		{Name: "map", Min: 1, Max: -1, Func: mapFn},
+		{Name: "filter", Min: 1, Max: 2, Func: filter},
+		{Name: "reduce", Min: 2, Max: 3, Func: reduce},
+		{Name: "range", Max: 3, Func: rangeFn},
+		{Name: "iterate", Min: 2, Max: 2, Func: iterate},
+		{Name: "repeat", Min: 1, Max: 2, Func: repeat},
+		{Name: "cycle", Min: 1, Max: 1, Func: cycle},
+		{Name: "take", Min: 1, Max: 2, Func: take},
+		{Name: "drop", Min: 2, Max: 2, Func: drop},
+		{Name: "concat", Max: -1, Func: concat},
+		{Name: "str", Max: -1, Func: str},
+		{Name: "apply", Min: 2, Max: -1, Func: apply},
+		{Name: "identity", Min: 1, Max: 1, Func: identity},
+		{Name: "comp", Max: -1, Func: comp},
+		{Name: "transduce", Min: 3, Max: 4, Func: transduce},
+		{Name: "into", Min: 2, Max: 3, Func: into},
+	}
+
+	m := make(map[string]core.Any, len(fns))
+	for _, fn := range fns {
+		m[fn.Name] = fn
+	}
+	return m
+}
+
+// Fn is a function of the library implemented in Go. Fn receives the context
+// of the evaluation and uses it to invoke the functions passed to it.
+type Fn struct {
+	Name string
+
+	// Min and Max are the minimum and maximum number of arguments. Max -1
+	// allows any number of arguments more than Min.
+	Min, Max int
+
+	Func func(ctx context.Context, args ...core.Any) (core.Any, error)
+}
+
+// Invoke invokes the function with a background context.
+func (fn Fn) Invoke(args ...core.Any) (core.Any, error) {
+	return fn.InvokeContext(context.Background(), args...)
+}
+
+// InvokeContext checks the number of arguments and invokes the function.
+func (fn Fn) InvokeContext(ctx context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) < fn.Min || (fn.Max >= 0 && len(args) > fn.Max) {
+		return nil, fmt.Errorf("%w (%d) to '%s'", core.ErrArity, len(args), fn.Name)
+	}
+	return fn.Func(ctx, args...)
+}
+
+// SExpr returns a string representation of the function.
+func (fn Fn) SExpr() (string, error) { return fmt.Sprintf("#fn[%s]", fn.Name), nil }
+
+func (fn Fn) String() string { return fmt.Sprintf("func %s", fn.Name) }
+
+// invoke invokes fn with the context if it accepts one.
+func invoke(ctx context.Context, fn core.Any, args ...core.Any) (core.Any, error) {
+	switch f := fn.(type) {
+	case core.ContextInvokable:
+		return f.InvokeContext(ctx, args...)
+	case core.Invokable:
+		return f.Invoke(args...)
+	}
+	return nil, core.Error{
+		Cause:   core.ErrNotInvokable,
+		Message: fmt.Sprintf("value of type '%s' is not invokable", reflect.TypeOf(fn)),
+	}
+}
+
+// checkLength checks the context for cancellation and the length of the
+// collection being built against the budget (if any).
+func checkLength(ctx context.Context, n int) error {
+	if err := ctx.Err(); err != nil {
+		return core.Error{Cause: err}
+	}
+	return core.MeterOf(ctx).Length(n)
+}
+
+// checkStep checks the context for cancellation and records a step in the
+// meter of the context (if any).
+func checkStep(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return core.Error{Cause: err}
+	}
+	return core.MeterOf(ctx).Step()
+}
+
+func toInt(name string, v core.Any) (int, error) {
+	if n, ok := v.(builtin.Int64); ok {
+		return int(n), nil
+	}
+	return 0, fmt.Errorf("%s: expecting integer, not '%s'", name, reflect.TypeOf(v))
+}
@@ -0,0 +1,221 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindings(t *testing.T) {
+	t.Parallel()
+
+	list := builtin.NewList
+	vec := builtin.NewVector
+	ints := func(vs ...int64) []core.Any {
+		res := make([]core.Any, len(vs))
+		for i, v := range vs {
+			res[i] = builtin.Int64(v)
+		}
+		return res
+	}
+	m, _ := builtin.NewMap(builtin.Keyword("a"), builtin.Int64(1))
+	set, _ := builtin.NewSet(builtin.Keyword("a"))
+	fns := Bindings()
+	fn := func(name string) core.Any { return fns[name] }
+
+	table := []struct {
+		title   string
+		fn      string
+		args    []core.Any
+		want    core.Any
+		wantErr error
+	}{
+		{title: "Add_NoArgs", fn: "+", want: builtin.Int64(0)},
+		{title: "Add", fn: "+", args: ints(1, 2, 3), want: builtin.Int64(6)},
+		{title: "Add_Mixed", fn: "+", args: []core.Any{builtin.Int64(1), builtin.Float64(0.5)}, want: builtin.Float64(1.5)},
+		{title: "Add_NotNumber", fn: "+", args: []core.Any{builtin.String("a")}, wantErr: builtin.ErrNotNumber},
+		{title: "Sub_Negate", fn: "-", args: ints(2), want: builtin.Int64(-2)},
+		{title: "Sub", fn: "-", args: ints(10, 2, 3), want: builtin.Int64(5)},
+		{title: "Sub_NoArgs", fn: "-", wantErr: core.ErrArity},
+		{title: "Mul", fn: "*", args: ints(2, 3, 4), want: builtin.Int64(24)},
+		{title: "Div_Reciprocal", fn: "/", args: ints(3), want: builtin.NewRatio(big.NewRat(1, 3))},
+		{title: "Div", fn: "/", args: ints(12, 2, 3), want: builtin.Int64(2)},
+		{title: "Mod", fn: "mod", args: ints(-7, 2), want: builtin.Int64(1)},
+		{title: "Inc", fn: "inc", args: ints(1), want: builtin.Int64(2)},
+		{title: "Dec", fn: "dec", args: ints(1), want: builtin.Int64(0)},
+		{title: "LessThan", fn: "<", args: ints(1, 2, 3), want: builtin.Bool(true)},
+		{title: "LessThan_False", fn: "<", args: ints(1, 3, 2), want: builtin.Bool(false)},
+		{title: "GreaterThan", fn: ">", args: ints(3, 2, 1), want: builtin.Bool(true)},
+		{title: "LessOrEqual", fn: "<=", args: ints(1, 1, 2), want: builtin.Bool(true)},
+		{title: "GreaterOrEqual", fn: ">=", args: ints(2, 2, 3), want: builtin.Bool(false)},
+		{title: "Compare_Incomparable", fn: "<", args: []core.Any{builtin.Int64(1), builtin.Keyword("a")}, wantErr: core.ErrIncomparable},
+		{title: "Equals", fn: "=", args: []core.Any{builtin.Int64(1), builtin.Float64(1)}, want: builtin.Bool(true)},
+		{title: "Equals_NaN", fn: "=", args: []core.Any{builtin.Float64(math.NaN()), builtin.Float64(5)}, want: builtin.Bool(false)},
+		{title: "Equals_Seqs", fn: "=", args: []core.Any{list(ints(1, 2)...), list(ints(1, 2)...)}, want: builtin.Bool(true)},
+		{title: "NotEquals", fn: "not=", args: ints(1, 2), want: builtin.Bool(true)},
+		{title: "Not", fn: "not", args: []core.Any{builtin.Nil{}}, want: builtin.Bool(true)},
+		{title: "First", fn: "first", args: []core.Any{vec(ints(1, 2)...)}, want: builtin.Int64(1)},
+		{title: "First_Nil", fn: "first", args: []core.Any{builtin.Nil{}}, want: builtin.Nil{}},
+		{title: "First_Empty", fn: "first", args: []core.Any{list()}, want: builtin.Nil{}},
+		{title: "First_NotSeq", fn: "first", args: ints(1), wantErr: errors.New("value of type 'builtin.Int64' is not a sequence")},
+		{title: "Rest", fn: "rest", args: []core.Any{list(ints(1, 2)...)}, want: list(ints(2)...)},
+		{title: "Rest_Single", fn: "rest", args: []core.Any{vec(ints(1)...)}, want: list()},
+		{title: "Cons", fn: "cons", args: []core.Any{builtin.Int64(0), vec(ints(1)...)}, want: list(ints(0, 1)...)},
+		{title: "Conj_List", fn: "conj", args: []core.Any{list(ints(1)...), builtin.Int64(2)}, want: list(ints(2, 1)...)},
+		{title: "Conj_Vector", fn: "conj", args: []core.Any{vec(ints(1)...), builtin.Int64(2)}, want: vec(ints(1, 2)...)},
+		{title: "Conj_Nil", fn: "conj", args: []core.Any{builtin.Nil{}, builtin.Int64(1)}, want: list(ints(1)...)},
+		{title: "Conj_Map", fn: "conj", args: []core.Any{mustMap(t), vec(builtin.Keyword("a"), builtin.Int64(1))}, want: m},
+		{title: "Conj_MapInvalidEntry", fn: "conj", args: []core.Any{m, vec(builtin.Keyword("a"))}, wantErr: errors.New("conj: map entry must be a [key value] vector")},
+		{title: "Count_Vector", fn: "count", args: []core.Any{vec(ints(1, 2)...)}, want: builtin.Int64(2)},
+		{title: "Count_String", fn: "count", args: []core.Any{builtin.String("∂x")}, want: builtin.Int64(2)},
+		{title: "Count_Nil", fn: "count", args: []core.Any{builtin.Nil{}}, want: builtin.Int64(0)},
+		{title: "Nth_Vector", fn: "nth", args: []core.Any{vec(ints(1, 2)...), builtin.Int64(1)}, want: builtin.Int64(2)},
+		{title: "Nth_List", fn: "nth", args: []core.Any{list(ints(1, 2)...), builtin.Int64(1)}, want: builtin.Int64(2)},
+		{title: "Nth_OutOfBounds", fn: "nth", args: []core.Any{list(ints(1)...), builtin.Int64(1)}, wantErr: builtin.ErrIndexOutOfBounds},
+		{title: "Nth_NotFound", fn: "nth", args: []core.Any{vec(), builtin.Int64(0), builtin.Keyword("none")}, want: builtin.Keyword("none")},
+		{title: "Get_Map", fn: "get", args: []core.Any{m, builtin.Keyword("a")}, want: builtin.Int64(1)},
+		{title: "Get_MapNotFound", fn: "get", args: []core.Any{m, builtin.Keyword("b"), builtin.Int64(0)}, want: builtin.Int64(0)},
+		{title: "Get_Set", fn: "get", args: []core.Any{set, builtin.Keyword("a")}, want: builtin.Keyword("a")},
+		{title: "Get_Vector", fn: "get", args: []core.Any{vec(ints(1)...), builtin.Int64(1)}, want: builtin.Nil{}},
+		{title: "Map", fn: "map", args: []core.Any{fn("inc"), vec(ints(1, 2)...)}, want: list(ints(2, 3)...)},
+		{title: "Map_MultipleColls", fn: "map", args: []core.Any{fn("+"), vec(ints(1, 2)...), list(ints(10, 20, 30)...)}, want: list(ints(11, 22)...)},
+		{title: "Map_NotInvokable", fn: "map", args: []core.Any{builtin.Int64(1), vec(ints(1)...)}, wantErr: core.ErrNotInvokable},
+		{title: "Filter", fn: "filter", args: []core.Any{fn("identity"), vec(builtin.Nil{}, builtin.Int64(1), builtin.Bool(false))}, want: list(ints(1)...)},
+		{title: "Reduce", fn: "reduce", args: []core.Any{fn("+"), vec(ints(1, 2, 3)...)}, want: builtin.Int64(6)},
+		{title: "Reduce_Init", fn: "reduce", args: []core.Any{fn("conj"), vec(), list(ints(1, 2)...)}, want: vec(ints(1, 2)...)},
+		{title: "Reduce_Empty", fn: "reduce", args: []core.Any{fn("*"), vec()}, want: builtin.Int64(1)},
+		{title: "Range_End", fn: "range", args: ints(3), want: list(ints(0, 1, 2)...)},
+		{title: "Range_StartEndStep", fn: "range", args: ints(5, 0, -2), want: list(ints(5, 3, 1)...)},
+		{title: "Range_Empty", fn: "range", args: ints(3, 1), want: list()},
+		{title: "Range_Infinite", fn: "take", args: []core.Any{builtin.Int64(3), mustInvoke(t, fn("range"))}, want: list(ints(0, 1, 2)...)},
+		{title: "Range_NotNumber", fn: "range", args: []core.Any{builtin.Keyword("a")}, wantErr: core.ErrIncomparable},
+		{title: "Range_ZeroStep", fn: "range", args: ints(0, 1, 0), wantErr: errors.New("range: step must not be zero")},
+		{title: "Iterate", fn: "take", args: []core.Any{builtin.Int64(3), mustInvoke(t, fn("iterate"), fn("inc"), builtin.Int64(1))}, want: list(ints(1, 2, 3)...)},
+		{title: "Iterate_Error", fn: "take", args: []core.Any{builtin.Int64(2), mustInvoke(t, fn("iterate"), builtin.Int64(1), builtin.Int64(1))}, wantErr: core.ErrNotInvokable},
+		{title: "Repeat", fn: "repeat", args: []core.Any{builtin.Int64(2), builtin.Keyword("a")}, want: list(builtin.Keyword("a"), builtin.Keyword("a"))},
+		{title: "Repeat_Infinite", fn: "take", args: []core.Any{builtin.Int64(2), mustInvoke(t, fn("repeat"), builtin.Int64(1))}, want: list(ints(1, 1)...)},
+		{title: "Repeat_Negative", fn: "repeat", args: []core.Any{builtin.Int64(-1), builtin.Int64(1)}, want: list()},
+		{title: "Cycle", fn: "take", args: []core.Any{builtin.Int64(5), mustInvoke(t, fn("cycle"), vec(ints(1, 2)...))}, want: list(ints(1, 2, 1, 2, 1)...)},
+		{title: "Cycle_Empty", fn: "cycle", args: []core.Any{vec()}, want: list()},
+		{title: "Take", fn: "take", args: []core.Any{builtin.Int64(2), vec(ints(1, 2, 3)...)}, want: list(ints(1, 2)...)},
+		{title: "Drop", fn: "drop", args: []core.Any{builtin.Int64(2), vec(ints(1, 2, 3)...)}, want: list(ints(3)...)},
+		{title: "Drop_All", fn: "drop", args: []core.Any{builtin.Int64(5), list(ints(1)...)}, want: list()},
+		{title: "Concat", fn: "concat", args: []core.Any{vec(ints(1)...), builtin.Nil{}, list(ints(2, 3)...)}, want: list(ints(1, 2, 3)...)},
+		{title: "Str", fn: "str", args: []core.Any{builtin.String("a"), builtin.Char('b'), builtin.Nil{}, builtin.Int64(1), builtin.Keyword("k")}, want: builtin.String("ab1:k")},
+		{title: "Str_BigNumbers", fn: "str", args: []core.Any{builtin.NewBigInt(big.NewInt(42)), builtin.String(" "), mustDecimal(t, "1.50"), builtin.String(" "), builtin.NewRatio(big.NewRat(1, 3))}, want: builtin.String("42 1.50 1/3")},
+		{title: "Apply", fn: "apply", args: []core.Any{fn("+"), builtin.Int64(1), vec(ints(2, 3)...)}, want: builtin.Int64(6)},
+		{title: "Transduce", fn: "transduce", args: []core.Any{mustInvoke(t, fn("map"), fn("inc")), fn("+"), vec(ints(1, 2, 3)...)}, want: builtin.Int64(9)},
+		{title: "Transduce_Init", fn: "transduce", args: []core.Any{mustInvoke(t, fn("take"), builtin.Int64(2)), fn("+"), builtin.Int64(10), mustInvoke(t, fn("range"))}, want: builtin.Int64(11)},
+		{title: "Transduce_NotXform", fn: "transduce", args: []core.Any{fn("inc"), fn("+"), vec()}, wantErr: errors.New("transduce: expecting xform, not 'core.Fn'")},
+		{title: "Into", fn: "into", args: []core.Any{vec(ints(0)...), list(ints(1, 2)...)}, want: vec(ints(0, 1, 2)...)},
+		{title: "Into_Xform", fn: "into", args: []core.Any{vec(), mustInvoke(t, fn("comp"), mustInvoke(t, fn("filter"), fn("identity")), mustInvoke(t, fn("map"), fn("str"))), vec(builtin.Nil{}, builtin.Int64(1))}, want: vec(builtin.String("1"))},
+		{title: "Comp_Fns", fn: "apply", args: []core.Any{mustInvoke(t, fn("comp"), fn("inc"), fn("+")), vec(ints(1, 2)...)}, want: builtin.Int64(4)},
+		{title: "Comp_NoArgs", fn: "apply", args: []core.Any{mustInvoke(t, fn("comp")), vec(ints(1)...)}, want: builtin.Int64(1)},
+		{title: "Comp_Mixed", fn: "comp", args: []core.Any{mustInvoke(t, fn("map"), fn("inc")), fn("inc")}, wantErr: errors.New("comp: cannot compose xforms with functions")},
+		{title: "Identity", fn: "identity", args: ints(1), want: builtin.Int64(1)},
+		{title: "Identity_Arity", fn: "identity", args: ints(1, 2), wantErr: core.ErrArity},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := fns[tt.fn].(Fn).Invoke(tt.args...)
+			if seq, ok := got.(core.Seq); ok && err == nil && !core.IsCounted(seq) {
+				// realize lazy sequences to surface their errors.
+				_, err = seq.Count()
+			}
+			if tt.wantErr != nil {
+				assert.Error(t, err)
+				if !errors.Is(err, tt.wantErr) {
+					assert.EqualError(t, err, tt.wantErr.Error())
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			eq, err := core.Eq(tt.want, got)
+			assert.NoError(t, err)
+			assert.True(t, eq, "want=%#v\ngot=%#v", tt.want, got)
+		})
+	}
+}
+
+func TestFn_InvokeContext(t *testing.T) {
+	t.Parallel()
+
+	fns := Bindings()
+	rangeFn, take := fns["range"].(Fn), fns["take"].(Fn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	seq, err := rangeFn.InvokeContext(ctx, builtin.Int64(10))
+	assert.NoError(t, err)
+	cancel()
+	_, err = seq.(core.Seq).Count()
+	assert.True(t, errors.Is(err, context.Canceled))
+
+	ctx = core.WithMeter(context.Background(), core.NewMeter(core.Budget{MaxLength: 5}))
+	_, err = take.InvokeContext(ctx, builtin.Int64(1000000), mustInvoke(t, rangeFn))
+	assert.True(t, errors.Is(err, core.ErrBudgetExceeded))
+
+	ctx = core.WithMeter(context.Background(), core.NewMeter(core.Budget{MaxSteps: 5}))
+	seq, err = rangeFn.InvokeContext(ctx)
+	assert.NoError(t, err)
+	_, err = seq.(core.Seq).Count()
+	assert.True(t, errors.Is(err, core.ErrBudgetExceeded))
+
+	s, err := rangeFn.SExpr()
+	assert.NoError(t, err)
+	assert.Equal(t, "#fn[range]", s)
+}
+
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	fns := Bindings()
+	var calls int
+	inc := Fn{Name: "inc", Min: 1, Max: 1, Func: func(ctx context.Context, args ...core.Any) (core.Any, error) {
+		calls++
+		return builtin.Add(args[0], builtin.Int64(1))
+	}}
+
+	seq := mustInvoke(t, fns["map"], inc, mustInvoke(t, fns["range"]))
+	assert.Equal(t, 0, calls, "map must not realize the sequence")
+
+	got := mustInvoke(t, fns["take"], builtin.Int64(3), seq)
+	assert.Equal(t, 3, calls, "take must realize only the items taken")
+	eq, err := core.Eq(builtin.NewList(builtin.Int64(1), builtin.Int64(2), builtin.Int64(3)), got)
+	assert.NoError(t, err)
+	assert.True(t, eq)
+
+	mustInvoke(t, fns["take"], builtin.Int64(3), seq)
+	assert.Equal(t, 3, calls, "realized items must be cached")
+}
+
+func mustInvoke(t *testing.T, fn core.Any, args ...core.Any) core.Any {
+	v, err := fn.(Fn).Invoke(args...)
+	if err != nil {
+		t.Fatalf("Invoke(): %v", err)
+	}
+	return v
+}
+
+func mustMap(t *testing.T, kvs ...core.Any) builtin.PersistentMap {
+	m, err := builtin.NewMap(kvs...)
+	if err != nil {
+		t.Fatalf("NewMap(): %v", err)
+	}
+	return m
+}
+
+func mustDecimal(t *testing.T, s string) builtin.BigDecimal {
+	d, err := builtin.ParseBigDecimal(s)
+	if err != nil {
+		t.Fatalf("ParseBigDecimal(): %v", err)
+	}
+	return d
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"context"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+// add implements (+ x*). Returns 0 if there are no arguments.
+func add(_ context.Context, args ...core.Any) (core.Any, error) {
+	return fold(builtin.Add, builtin.Int64(0), args)
+}
+
+// sub implements (- x y*). Returns -x if there is only one argument.
+func sub(_ context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) == 1 {
+		return builtin.Neg(args[0])
+	}
+	return fold(builtin.Sub, args[0], args[1:])
+}
+
+// mul implements (* x*). Returns 1 if there are no arguments.
+func mul(_ context.Context, args ...core.Any) (core.Any, error) {
+	return fold(builtin.Mul, builtin.Int64(1), args)
+}
+
+// div implements (/ x y*). Returns 1/x if there is only one argument.
+func div(_ context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) == 1 {
+		return builtin.Div(builtin.Int64(1), args[0])
+	}
+	return fold(builtin.Div, args[0], args[1:])
+}
+
+// mod implements (mod x y).
+func mod(_ context.Context, args ...core.Any) (core.Any, error) {
+	return builtin.Mod(args[0], args[1])
+}
+
+// inc implements (inc x).
+func inc(_ context.Context, args ...core.Any) (core.Any, error) {
+	return builtin.Add(args[0], builtin.Int64(1))
+}
+
+// dec implements (dec x).
+func dec(_ context.Context, args ...core.Any) (core.Any, error) {
+	return builtin.Sub(args[0], builtin.Int64(1))
+}
+
+// compareAll returns a function that returns true if ok is true for the
+// comparison of every pair of consecutive arguments (e.g., (< 1 2 3)).
+func compareAll(ok func(c int) bool) func(ctx context.Context, args ...core.Any) (core.Any, error) {
+	return func(_ context.Context, args ...core.Any) (core.Any, error) {
+		if len(args) == 1 {
+			_, err := core.Compare(args[0], args[0])
+			return builtin.Bool(true), err
+		}
+
+		for i := 1; i < len(args); i++ {
+			c, err := core.Compare(args[i-1], args[i])
+			if err != nil {
+				return nil, err
+			} else if !ok(c) {
+				return builtin.Bool(false), nil
+			}
+		}
+		return builtin.Bool(true), nil
+	}
+}
+
+// equals implements (= x y*).
+func equals(_ context.Context, args ...core.Any) (core.Any, error) {
+	for i := 1; i < len(args); i++ {
+		eq, err := core.Eq(args[i-1], args[i])
+		if err != nil || !eq {
+			return builtin.Bool(false), err
+		}
+	}
+	return builtin.Bool(true), nil
+}
+
+// notEquals implements (not= x y*).
+func notEquals(ctx context.Context, args ...core.Any) (core.Any, error) {
+	eq, err := equals(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return !eq.(builtin.Bool), nil
+}
+
+// not implements (not x).
+func not(_ context.Context, args ...core.Any) (core.Any, error) {
+	return builtin.Bool(!builtin.IsTruthy(args[0])), nil
+}
+
+func fold(op func(a, b core.Any) (core.Any, error), acc core.Any, args []core.Any) (core.Any, error) {
+	var err error
+	for _, arg := range args {
+		if acc, err = op(acc, arg); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
@@ -0,0 +1,658 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+// first implements (first coll). Returns nil if coll is empty.
+func first(_ context.Context, args ...core.Any) (core.Any, error) {
+	seq, err := seqOf(args[0])
+	if err != nil || seq == nil {
+		return builtin.Nil{}, err
+	}
+
+	v, err := seq.First()
+	if err != nil {
+		return nil, err
+	} else if v == nil {
+		return builtin.Nil{}, nil
+	}
+	return v, nil
+}
+
+// rest implements (rest coll). Returns an empty list if coll has no items
+// after the first.
+func rest(_ context.Context, args ...core.Any) (core.Any, error) {
+	seq, err := seqOf(args[0])
+	if err != nil || seq == nil {
+		return builtin.NewList(), err
+	}
+
+	next, err := seq.Next()
+	if err != nil {
+		return nil, err
+	} else if next == nil {
+		return builtin.NewList(), nil
+	}
+	return next, nil
+}
+
+// cons implements (cons x coll).
+func cons(_ context.Context, args ...core.Any) (core.Any, error) {
+	seq, err := seqOf(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return builtin.Cons(args[0], seq)
+}
+
+// conj implements (conj coll x*). Items are added at the front of lists and
+// seqs and at the end of vectors. Items conjoined to a map must be [k v]
+// vectors or maps.
+func conj(_ context.Context, args ...core.Any) (core.Any, error) {
+	items := args[1:]
+	switch coll := args[0].(type) {
+	case nil, builtin.Nil:
+		return builtin.NewList().Conj(items...)
+
+	case core.Vector:
+		return coll.Conj(items...)
+
+	case core.Set:
+		return coll.Conj(items...)
+
+	case core.Map:
+		return conjMap(coll, items)
+
+	case core.Seq:
+		return coll.Conj(items...)
+	}
+
+	return nil, fmt.Errorf("conj: cannot conj to value of type '%s'", reflect.TypeOf(args[0]))
+}
+
+// count implements (count coll).
+func count(_ context.Context, args ...core.Any) (core.Any, error) {
+	switch coll := args[0].(type) {
+	case nil, builtin.Nil:
+		return builtin.Int64(0), nil
+
+	case builtin.String:
+		return builtin.Int64(len([]rune(coll))), nil
+
+	case interface{ Count() (int, error) }:
+		cnt, err := coll.Count()
+		return builtin.Int64(cnt), err
+	}
+
+	return nil, fmt.Errorf("count: not supported on value of type '%s'", reflect.TypeOf(args[0]))
+}
+
+// nth implements (nth coll index not-found?). Fails with an error wrapping
+// builtin.ErrIndexOutOfBounds if the index is out of bounds and not-found
+// is not given.
+func nth(_ context.Context, args ...core.Any) (core.Any, error) {
+	i, err := toInt("nth", args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := entryAt(args[0], i)
+	if errors.Is(err, builtin.ErrIndexOutOfBounds) && len(args) == 3 {
+		return args[2], nil
+	} else if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// get implements (get coll key not-found?). Returns not-found (or nil) if
+// the key is not present in the map, set or vector.
+func get(_ context.Context, args ...core.Any) (core.Any, error) {
+	var notFound core.Any = builtin.Nil{}
+	if len(args) == 3 {
+		notFound = args[2]
+	}
+
+	key := args[1]
+	switch coll := args[0].(type) {
+	case core.Map:
+		if found, err := coll.HasKey(key); err != nil || !found {
+			return notFound, err
+		}
+		return coll.EntryAt(key)
+
+	case core.Set:
+		if found, err := coll.Contains(key); err != nil || !found {
+			return notFound, err
+		}
+		return key, nil
+
+	case core.Vector:
+		i, ok := key.(builtin.Int64)
+		if !ok {
+			return notFound, nil
+		}
+
+		v, err := coll.EntryAt(int(i))
+		if errors.Is(err, builtin.ErrIndexOutOfBounds) {
+			return notFound, nil
+		}
+		return v, err
+	}
+
+	return notFound, nil
+}
+
+// mapFn implements (map f) and (map f coll colls*). Returns a lazy sequence
+// of the results of invoking f with the items at the same index in each of
+// the colls. Stops when any of the colls is exhausted. Returns an Xform that
+// maps the items with f if no coll is given.
+func mapFn(ctx context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) == 1 {
+		return mapping(args[0]), nil
+	}
+
+	seqs := make([]core.Seq, len(args)-1)
+	for i, coll := range args[1:] {
+		seq, err := seqOf(coll)
+		if err != nil {
+			return nil, err
+		}
+		seqs[i] = seq
+	}
+	return mapSeq(ctx, args[0], seqs), nil
+}
+
+func mapSeq(ctx context.Context, f core.Any, seqs []core.Seq) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		if err := checkStep(ctx); err != nil {
+			return nil, err
+		}
+
+		items := make([]core.Any, len(seqs))
+		rests := make([]core.Seq, len(seqs))
+		for i, seq := range seqs {
+			v, next, err := uncons(seq)
+			if err != nil || v == nil {
+				return nil, err
+			}
+			items[i], rests[i] = v, next
+		}
+
+		v, err := invoke(ctx, f, items...)
+		if err != nil {
+			return nil, err
+		}
+		return builtin.Cons(v, mapSeq(ctx, f, rests))
+	})
+}
+
+// filter implements (filter pred) and (filter pred coll). Returns a lazy
+// sequence of the items in coll for which pred returns a truthy value.
+// Returns an Xform that filters the items with pred if coll is not given.
+func filter(ctx context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) == 1 {
+		return filtering(args[0]), nil
+	}
+
+	seq, err := seqOf(args[1])
+	if err != nil {
+		return nil, err
+	}
+	return filterSeq(ctx, args[0], seq), nil
+}
+
+func filterSeq(ctx context.Context, pred core.Any, seq core.Seq) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		for {
+			if err := checkStep(ctx); err != nil {
+				return nil, err
+			}
+
+			v, next, err := uncons(seq)
+			if err != nil || v == nil {
+				return nil, err
+			}
+
+			ok, err := invoke(ctx, pred, v)
+			if err != nil {
+				return nil, err
+			} else if builtin.IsTruthy(ok) {
+				return builtin.Cons(v, filterSeq(ctx, pred, next))
+			}
+			seq = next
+		}
+	})
+}
+
+// reduce implements (reduce f coll) and (reduce f init coll). If init is
+// not given, the first item is used as init and (f) is returned if coll is
+// empty.
+func reduce(ctx context.Context, args ...core.Any) (core.Any, error) {
+	seq, err := seqOf(args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	var acc core.Any
+	if len(args) == 3 {
+		acc = args[1]
+	} else {
+		v, next, err := uncons(seq)
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			return invoke(ctx, args[0])
+		}
+		acc, seq = v, next
+	}
+
+	err = core.ForEach(seq, func(item core.Any) (bool, error) {
+		var err error
+		acc, err = invoke(ctx, args[0], acc, item)
+		return false, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// rangeFn implements (range), (range end), (range start end) and (range
+// start end step). Returns a lazy sequence of numbers from start (inclusive,
+// 0 by default) to end (exclusive) by step (1 by default). The sequence is
+// infinite if end is not given.
+func rangeFn(ctx context.Context, args ...core.Any) (core.Any, error) {
+	var start, end, step core.Any = builtin.Int64(0), nil, builtin.Int64(1)
+	switch len(args) {
+	case 1:
+		end = args[0]
+	case 2:
+		start, end = args[0], args[1]
+	case 3:
+		start, end, step = args[0], args[1], args[2]
+	}
+
+	dir, err := core.Compare(step, builtin.Int64(0))
+	if err != nil {
+		return nil, err
+	} else if dir == 0 {
+		return nil, errors.New("range: step must not be zero")
+	}
+
+	if end != nil {
+		if _, err := core.Compare(start, end); err != nil {
+			return nil, err
+		}
+	}
+	return rangeSeq(ctx, start, end, step, dir), nil
+}
+
+func rangeSeq(ctx context.Context, x, end, step core.Any, dir int) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		if err := checkStep(ctx); err != nil {
+			return nil, err
+		}
+
+		if end != nil {
+			if c, err := core.Compare(x, end); err != nil || c*dir >= 0 {
+				return nil, err
+			}
+		}
+
+		next, err := builtin.Add(x, step)
+		if err != nil {
+			return nil, err
+		}
+		return builtin.Cons(x, rangeSeq(ctx, next, end, step, dir))
+	})
+}
+
+// iterate implements (iterate f x). Returns an infinite lazy sequence of x,
+// (f x), (f (f x)) and so on.
+func iterate(ctx context.Context, args ...core.Any) (core.Any, error) {
+	return iterateSeq(ctx, args[0], args[1]), nil
+}
+
+func iterateSeq(ctx context.Context, f, x core.Any) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		if err := checkStep(ctx); err != nil {
+			return nil, err
+		}
+
+		next := builtin.NewLazySeq(func() (core.Any, error) {
+			v, err := invoke(ctx, f, x)
+			if err != nil {
+				return nil, err
+			}
+			return iterateSeq(ctx, f, v), nil
+		})
+		return builtin.Cons(x, next)
+	})
+}
+
+// repeat implements (repeat x) and (repeat n x). Returns a lazy sequence of
+// x repeated n times (infinitely if n is not given).
+func repeat(ctx context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) == 1 {
+		return repeatSeq(ctx, args[0], -1), nil
+	}
+
+	n, err := toInt("repeat", args[0])
+	if err != nil {
+		return nil, err
+	} else if n < 0 {
+		n = 0
+	}
+	return repeatSeq(ctx, args[1], n), nil
+}
+
+// repeatSeq returns a lazy sequence of x repeated n times. The sequence is
+// infinite if n is negative.
+func repeatSeq(ctx context.Context, x core.Any, n int) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		if n == 0 {
+			return nil, nil
+		} else if err := checkStep(ctx); err != nil {
+			return nil, err
+		}
+		return builtin.Cons(x, repeatSeq(ctx, x, n-1))
+	})
+}
+
+// cycle implements (cycle coll). Returns an infinite lazy sequence of the
+// items of coll repeated. Returns an empty sequence if coll is empty.
+func cycle(ctx context.Context, args ...core.Any) (core.Any, error) {
+	seq, err := seqOf(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return cycleSeq(ctx, seq, seq), nil
+}
+
+func cycleSeq(ctx context.Context, coll, seq core.Seq) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		if err := checkStep(ctx); err != nil {
+			return nil, err
+		}
+
+		v, next, err := uncons(seq)
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			// start over from the beginning of the coll.
+			if v, next, err = uncons(coll); err != nil || v == nil {
+				return nil, err
+			}
+		}
+		return builtin.Cons(v, cycleSeq(ctx, coll, next))
+	})
+}
+
+// take implements (take n) and (take n coll). Returns a list of the first n
+// items. Returns an Xform that stops after n items if coll is not given.
+func take(ctx context.Context, args ...core.Any) (core.Any, error) {
+	n, err := toInt("take", args[0])
+	if err != nil {
+		return nil, err
+	} else if len(args) == 1 {
+		return taking(n), nil
+	}
+
+	seq, err := seqOf(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var res []core.Any
+	for len(res) < n {
+		v, next, err := uncons(seq)
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			break
+		}
+
+		res = append(res, v)
+		if err := checkLength(ctx, len(res)); err != nil {
+			return nil, err
+		}
+		seq = next
+	}
+	return builtin.NewList(res...), nil
+}
+
+// drop implements (drop n coll). Returns the items after the first n.
+func drop(_ context.Context, args ...core.Any) (core.Any, error) {
+	n, err := toInt("drop", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	seq, err := seqOf(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < n && seq != nil; i++ {
+		v, next, err := uncons(seq)
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			break
+		}
+		seq = next
+	}
+
+	if seq == nil {
+		return builtin.NewList(), nil
+	}
+	return seq, nil
+}
+
+// concat implements (concat coll*). Returns a lazy sequence of the items
+// in all the colls.
+func concat(ctx context.Context, args ...core.Any) (core.Any, error) {
+	seqs := make([]core.Seq, len(args))
+	for i, coll := range args {
+		seq, err := seqOf(coll)
+		if err != nil {
+			return nil, err
+		}
+		seqs[i] = seq
+	}
+	return concatSeq(ctx, seqs), nil
+}
+
+func concatSeq(ctx context.Context, seqs []core.Seq) core.Seq {
+	return builtin.NewLazySeq(func() (core.Any, error) {
+		for len(seqs) > 0 {
+			if err := checkStep(ctx); err != nil {
+				return nil, err
+			}
+
+			v, next, err := uncons(seqs[0])
+			if err != nil {
+				return nil, err
+			} else if v != nil {
+				rest := append([]core.Seq{next}, seqs[1:]...)
+				return builtin.Cons(v, concatSeq(ctx, rest))
+			}
+			seqs = seqs[1:]
+		}
+		return nil, nil
+	})
+}
+
+// str implements (str x*). Returns the concatenation of the string values
+// of the arguments. nil is treated as an empty string, strings and chars
+// are used as is, big numbers are rendered without the reader suffixes
+// (e.g., 42 instead of 42N) and other values are rendered as s-expressions.
+func str(_ context.Context, args ...core.Any) (core.Any, error) {
+	var b strings.Builder
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case nil, builtin.Nil:
+
+		case builtin.String:
+			b.WriteString(string(v))
+
+		case builtin.Char:
+			b.WriteRune(rune(v))
+
+		case builtin.BigInt, builtin.BigDecimal, builtin.Ratio:
+			b.WriteString(v.(fmt.Stringer).String())
+
+		case core.SExpressable:
+			s, err := v.SExpr()
+			if err != nil {
+				return nil, err
+			}
+			b.WriteString(s)
+
+		default:
+			fmt.Fprintf(&b, "%v", v)
+		}
+	}
+	return builtin.String(b.String()), nil
+}
+
+// apply implements (apply f x* coll). f is invoked with the x values and
+// the items of coll as arguments.
+func apply(ctx context.Context, args ...core.Any) (core.Any, error) {
+	seq, err := seqOf(args[len(args)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := core.ToSlice(seq)
+	if err != nil {
+		return nil, err
+	}
+
+	fnArgs := append(append([]core.Any{}, args[1:len(args)-1]...), items...)
+	return invoke(ctx, args[0], fnArgs...)
+}
+
+// identity implements (identity x).
+func identity(_ context.Context, args ...core.Any) (core.Any, error) {
+	return args[0], nil
+}
+
+// seqOf returns the seq of the collection. Returns nil seq for nil.
+func seqOf(v core.Any) (core.Seq, error) {
+	switch coll := v.(type) {
+	case nil, builtin.Nil:
+		return nil, nil
+
+	case core.Seq:
+		return coll, nil
+
+	case core.Seqable:
+		return coll.Seq()
+
+	case builtin.String:
+		var chars []core.Any
+		for _, r := range coll {
+			chars = append(chars, builtin.Char(r))
+		}
+		return builtin.NewList(chars...), nil
+	}
+
+	return nil, fmt.Errorf("value of type '%s' is not a sequence", reflect.TypeOf(v))
+}
+
+// uncons returns the first item and the rest of the seq. Returns nil item
+// if the seq is empty.
+func uncons(seq core.Seq) (core.Any, core.Seq, error) {
+	if seq == nil {
+		return nil, nil, nil
+	}
+
+	v, err := seq.First()
+	if err != nil || v == nil {
+		return nil, nil, err
+	}
+
+	next, err := seq.Next()
+	return v, next, err
+}
+
+// entryAt returns the item at index i of the collection.
+func entryAt(coll core.Any, i int) (core.Any, error) {
+	if vec, ok := coll.(core.Vector); ok {
+		return vec.EntryAt(i)
+	}
+
+	seq, err := seqOf(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	for j := 0; i >= 0; j++ {
+		v, next, err := uncons(seq)
+		if err != nil {
+			return nil, err
+		} else if v == nil {
+			break
+		} else if j == i {
+			return v, nil
+		}
+		seq = next
+	}
+	return nil, fmt.Errorf("%w: %d", builtin.ErrIndexOutOfBounds, i)
+}
+
+func conjMap(m core.Map, items []core.Any) (core.Any, error) {
+	for _, item := range items {
+		var entries core.Seq
+		var err error
+		switch v := item.(type) {
+		case core.Map:
+			entries, err = v.Seq()
+
+		case core.Vector:
+			entries = builtin.NewList(v)
+
+		default:
+			return nil, fmt.Errorf("conj: cannot conj value of type '%s' to map", reflect.TypeOf(item))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = core.ForEach(entries, func(entry core.Any) (bool, error) {
+			vec, ok := entry.(core.Vector)
+			if !ok {
+				return false, errors.New("conj: map entry must be a [key value] vector")
+			} else if cnt, err := vec.Count(); err != nil || cnt != 2 {
+				return false, errors.New("conj: map entry must be a [key value] vector")
+			}
+
+			k, err := vec.EntryAt(0)
+			if err != nil {
+				return false, err
+			}
+
+			v, err := vec.EntryAt(1)
+			if err != nil {
+				return false, err
+			}
+
+			m, err = m.Assoc(k, v)
+			return false, err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
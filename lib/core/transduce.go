@@ -0,0 +1,156 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+// Xform is a transducer returned by map, filter and take when they are
+// called without a collection. Xforms are composed with comp and applied
+// with transduce and into. The functions of the Xform are invoked with the
+// context of the call that applies it.
+type Xform struct {
+	xf func(ctx context.Context) core.Transducer
+}
+
+// Transducer returns the transducer of the Xform for the context.
+func (x Xform) Transducer(ctx context.Context) core.Transducer { return x.xf(ctx) }
+
+// SExpr returns a string representation of the Xform.
+func (x Xform) SExpr() (string, error) { return "#xform", nil }
+
+func mapping(f core.Any) Xform {
+	return Xform{xf: func(ctx context.Context) core.Transducer {
+		return core.Mapping(func(item core.Any) (core.Any, error) {
+			return invoke(ctx, f, item)
+		})
+	}}
+}
+
+func filtering(pred core.Any) Xform {
+	return Xform{xf: func(ctx context.Context) core.Transducer {
+		return core.Filtering(func(item core.Any) (bool, error) {
+			ok, err := invoke(ctx, pred, item)
+			return builtin.IsTruthy(ok), err
+		})
+	}}
+}
+
+func taking(n int) Xform {
+	return Xform{xf: func(_ context.Context) core.Transducer { return core.Taking(n) }}
+}
+
+// comp implements (comp f*). Returns an Xform if all the arguments are
+// Xforms; items flow through them from left to right. Otherwise, returns a
+// function that invokes the functions from right to left, passing the
+// result of each to the next.
+func comp(_ context.Context, args ...core.Any) (core.Any, error) {
+	if len(args) == 0 {
+		return Fn{Name: "identity", Min: 1, Max: 1, Func: identity}, nil
+	}
+
+	xforms := make([]Xform, 0, len(args))
+	for _, arg := range args {
+		if x, ok := arg.(Xform); ok {
+			xforms = append(xforms, x)
+		}
+	}
+
+	if len(xforms) == len(args) {
+		return Xform{xf: func(ctx context.Context) core.Transducer {
+			xfs := make([]core.Transducer, len(xforms))
+			for i, x := range xforms {
+				xfs[i] = x.Transducer(ctx)
+			}
+			return core.Comp(xfs...)
+		}}, nil
+	} else if len(xforms) > 0 {
+		return nil, errors.New("comp: cannot compose xforms with functions")
+	}
+
+	return Fn{Name: "comp", Max: -1, Func: func(ctx context.Context, fnArgs ...core.Any) (core.Any, error) {
+		v, err := invoke(ctx, args[len(args)-1], fnArgs...)
+		for i := len(args) - 2; i >= 0 && err == nil; i-- {
+			v, err = invoke(ctx, args[i], v)
+		}
+		return v, err
+	}}, nil
+}
+
+// transduce implements (transduce xform f coll) and (transduce xform f init
+// coll). Reduces coll with f transformed by xform. If init is not given,
+// (f) is used as init.
+func transduce(ctx context.Context, args ...core.Any) (core.Any, error) {
+	x, err := toXform("transduce", args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	f := args[1]
+	var init core.Any
+	if len(args) == 4 {
+		init = args[2]
+	} else if init, err = invoke(ctx, f); err != nil {
+		return nil, err
+	}
+
+	return transduceSeq(ctx, x, args[len(args)-1], init, func(acc, item core.Any) (core.Any, bool, error) {
+		v, err := invoke(ctx, f, acc, item)
+		return v, false, err
+	})
+}
+
+// into implements (into to from) and (into to xform from). Returns to with
+// the items of from (transformed by xform) conjoined.
+func into(ctx context.Context, args ...core.Any) (core.Any, error) {
+	xf := Xform{xf: func(_ context.Context) core.Transducer { return core.Comp() }}
+	if len(args) == 3 {
+		x, err := toXform("into", args[1])
+		if err != nil {
+			return nil, err
+		}
+		xf = x
+	}
+
+	return transduceSeq(ctx, xf, args[len(args)-1], args[0], func(acc, item core.Any) (core.Any, bool, error) {
+		v, err := conj(ctx, acc, item)
+		if err != nil {
+			return nil, false, err
+		}
+
+		cnt, err := count(ctx, v)
+		if err != nil {
+			return nil, false, err
+		}
+		return v, false, checkLength(ctx, int(cnt.(builtin.Int64)))
+	})
+}
+
+// transduceSeq reduces the items of coll using rf transformed by the Xform.
+// Each item of coll is counted as a step.
+func transduceSeq(ctx context.Context, x Xform, coll, init core.Any, rf core.ReduceFunc) (core.Any, error) {
+	seq, err := seqOf(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	xrf := x.Transducer(ctx)(rf)
+	return core.Reduce(seq, init, func(acc, item core.Any) (core.Any, bool, error) {
+		if err := checkStep(ctx); err != nil {
+			return nil, false, err
+		}
+		return xrf(acc, item)
+	})
+}
+
+func toXform(name string, v core.Any) (Xform, error) {
+	if x, ok := v.(Xform); ok {
+		return x, nil
+	}
+	return Xform{}, fmt.Errorf("%s: expecting xform, not '%s'", name, reflect.TypeOf(v))
+}
@@ -0,0 +1,400 @@
+package slurp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"reflect"
+	"strings"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+	"github.com/spy16/slurp/reader"
+)
+
+// ModuleExt is the file extension of the slurp source files resolved by
+// require.
+const ModuleExt = ".slurp"
+
+var (
+	// ErrNoFS is returned by load and require when the interpreter has no
+	// filesystem configured (See WithFS).
+	ErrNoFS = errors.New("no filesystem configured")
+
+	// ErrCircularRequire is returned when modules require each other.
+	ErrCircularRequire = errors.New("circular require")
+)
+
+// WithFS sets the filesystem from which the load and require forms read
+// the source files. Use os.DirFS() for on-disk files or an embed.FS for
+// embedded files. If nil, load and require fail with ErrNoFS.
+func WithFS(fsys fs.FS) Option {
+	return func(ins *Interpreter) {
+		ins.fs = fsys
+	}
+}
+
+// LoadFile reads and evaluates all the forms in the file at the path in
+// the filesystem of the interpreter and returns the result of the last
+// form. Namespace switches made by the file do not change the current
+// namespace of the interpreter.
+func (ins *Interpreter) LoadFile(path string) (core.Any, error) {
+	ctx, done := ins.metered(context.Background())
+	defer done()
+
+	return ins.loadFile(ctx, path)
+}
+
+func (ins *Interpreter) loadFile(ctx context.Context, path string) (core.Any, error) {
+	if ins.fs == nil {
+		return nil, ErrNoFS
+	} else if !fs.ValidPath(path) {
+		return nil, fmt.Errorf("%w: invalid path '%s'", fs.ErrInvalid, path)
+	}
+
+	f, err := ins.fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// the file is evaluated in the current namespace of the caller. switching
+	// namespaces in the file affects only the evaluation of the file.
+	if cur := builtin.CurrentNS(core.ContextChild(ins.env, "<load>", ctx)); cur != nil {
+		ctx = builtin.WithCurrentNS(ctx, cur)
+	}
+
+	rd := reader.New(f)
+	rd.File = path
+
+	var res core.Any = builtin.Nil{}
+	for {
+		form, err := rd.One()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return res, nil
+			}
+			return nil, err
+		}
+
+		if res, err = ins.EvalContext(ctx, form); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Require loads the module with given name (e.g., foo.bar is loaded from
+// foo/bar.slurp) if it is not already loaded. Each module is loaded once.
+// Evaluations requiring a module that is being loaded wait for the load to
+// finish. Returns ErrCircularRequire if modules require each other, either
+// within an evaluation or across the evaluations that wait for each other.
+// A module that fails to load is loaded again when it is required next.
+func (ins *Interpreter) Require(module string) error {
+	ctx, done := ins.metered(context.Background())
+	defer done()
+
+	return ins.require(ctx, module)
+}
+
+func (ins *Interpreter) require(ctx context.Context, module string) error {
+	// modules being loaded by the evaluation are carried in the context so
+	// that concurrent evaluations requiring the same module are not taken
+	// as circular requires.
+	state, _ := ctx.Value(loadingKey{}).(loadState)
+	for i, m := range state.chain {
+		if m == module {
+			chain := append(append([]string(nil), state.chain[i:]...), module)
+			return fmt.Errorf("%w: %s", ErrCircularRequire, strings.Join(chain, " -> "))
+		}
+	}
+	if state.loader == nil {
+		state.loader = &loader{}
+	}
+
+	ins.mu.Lock()
+	if ml, found := ins.loaded[module]; found {
+		err := ins.waitLoad(ctx, state.loader, module, ml)
+		ins.mu.Unlock()
+		return err
+	}
+
+	ml := &moduleLoad{done: make(chan struct{}), owner: state.loader}
+	if ins.loaded == nil {
+		ins.loaded = map[string]*moduleLoad{}
+	}
+	ins.loaded[module] = ml
+	ins.mu.Unlock()
+
+	state.chain = append(append([]string(nil), state.chain...), module)
+	ctx = context.WithValue(ctx, loadingKey{}, state)
+
+	path := strings.ReplaceAll(module, ".", "/") + ModuleExt
+	if _, err := ins.loadFile(ctx, path); err != nil {
+		ml.err = fmt.Errorf("require %s: %w", module, err)
+	}
+
+	ins.mu.Lock()
+	defer ins.mu.Unlock()
+	if ml.err != nil {
+		delete(ins.loaded, module)
+	}
+	ml.owner = nil
+	close(ml.done)
+	return ml.err
+}
+
+// waitLoad waits for the module load by another evaluation to finish and
+// returns its error. Returns ErrCircularRequire if the evaluation loading
+// the module is waiting (directly or through other evaluations) for the
+// waiting loader. Must be called with ins.mu held.
+func (ins *Interpreter) waitLoad(ctx context.Context, l *loader, module string, ml *moduleLoad) error {
+	for other := ml.owner; other != nil; {
+		if other == l {
+			return fmt.Errorf("%w: %s is being loaded by a waiting evaluation", ErrCircularRequire, module)
+		} else if other.waiting == nil {
+			break
+		}
+		other = other.waiting.owner
+	}
+
+	l.waiting = ml
+	ins.mu.Unlock()
+	defer func() {
+		ins.mu.Lock()
+		l.waiting = nil
+	}()
+
+	select {
+	case <-ml.done:
+		return ml.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type loadingKey struct{}
+
+// loadState is the state of the module loading by an evaluation.
+type loadState struct {
+	chain  []string
+	loader *loader
+}
+
+// loader represents an evaluation loading modules.
+type loader struct {
+	waiting *moduleLoad // guarded by Interpreter.mu.
+}
+
+// moduleLoad tracks the loading of a module. done is closed once the load
+// is finished and err is set.
+type moduleLoad struct {
+	done  chan struct{}
+	err   error
+	owner *loader // guarded by Interpreter.mu; nil once done.
+}
+
+// parseLoad parses the (load path) form.
+func (ins *Interpreter) parseLoad(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: load", ErrParseSpecial)}
+
+	if args == nil {
+		return nil, e.With("requires exactly 1 argument, got 0")
+	}
+
+	if count, err := args.Count(); err != nil {
+		return nil, err
+	} else if count != 1 {
+		return nil, e.With(fmt.Sprintf("requires exactly 1 argument, got %d", count))
+	}
+
+	first, err := args.First()
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := a.Analyze(env, first)
+	if err != nil {
+		return nil, err
+	}
+
+	return loadExpr{ins: ins, Path: path}, nil
+}
+
+// parseRequire parses the (require spec+) form where each spec is a module
+// name or a vector of the form [module :as alias :refer [name*]]. Use
+// ':refer :all' to refer all names from the module.
+func (ins *Interpreter) parseRequire(_ core.Analyzer, _ core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: require", ErrParseSpecial)}
+
+	var items []core.Any
+	if args != nil {
+		var err error
+		if items, err = core.ToSlice(args); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, e.With("requires at least 1 module")
+	}
+
+	re := requireExpr{ins: ins}
+	for _, item := range items {
+		spec, err := parseRequireSpec(item)
+		if err != nil {
+			return nil, e.With(err.Error())
+		}
+		re.Specs = append(re.Specs, *spec)
+	}
+
+	return re, nil
+}
+
+func parseRequireSpec(form core.Any) (*requireSpec, error) {
+	vec, isVec := form.(core.Vector)
+	if !isVec {
+		name, err := nsName(form)
+		if err != nil {
+			return nil, err
+		}
+		return &requireSpec{Module: name}, nil
+	}
+
+	items, err := collSeq(vec)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := core.ToSlice(items)
+	if err != nil {
+		return nil, err
+	} else if len(opts) == 0 {
+		return nil, errors.New("empty require spec")
+	}
+
+	name, err := nsName(opts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &requireSpec{Module: name}
+	for i := 1; i < len(opts); i += 2 {
+		if i+1 >= len(opts) {
+			return nil, fmt.Errorf("missing value for option '%v'", opts[i])
+		}
+
+		switch opt, val := opts[i], opts[i+1]; opt {
+		case kwAs:
+			alias, ok := val.(builtin.Symbol)
+			if !ok {
+				return nil, fmt.Errorf("expecting symbol after :as, got '%s'", reflect.TypeOf(val))
+			}
+			spec.Alias = string(alias)
+
+		case builtin.Keyword("refer"):
+			if val == builtin.Keyword("all") {
+				spec.ReferAll = true
+				continue
+			}
+
+			names, ok := val.(core.Vector)
+			if !ok {
+				return nil, fmt.Errorf(
+					"expecting vector of symbols or :all after :refer, got '%s'", reflect.TypeOf(val))
+			}
+
+			cnt, err := names.Count()
+			if err != nil {
+				return nil, err
+			}
+
+			for j := 0; j < cnt; j++ {
+				item, err := names.EntryAt(j)
+				if err != nil {
+					return nil, err
+				}
+
+				sym, ok := item.(builtin.Symbol)
+				if !ok {
+					return nil, fmt.Errorf("expecting symbol in :refer, got '%s'", reflect.TypeOf(item))
+				}
+				spec.Refer = append(spec.Refer, string(sym))
+			}
+
+		default:
+			return nil, fmt.Errorf("unknown require option '%v'", opt)
+		}
+	}
+
+	return spec, nil
+}
+
+// loadExpr loads the file at the path (value of Path) when evaluated.
+type loadExpr struct {
+	ins  *Interpreter
+	Path core.Expr
+}
+
+func (le loadExpr) Eval(env core.Env) (core.Any, error) {
+	v, err := le.Path.Eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	path, ok := v.(builtin.String)
+	if !ok {
+		return nil, fmt.Errorf("load: path must be a string, not '%s'", reflect.TypeOf(v))
+	}
+
+	return le.ins.loadFile(core.ContextOf(env), string(path))
+}
+
+// requireExpr loads the modules (if required) and sets up the aliases and
+// refers in the current namespace when evaluated.
+type requireExpr struct {
+	ins   *Interpreter
+	Specs []requireSpec
+}
+
+type requireSpec struct {
+	Module   string
+	Alias    string
+	Refer    []string
+	ReferAll bool
+}
+
+func (re requireExpr) Eval(env core.Env) (core.Any, error) {
+	for _, spec := range re.Specs {
+		if err := re.ins.require(core.ContextOf(env), spec.Module); err != nil {
+			return nil, err
+		}
+
+		if spec.Alias == "" && len(spec.Refer) == 0 && !spec.ReferAll {
+			continue
+		}
+
+		cur := builtin.CurrentNS(env)
+		if cur == nil {
+			return nil, fmt.Errorf("%w: no current namespace", builtin.ErrNoNamespace)
+		}
+
+		ns, err := builtin.FindNS(env, spec.Module)
+		if err != nil {
+			return nil, err
+		}
+
+		if spec.Alias != "" {
+			cur.Alias(spec.Alias, ns)
+		}
+
+		if spec.ReferAll {
+			cur.Refer(ns)
+		} else if len(spec.Refer) > 0 {
+			cur.Refer(ns, spec.Refer...)
+		}
+	}
+
+	return builtin.Nil{}, nil
+}
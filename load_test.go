@@ -0,0 +1,286 @@
+package slurp
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+	"github.com/spy16/slurp/reader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFS = fstest.MapFS{
+	"math/util.slurp": {Data: []byte(`
+		(ns math.util)
+		(def loads (if (try loads (catch :default e nil)) (inc loads) 1))
+		(def twice (fn [x] (+ x x)))`)},
+	"app.slurp": {Data: []byte(`
+		(ns app (:require [math.util :as mu :refer [twice]]))
+		(def result [(twice 2) (mu/twice 3)])`)},
+	"cycle/a.slurp":  {Data: []byte(`(ns cycle.a (:require cycle.b))`)},
+	"cycle/b.slurp":  {Data: []byte(`(ns cycle.b (:require cycle.a))`)},
+	"broken.slurp":   {Data: []byte("(def x 1)\n(def y")},
+	"script.slurp":   {Data: []byte(`(def counter (inc (try counter (catch :default e 0)))) counter`)},
+	"noalias.slurp":  {Data: []byte(`(def x :noalias)`)},
+	"refall.slurp":   {Data: []byte(`(ns refall (:require [math.util :refer :all])) (def r (twice 5))`)},
+	"badspec.slurp":  {Data: []byte(`(require [math.util :as])`)},
+	"badalias.slurp": {Data: []byte(`(require [noalias :as n])`)},
+}
+
+func TestInterpreter_Require(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "RequireWithAliasAndRefer",
+			src:   `(require app) app/result`,
+			want:  builtin.NewVector(builtin.Int64(4), builtin.Int64(6)),
+		},
+		{
+			title: "RequireReferAll",
+			src:   `(require 'refall) refall/r`,
+			want:  builtin.Int64(10),
+		},
+		{
+			title: "RequireOnce",
+			src:   `(require math.util) (require math.util app) math.util/loads`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "RestoresNamespace",
+			src:   `(require app) (def x 1) user/x`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "LoadEvaluatesEveryTime",
+			src:   `(load "script.slurp") (load "script.slurp")`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title: "ConcurrentRequire",
+			src:   `(let [a (go (require app)) b (go (require app))] [(deref a) (deref b) app/result])`,
+			want:  builtin.NewVector(builtin.Nil{}, builtin.Nil{}, builtin.NewVector(builtin.Int64(4), builtin.Int64(6))),
+		},
+		{
+			title:   "CircularRequire",
+			src:     `(require cycle.a)`,
+			wantErr: ErrCircularRequire,
+		},
+		{
+			title:   "ModuleNotFound",
+			src:     `(require unknown)`,
+			wantErr: fs.ErrNotExist,
+		},
+		{
+			title:   "AliasUnknownNamespace",
+			src:     `(load "badalias.slurp")`,
+			wantErr: builtin.ErrNoNamespace,
+		},
+		{
+			title:   "InvalidSpec",
+			src:     `(load "badspec.slurp")`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "InvalidPath",
+			src:     `(load "../etc/passwd")`,
+			wantErr: fs.ErrInvalid,
+		},
+		{
+			title:   "NoArgs",
+			src:     `(require)`,
+			wantErr: ErrParseSpecial,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(WithCoreLib(), WithFS(testFS))
+			require.NoError(t, ins.Bind(testGlobals))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpreter_ConcurrentRequire(t *testing.T) {
+	t.Parallel()
+
+	t.Run("LoadsOnce", func(t *testing.T) {
+		const n = 8
+
+		var loads int32
+		release := make(chan struct{})
+		ins := New(WithFS(fstest.MapFS{
+			"slow.slurp": {Data: []byte(`(ns slow) (loading) (def x 1)`)},
+		}))
+		require.NoError(t, ins.Bind(map[string]core.Any{
+			"loading": Func("loading", func() {
+				atomic.AddInt32(&loads, 1)
+				<-release
+			}),
+		}))
+
+		var started, finished sync.WaitGroup
+		errs := make([]error, n)
+		for i := 0; i < n; i++ {
+			started.Add(1)
+			finished.Add(1)
+			go func(i int) {
+				defer finished.Done()
+				started.Done()
+				errs[i] = ins.Require("slow")
+			}(i)
+		}
+		started.Wait()
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		finished.Wait()
+
+		for _, err := range errs {
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), atomic.LoadInt32(&loads))
+
+		got, err := ins.EvalStr(`slow/x`)
+		require.NoError(t, err)
+		assert.Equal(t, builtin.Int64(1), got)
+	})
+
+	t.Run("CircularAcrossEvaluations", func(t *testing.T) {
+		var barrier sync.WaitGroup
+		barrier.Add(2)
+		ins := New(WithFS(fstest.MapFS{
+			"a.slurp": {Data: []byte(`(ns a) (sync) (require b)`)},
+			"b.slurp": {Data: []byte(`(ns b) (sync) (require a)`)},
+		}))
+		require.NoError(t, ins.Bind(map[string]core.Any{
+			"sync": Func("sync", func() {
+				barrier.Done()
+				barrier.Wait()
+			}),
+		}))
+
+		errCh := make(chan error, 2)
+		go func() { errCh <- ins.Require("a") }()
+		go func() { errCh <- ins.Require("b") }()
+
+		assert.ErrorIs(t, <-errCh, ErrCircularRequire)
+		assert.ErrorIs(t, <-errCh, ErrCircularRequire)
+	})
+
+	t.Run("RetriesFailedLoad", func(t *testing.T) {
+		fsys := fstest.MapFS{"flaky.slurp": {Data: []byte(`(ns flaky) (def x`)}}
+		ins := New(WithFS(fsys))
+
+		require.Error(t, ins.Require("flaky"))
+
+		fsys["flaky.slurp"] = &fstest.MapFile{Data: []byte(`(ns flaky) (def x 1)`)}
+		require.NoError(t, ins.Require("flaky"))
+	})
+}
+
+func TestInterpreter_LoadFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoFS", func(t *testing.T) {
+		_, err := New().EvalStr(`(require foo)`)
+		assert.ErrorIs(t, err, ErrNoFS)
+	})
+
+	t.Run("NamespaceIsPerEvaluation", func(t *testing.T) {
+		ins := New(WithCoreLib(), WithFS(fstest.MapFS{
+			"pause.slurp": {Data: []byte(`(ns pause) (deliver started true) (deref resume) (def x 1)`)},
+		}))
+		started, resume := builtin.NewPromise(), builtin.NewPromise()
+		require.NoError(t, ins.Bind(map[string]core.Any{"started": started, "resume": resume}))
+
+		errCh := make(chan error, 1)
+		go func() {
+			_, err := ins.LoadFile("pause.slurp")
+			errCh <- err
+		}()
+
+		_, err := started.Deref(context.Background())
+		require.NoError(t, err)
+
+		// the file is paused in namespace 'pause'.
+		assert.Equal(t, "user", ins.CurrentNS())
+		got, err := ins.EvalStr(`(def y 2) user/y`)
+		require.NoError(t, err)
+		assert.Equal(t, builtin.Int64(2), got)
+
+		resume.Deliver(true)
+		require.NoError(t, <-errCh)
+
+		got, err = ins.EvalStr(`[pause/x (try pause/y (catch ErrNotFound e :none))]`)
+		require.NoError(t, err)
+		assert.Equal(t, builtin.NewVector(builtin.Int64(1), builtin.Keyword("none")), got)
+	})
+
+	t.Run("ReaderErrorHasFile", func(t *testing.T) {
+		_, err := New(WithFS(testFS)).LoadFile("broken.slurp")
+		require.Error(t, err)
+
+		var rdErr reader.Error
+		require.ErrorAs(t, err, &rdErr)
+		assert.Equal(t, "broken.slurp", rdErr.Begin.File)
+	})
+}
+
+func TestInterpreter_StackTrace(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"main.slurp": {Data: []byte(`(def inner (fn [x] (undefined-fn x)))
+(def outer (fn [x]
+  (inc (inner x))))
+(outer 1)`)},
+		"tail.slurp": {Data: []byte(`(def inner (fn [x] (undefined-fn x)))
+(def tail (fn [] (inner 1)))
+(tail)`)},
+	}
+
+	table := map[string]string{
+		"main.slurp": "\n    at inner (inner x) (main.slurp:3:8)" +
+			"\n    at outer (outer 1) (main.slurp:4:1)",
+		"tail.slurp": "\n    at inner (inner 1) (tail.slurp:2:18)" +
+			"\n    at tail (tail) (tail.slurp:3:1)",
+	}
+
+	for file, want := range table {
+		ins := New(WithFS(fsys))
+		require.NoError(t, ins.Bind(testGlobals))
+
+		_, err := ins.LoadFile(file)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, core.ErrNotFound)
+		assert.Equal(t, err.Error()+want, fmt.Sprintf("%+v", err))
+	}
+
+	t.Run("EvalStr", func(t *testing.T) {
+		ins := New()
+		_, err := ins.EvalStr("(def f (fn [] (undefined-fn)))\n(f)")
+		require.Error(t, err)
+		assert.Equal(t, err.Error()+"\n    at f (f) (<string>:2:1)", fmt.Sprintf("%+v", err))
+	})
+}
@@ -0,0 +1,40 @@
+package slurp
+
+import "github.com/spy16/slurp/builtin"
+
+var (
+	// ProfilePure allows only the special forms needed to write pure
+	// expressions. No global bindings (i.e., root bindings and namespace
+	// vars) can be reached unless allowed using Profile.WithBindings.
+	ProfilePure = builtin.Profile{
+		Name:     "pure",
+		Specials: []string{"do", "if", "let", "quote"},
+		Bindings: []string{},
+	}
+
+	// ProfileRules allows functions, iteration and error handling on top of
+	// ProfilePure, but no special forms that define globals, spawn goroutines,
+	// load code or change namespaces. No global bindings can be reached unless
+	// allowed using Profile.WithBindings.
+	ProfileRules = builtin.Profile{
+		Name: "rules",
+		Specials: []string{
+			"do", "if", "let", "quote", "fn", "loop", "recur", "try", "throw",
+			"binding", "lazy-seq",
+		},
+		Bindings: []string{},
+	}
+
+	// ProfileFull allows all the special forms and global bindings.
+	ProfileFull = builtin.Profile{Name: "full"}
+)
+
+// WithProfile restricts the special forms and global bindings the code
+// evaluated by the interpreter can use. Disallowed forms are rejected during
+// analysis with an error that wraps builtin.ErrNotAllowed. The profile has
+// no effect if the analyzer is not a *builtin.Analyzer.
+func WithProfile(profile builtin.Profile) Option {
+	return func(ins *Interpreter) {
+		ins.profile = &profile
+	}
+}
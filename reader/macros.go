@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
+	"reflect"
 	"strconv"
 	"strings"
 
@@ -15,47 +17,68 @@ import (
 // or customize behavior of the reader.
 type Macro func(rd *Reader, init rune) (core.Any, error)
 
-// // TODO(enhancement):  implement slurp.Set
-// // SetReader implements the reader macro for reading set from source.
-// func SetReader(setEnd rune, factory func() slurp.Set) Macro {
-// 	return func(rd *Reader, _ rune) (core.Any, error) {
-// 		forms, err := rd.Container(setEnd, "Set")
-// 		if err != nil {
-// 			return nil, err
-// 		}
-// 		return factory().Conj(forms...), nil
-// 	}
-// }
-
-// // TODO(enhancement) implement slurp.Map
-// // MapReader returns a reader macro for reading map values from source. factory
-// // is used to construct the map and `Assoc` is called for every pair read.
-// func MapReader(mapEnd rune, factory func() slurp.Map) Macro {
-// 	return func(rd *Reader, _ rune) (core.Any, error) {
-// 		forms, err := rd.Container(mapEnd, "Map")
-// 		if err != nil {
-// 			return nil, err
-// 		}
-
-// 		if len(forms)%2 != 0 {
-// 			return nil, errors.New("expecting even number of forms within {}")
-// 		}
-
-// 		m := factory()
-// 		for i := 0; i < len(forms); i += 2 {
-// 			if m.HasKey(forms[i]) {
-// 				return nil, fmt.Errorf("duplicate key: %v", forms[i])
-// 			}
-
-// 			m, err = m.Assoc(forms[i], forms[i+1])
-// 			if err != nil {
-// 				return nil, err
-// 			}
-// 		}
-
-// 		return m, nil
-// 	}
-// }
+// SetReader implements the reader macro for reading set from source. factory
+// is used to construct the set and `Conj` is called for every form read.
+func SetReader(setEnd rune, factory func() core.Set) Macro {
+	return func(rd *Reader, _ rune) (core.Any, error) {
+		beginPos := rd.Position()
+
+		s := factory()
+		if err := rd.Container(setEnd, "Set", func(val core.Any) error {
+			found, err := s.Contains(val)
+			if err != nil {
+				return err
+			} else if found {
+				return fmt.Errorf("duplicate item: %v", val)
+			}
+
+			s, err = s.Conj(val)
+			return err
+		}); err != nil {
+			return nil, rd.annotateErr(err, beginPos)
+		}
+
+		return withPosition(s, beginPos)
+	}
+}
+
+// MapReader returns a reader macro for reading map values from source. factory
+// is used to construct the map and `Assoc` is called for every pair read.
+func MapReader(mapEnd rune, factory func() core.Map) Macro {
+	return func(rd *Reader, _ rune) (core.Any, error) {
+		beginPos := rd.Position()
+
+		var forms []core.Any
+		if err := rd.Container(mapEnd, "Map", func(val core.Any) error {
+			forms = append(forms, val)
+			return nil
+		}); err != nil {
+			return nil, rd.annotateErr(err, beginPos)
+		}
+
+		if len(forms)%2 != 0 {
+			return nil, rd.annotateErr(
+				errors.New("expecting even number of forms within {}"), beginPos)
+		}
+
+		m := factory()
+		for i := 0; i < len(forms); i += 2 {
+			found, err := m.HasKey(forms[i])
+			if err != nil {
+				return nil, rd.annotateErr(err, beginPos)
+			} else if found {
+				return nil, rd.annotateErr(
+					fmt.Errorf("duplicate key: %v", forms[i]), beginPos)
+			}
+
+			if m, err = m.Assoc(forms[i], forms[i+1]); err != nil {
+				return nil, rd.annotateErr(err, beginPos)
+			}
+		}
+
+		return withPosition(m, beginPos)
+	}
+}
 
 // UnmatchedDelimiter implements a reader macro that can be used to capture
 // unmatched delimiters such as closing parenthesis etc.
@@ -93,11 +116,40 @@ func readNumber(rd *Reader, init rune) (core.Any, error) {
 	decimalPoint := strings.ContainsRune(numStr, '.')
 	isRadix := strings.ContainsRune(numStr, 'r')
 	isScientific := strings.ContainsRune(numStr, 'e')
+	isRatio := strings.ContainsRune(numStr, '/')
 
 	switch {
-	case isRadix && (decimalPoint || isScientific):
+	case isRadix && (decimalPoint || isScientific || isRatio):
 		return nil, rd.annotateErr(ErrNumberFormat, beginPos)
 
+	case isRadix:
+		v, err := parseRadix(numStr)
+		if err != nil {
+			return nil, rd.annotateErr(err, beginPos)
+		}
+		return v, nil
+
+	case strings.HasSuffix(numStr, "N"):
+		v, ok := new(big.Int).SetString(strings.TrimSuffix(numStr, "N"), 0)
+		if !ok {
+			return nil, rd.annotateErr(ErrNumberFormat, beginPos)
+		}
+		return builtin.NewBigInt(v), nil
+
+	case strings.HasSuffix(numStr, "M"):
+		v, err := builtin.ParseBigDecimal(strings.TrimSuffix(numStr, "M"))
+		if err != nil {
+			return nil, rd.annotateErr(fmt.Errorf("%w (decimal): '%s'", ErrNumberFormat, numStr), beginPos)
+		}
+		return v, nil
+
+	case isRatio:
+		v, err := parseRatio(numStr)
+		if err != nil {
+			return nil, rd.annotateErr(err, beginPos)
+		}
+		return v, nil
+
 	case isScientific:
 		v, err := parseScientific(numStr)
 		if err != nil {
@@ -112,15 +164,15 @@ func readNumber(rd *Reader, init rune) (core.Any, error) {
 		}
 		return builtin.Float64(v), nil
 
-	case isRadix:
-		v, err := parseRadix(numStr)
-		if err != nil {
-			return nil, rd.annotateErr(err, beginPos)
-		}
-		return v, nil
-
 	default:
 		v, err := strconv.ParseInt(numStr, 0, 64)
+		if errors.Is(err, strconv.ErrRange) {
+			// too large for Int64, promote to BigInt.
+			bi, ok := new(big.Int).SetString(numStr, 0)
+			if ok {
+				return builtin.NewBigInt(bi), nil
+			}
+		}
 		if err != nil {
 			return nil, rd.annotateErr(ErrNumberFormat, beginPos)
 		}
@@ -238,7 +290,10 @@ func readList(rd *Reader, _ rune) (core.Any, error) {
 		return nil, rd.annotateErr(err, beginPos)
 	}
 
-	return builtin.NewList(forms...), nil
+	if len(forms) == 0 {
+		return builtin.NewList(), nil
+	}
+	return withPosition(builtin.NewList(forms...), beginPos)
 }
 
 func quoteFormReader(expandFunc string) Macro {
@@ -263,15 +318,144 @@ func quoteFormReader(expandFunc string) Macro {
 	}
 }
 
+// readUnquote implements the reader macro for '~' which reads the next form as
+// (unquote <form>) or as (unquote-splicing <form>) if followed by '@'.
+func readUnquote(rd *Reader, init rune) (core.Any, error) {
+	r, err := rd.NextRune()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, Error{Cause: ErrEOF}
+		}
+		return nil, err
+	}
+
+	if r == '@' {
+		return quoteFormReader("unquote-splicing")(rd, r)
+	}
+
+	rd.Unread(r)
+	return quoteFormReader("unquote")(rd, init)
+}
+
 // readVector implements the reader macro for reading vector from source.
 func readVector(rd *Reader, _ rune) (core.Any, error) {
 	const vecEnd = ']'
 
+	beginPos := rd.Position()
+
 	v := builtin.EmptyVector.Transient()
-	err := rd.Container(vecEnd, "Vector", func(val core.Any) error {
+	if err := rd.Container(vecEnd, "Vector", func(val core.Any) error {
 		v.Cons(val)
 		return nil
-	})
+	}); err != nil {
+		return nil, err
+	}
+
+	return withPosition(v.Persistent(), beginPos)
+}
+
+// readMeta implements the reader macro for '^' which attaches metadata to
+// the next form. Metadata can be a map, a keyword (^:foo is {:foo true})
+// or a symbol or string (^T is {:tag T}) and is merged into the existing
+// metadata of the form.
+func readMeta(rd *Reader, _ rune) (core.Any, error) {
+	beginPos := rd.Position()
+
+	metaForm, err := readNext(rd)
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos)
+	}
+
+	meta, err := toMeta(metaForm)
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos)
+	}
+
+	form, err := readNext(rd)
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos)
+	}
+
+	if m, ok := form.(core.Meta); ok && m.Meta() != nil {
+		merged := m.Meta()
+		seq, err := meta.Seq()
+		if err != nil {
+			return nil, err
+		}
 
-	return v.Persistent(), err
+		if err := core.ForEach(seq, func(item core.Any) (bool, error) {
+			entry := item.(core.Vector)
+			k, err := entry.EntryAt(0)
+			if err != nil {
+				return true, err
+			}
+			v, err := entry.EntryAt(1)
+			if err != nil {
+				return true, err
+			}
+			merged, err = merged.Assoc(k, v)
+			return false, err
+		}); err != nil {
+			return nil, rd.annotateErr(err, beginPos)
+		}
+		meta = merged
+	}
+
+	res, err := builtin.WithMeta(form, meta)
+	if err != nil {
+		return nil, rd.annotateErr(err, beginPos)
+	}
+	return res, nil
+}
+
+// readNext reads the next form for a prefix macro (e.g., '^'). Returns
+// ErrEOF if the stream ends before the form.
+func readNext(rd *Reader) (core.Any, error) {
+	form, err := rd.One()
+	if err != nil {
+		if err == io.EOF {
+			return nil, ErrEOF
+		}
+		return nil, err
+	}
+	return form, nil
+}
+
+func toMeta(form core.Any) (core.Map, error) {
+	switch f := form.(type) {
+	case core.Map:
+		if m, ok := f.(core.Meta); ok && m.Meta() != nil {
+			return m.WithMeta(nil).(core.Map), nil
+		}
+		return f, nil
+
+	case builtin.Keyword:
+		return builtin.NewMap(f, builtin.Bool(true))
+
+	case builtin.Symbol, builtin.String:
+		return builtin.NewMap(builtin.Keyword("tag"), f)
+
+	default:
+		return nil, fmt.Errorf("metadata must be a map, keyword, symbol or string, not '%s'",
+			reflect.TypeOf(form))
+	}
+}
+
+// withPosition attaches the position as {:file :line :col} metadata to the
+// form if the form supports metadata (See builtin.PositionOf).
+func withPosition(form core.Any, pos Position) (core.Any, error) {
+	m, ok := form.(core.Meta)
+	if !ok {
+		return form, nil
+	}
+
+	meta, err := builtin.NewMap(
+		builtin.MetaFile, builtin.String(pos.File),
+		builtin.MetaLine, builtin.Int64(pos.Ln),
+		builtin.MetaCol, builtin.Int64(pos.Col),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return m.WithMeta(meta), nil
 }
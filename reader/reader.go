@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/big"
 	"net"
 	"os"
 	"reflect"
@@ -63,11 +64,17 @@ func New(r io.Reader, opts ...Option) *Reader {
 			')':  UnmatchedDelimiter(),
 			'[':  readVector,
 			']':  UnmatchedDelimiter(),
+			'{':  MapReader('}', func() core.Map { return builtin.EmptyMap }),
+			'}':  UnmatchedDelimiter(),
 			'\'': quoteFormReader("quote"),
-			'~':  quoteFormReader("unquote"),
+			'~':  readUnquote,
 			'`':  quoteFormReader("syntax-quote"),
+			'^':  readMeta,
+			'@':  quoteFormReader("deref"),
+		},
+		dispatch: map[rune]Macro{
+			'{': SetReader('}', func() core.Set { return builtin.EmptySet }),
 		},
-		dispatch: map[rune]Macro{},
 	}
 
 	for _, option := range withDefaults(opts) {
@@ -274,7 +281,7 @@ func (rd *Reader) Token(init rune) (string, error) {
 
 // Container reads multiple forms until 'end' rune is reached. Should be used to read
 // collection types like List etc. formType is only used to annotate errors.
-func (rd Reader) Container(end rune, formType string, f func(core.Any) error) error {
+func (rd *Reader) Container(end rune, formType string, f func(core.Any) error) error {
 	for {
 		if err := rd.SkipSpaces(); err != nil {
 			if err == io.EOF {
@@ -410,15 +417,15 @@ func readUnicodeChar(token string, base int) (builtin.Char, error) {
 	return builtin.Char(num), nil
 }
 
-func parseRadix(numStr string) (builtin.Int64, error) {
+func parseRadix(numStr string) (core.Any, error) {
 	parts := strings.Split(numStr, "r")
 	if len(parts) != 2 {
-		return 0, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
+		return nil, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
 	}
 
 	base, err := strconv.ParseInt(parts[0], 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
+		return nil, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
 	}
 
 	repr := parts[1]
@@ -428,13 +435,47 @@ func parseRadix(numStr string) (builtin.Int64, error) {
 	}
 
 	v, err := strconv.ParseInt(repr, int(base), 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// too large for Int64, promote to BigInt.
+		if bi, ok := new(big.Int).SetString(repr, int(base)); ok {
+			return builtin.NewBigInt(bi), nil
+		}
+	}
 	if err != nil {
-		return 0, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
+		return nil, fmt.Errorf("%w (radix notation): '%s'", ErrNumberFormat, numStr)
 	}
 
 	return builtin.Int64(v), nil
 }
 
+// parseRatio parses a ratio of the form [+-]numerator/denominator. Ratios
+// are reduced to the lowest terms and integral ratios (e.g., 4/2) are read
+// as integers.
+func parseRatio(numStr string) (core.Any, error) {
+	parts := strings.Split(numStr, "/")
+	if len(parts) != 2 || parts[1] == "" || parts[1][0] < '0' || parts[1][0] > '9' {
+		return nil, fmt.Errorf("%w (ratio): '%s'", ErrNumberFormat, numStr)
+	}
+
+	num, ok := new(big.Int).SetString(parts[0], 10)
+	if !ok {
+		return nil, fmt.Errorf("%w (ratio): '%s'", ErrNumberFormat, numStr)
+	}
+
+	denom, ok := new(big.Int).SetString(parts[1], 10)
+	if !ok || denom.Sign() == 0 {
+		return nil, fmt.Errorf("%w (ratio): '%s'", ErrNumberFormat, numStr)
+	}
+
+	r := new(big.Rat).SetFrac(num, denom)
+	if !r.IsInt() {
+		return builtin.NewRatio(r), nil
+	} else if n := r.Num(); n.IsInt64() {
+		return builtin.Int64(n.Int64()), nil
+	}
+	return builtin.NewBigInt(r.Num()), nil
+}
+
 func parseScientific(numStr string) (builtin.Float64, error) {
 	parts := strings.Split(numStr, "e")
 	if len(parts) != 2 {
@@ -488,15 +529,4 @@ func inferFileName(rs io.Reader) string {
 
 // Position represents the positional information about a value read
 // by reader.
-type Position struct {
-	File string
-	Ln   int
-	Col  int
-}
-
-func (p Position) String() string {
-	if p.File == "" {
-		p.File = "<unknown>"
-	}
-	return fmt.Sprintf("%s:%d:%d", p.File, p.Ln, p.Col)
-}
+type Position = core.Position
@@ -3,6 +3,7 @@ package reader
 import (
 	"bytes"
 	"io"
+	"math/big"
 	"os"
 	"reflect"
 	"strings"
@@ -139,7 +140,7 @@ func TestReader_All(t *testing.T) {
 				builtin.Int64(10),
 				builtin.Char('a'),
 				builtin.Keyword("hello"),
-				builtin.NewVector(builtin.Keyword("foo"), builtin.String("bar")),
+				at(1, 61, builtin.NewVector(builtin.Keyword("foo"), builtin.String("bar"))),
 			},
 		},
 		{
@@ -215,12 +216,44 @@ func TestReader_One(t *testing.T) {
 			src:  "~(x 3)",
 			want: builtin.NewList(
 				builtin.Symbol("unquote"),
-				builtin.NewList(
+				listAt(1, 2,
 					builtin.Symbol("x"),
 					builtin.Int64(3),
 				),
 			),
 		},
+		{
+			name: "UnQuoteSplicing",
+			src:  "~@(x 3)",
+			want: builtin.NewList(
+				builtin.Symbol("unquote-splicing"),
+				listAt(1, 3,
+					builtin.Symbol("x"),
+					builtin.Int64(3),
+				),
+			),
+		},
+		{
+			name:    "UnQuoteEOF",
+			src:     "~",
+			wantErr: true,
+		},
+		{
+			name: "Deref",
+			src:  "@(x 3)",
+			want: builtin.NewList(
+				builtin.Symbol("deref"),
+				listAt(1, 2,
+					builtin.Symbol("x"),
+					builtin.Int64(3),
+				),
+			),
+		},
+		{
+			name:    "DerefEOF",
+			src:     "@",
+			wantErr: true,
+		},
 	})
 }
 
@@ -377,9 +410,93 @@ func TestReader_One_Number(t *testing.T) {
 			src:     "9.3.2",
 			wantErr: true,
 		},
+		{
+			name: "BigInt",
+			src:  "12N",
+			want: builtin.NewBigInt(big.NewInt(12)),
+		},
+		{
+			name: "NegativeHexBigInt",
+			src:  "-0xffN",
+			want: builtin.NewBigInt(big.NewInt(-255)),
+		},
+		{
+			name: "IntOverflowPromotesToBigInt",
+			src:  "9223372036854775808",
+			want: builtin.NewBigInt(new(big.Int).Lsh(big.NewInt(1), 63)),
+		},
+		{
+			name: "RadixOverflowPromotesToBigInt",
+			src:  "2r1" + strings.Repeat("0", 64),
+			want: builtin.NewBigInt(new(big.Int).Lsh(big.NewInt(1), 64)),
+		},
+		{
+			name:    "InvalidBigInt",
+			src:     "1.5N",
+			wantErr: true,
+		},
+		{
+			name: "BigDecimal",
+			src:  "1.50M",
+			want: builtin.NewBigDecimal(big.NewInt(150), 2),
+		},
+		{
+			name: "ScientificBigDecimal",
+			src:  "-15e-3M",
+			want: builtin.NewBigDecimal(big.NewInt(-15), 3),
+		},
+		{
+			name:    "InvalidBigDecimal",
+			src:     "0x10M",
+			wantErr: true,
+		},
+		{
+			name: "Ratio",
+			src:  "-2/6",
+			want: builtin.NewRatio(big.NewRat(-1, 3)),
+		},
+		{
+			name: "IntegralRatio",
+			src:  "4/2",
+			want: builtin.Int64(2),
+		},
+		{
+			name:    "RatioZeroDenominator",
+			src:     "1/0",
+			wantErr: true,
+		},
+		{
+			name:    "RatioSignedDenominator",
+			src:     "1/-3",
+			wantErr: true,
+		},
+		{
+			name:    "RatioWithDecimal",
+			src:     "1.5/3",
+			wantErr: true,
+		},
 	})
 }
 
+func TestReader_One_Number_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range []string{"123456789012345678901234567890N", "-0.050M", "1000M", "22/7", "-1/3"} {
+		got, err := New(strings.NewReader(src)).One()
+		if err != nil {
+			t.Fatalf("One(%q) error = %v", src, err)
+		}
+
+		s, err := got.(core.SExpressable).SExpr()
+		if err != nil {
+			t.Fatalf("SExpr() error = %v", err)
+		}
+		if s != src {
+			t.Errorf("SExpr() = %q, want %q", s, src)
+		}
+	}
+}
+
 func TestReader_One_String(t *testing.T) {
 	executeReaderTests(t, []readerTestCase{
 		{
@@ -535,12 +652,12 @@ func TestReader_One_List(t *testing.T) {
 		{
 			name: "ListWithOneEntry",
 			src:  `(help)`,
-			want: builtin.NewList(builtin.Symbol("help")),
+			want: listAt(1, 1, builtin.Symbol("help")),
 		},
 		{
 			name: "ListWithMultipleEntry",
 			src:  `(+ 0xF 3.1413)`,
-			want: builtin.NewList(
+			want: listAt(1, 1,
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
@@ -549,7 +666,7 @@ func TestReader_One_List(t *testing.T) {
 		{
 			name: "ListWithCommaSeparator",
 			src:  `(+,0xF,3.1413)`,
-			want: builtin.NewList(
+			want: listAt(1, 1,
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
@@ -561,7 +678,7 @@ func TestReader_One_List(t *testing.T) {
                       0xF
                       3.1413
 					)`,
-			want: builtin.NewList(
+			want: listAt(1, 1,
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
@@ -573,12 +690,20 @@ func TestReader_One_List(t *testing.T) {
                       0xF    ; hex representation of 15
                       3.1413 ; value of math constant pi
                   )`,
-			want: builtin.NewList(
+			want: listAt(1, 1,
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
 			),
 		},
+		{
+			name: "NestedMultiLine",
+			src:  "(a\n  (b))",
+			want: listAt(1, 1,
+				builtin.Symbol("a"),
+				listAt(2, 3, builtin.Symbol("b")),
+			),
+		},
 		{
 			name:    "UnexpectedEOF",
 			src:     "(+ 1 2 ",
@@ -592,30 +717,30 @@ func TestReader_One_Vector(t *testing.T) {
 		{
 			name: "EmptyVector",
 			src:  `[]`,
-			want: builtin.EmptyVector,
+			want: at(1, 1, builtin.EmptyVector),
 		},
 		{
 			name: "VectorWithOneEntry",
 			src:  `[help]`,
-			want: builtin.NewVector(builtin.Symbol("help")),
+			want: at(1, 1, builtin.NewVector(builtin.Symbol("help"))),
 		},
 		{
 			name: "VectorWithMultipleEntry",
 			src:  `[+ 0xF 3.1413]`,
-			want: builtin.NewVector(
+			want: at(1, 1, builtin.NewVector(
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
-			),
+			)),
 		},
 		{
 			name: "VectorWithCommaSeparator",
 			src:  `[+,0xF,3.1413]`,
-			want: builtin.NewVector(
+			want: at(1, 1, builtin.NewVector(
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
-			),
+			)),
 		},
 		{
 			name: "MultiLine",
@@ -623,11 +748,11 @@ func TestReader_One_Vector(t *testing.T) {
                       0xF
                       3.1413
 					]`,
-			want: builtin.NewVector(
+			want: at(1, 1, builtin.NewVector(
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
-			),
+			)),
 		},
 		{
 			name: "MultiLineWithComments",
@@ -635,11 +760,11 @@ func TestReader_One_Vector(t *testing.T) {
                       0xF    ; hex representation of 15
                       3.1413 ; value of math constant pi
                   ]`,
-			want: builtin.NewVector(
+			want: at(1, 1, builtin.NewVector(
 				builtin.Symbol("+"),
 				builtin.Int64(15),
 				builtin.Float64(3.1413),
-			),
+			)),
 		},
 		{
 			name:    "UnexpectedEOF",
@@ -649,6 +774,126 @@ func TestReader_One_Vector(t *testing.T) {
 	})
 }
 
+func TestReader_One_Map(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "EmptyMap",
+			src:  `{}`,
+			want: at(1, 1, builtin.EmptyMap),
+		},
+		{
+			name: "SimpleMap",
+			src:  `{:age 10}`,
+			want: at(1, 1, mustMap(t, builtin.Keyword("age"), builtin.Int64(10))),
+		},
+		{
+			name: "MultiLineWithComments",
+			src: `{:name "bob" ; name of the user
+                   :age  4}`,
+			want: at(1, 1, mustMap(t,
+				builtin.Keyword("name"), builtin.String("bob"),
+				builtin.Keyword("age"), builtin.Int64(4),
+			)),
+		},
+		{
+			name:    "OddNumberOfForms",
+			src:     `{:age 10 :name}`,
+			wantErr: true,
+		},
+		{
+			name:    "DuplicateKey",
+			src:     `{:age 10 :age 11}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     `{:age 10`,
+			wantErr: true,
+		},
+		{
+			name:    "UnmatchedDelimiter",
+			src:     `}`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_Meta(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "KeywordOnSymbol",
+			src:  `^:dynamic foo`,
+			want: withMeta(builtin.Symbol("foo"), builtin.Keyword("dynamic"), builtin.Bool(true)),
+		},
+		{
+			name: "TagOnSymbol",
+			src:  `^Int64 x`,
+			want: withMeta(builtin.Symbol("x"), builtin.Keyword("tag"), builtin.Symbol("Int64")),
+		},
+		{
+			name: "MapOnVector",
+			src:  `^{:doc "hello"} [1]`,
+			want: withMeta(at(1, 17, builtin.NewVector(builtin.Int64(1))),
+				builtin.Keyword("doc"), builtin.String("hello")),
+		},
+		{
+			name: "Nested",
+			src:  `^:a ^:b (x)`,
+			want: withMeta(listAt(1, 9, builtin.Symbol("x")),
+				builtin.Keyword("b"), builtin.Bool(true),
+				builtin.Keyword("a"), builtin.Bool(true)),
+		},
+		{
+			name:    "InvalidMeta",
+			src:     `^10 x`,
+			wantErr: true,
+		},
+		{
+			name:    "UnsupportedForm",
+			src:     `^:a 10`,
+			wantErr: true,
+		},
+		{
+			name:    "MissingForm",
+			src:     `^:a`,
+			wantErr: true,
+		},
+	})
+}
+
+func TestReader_One_Set(t *testing.T) {
+	executeReaderTests(t, []readerTestCase{
+		{
+			name: "EmptySet",
+			src:  `#{}`,
+			want: at(1, 2, builtin.EmptySet),
+		},
+		{
+			name: "SimpleSet",
+			src:  `#{:a 10}`,
+			want: at(1, 2, mustSet(t, builtin.Keyword("a"), builtin.Int64(10))),
+		},
+		{
+			name: "NestedCollections",
+			src:  `#{[1] #{}}`,
+			want: at(1, 2, mustSet(t,
+				at(1, 3, builtin.NewVector(builtin.Int64(1))),
+				at(1, 8, builtin.EmptySet),
+			)),
+		},
+		{
+			name:    "DuplicateItem",
+			src:     `#{:a :a}`,
+			wantErr: true,
+		},
+		{
+			name:    "UnexpectedEOF",
+			src:     `#{:a`,
+			wantErr: true,
+		},
+	})
+}
+
 type readerTestCase struct {
 	name    string
 	src     string
@@ -672,3 +917,56 @@ func executeReaderTests(t *testing.T, tests []readerTestCase) {
 		})
 	}
 }
+
+// listAt returns a list with the position (in a string source) set as the
+// reader does.
+func listAt(ln, col int, items ...core.Any) core.Any {
+	return at(ln, col, builtin.NewList(items...))
+}
+
+// at returns the form with the position (in a string source) set as the
+// reader does.
+func at(ln, col int, form core.Any) core.Any {
+	res, err := withPosition(form, Position{File: "<string>", Ln: ln, Col: col})
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+// withMeta returns the form with the key-value pairs added to its metadata.
+func withMeta(form core.Any, kvs ...core.Any) core.Any {
+	var meta core.Map = builtin.EmptyMap
+	if m, ok := form.(core.Meta); ok && m.Meta() != nil {
+		meta = m.Meta()
+	}
+
+	for i := 0; i < len(kvs); i += 2 {
+		var err error
+		if meta, err = meta.Assoc(kvs[i], kvs[i+1]); err != nil {
+			panic(err)
+		}
+	}
+
+	res, err := builtin.WithMeta(form, meta)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
+func mustMap(t *testing.T, kvs ...core.Any) builtin.PersistentMap {
+	m, err := builtin.NewMap(kvs...)
+	if err != nil {
+		t.Fatalf("NewMap(): %v", err)
+	}
+	return m
+}
+
+func mustSet(t *testing.T, items ...core.Any) builtin.PersistentSet {
+	s, err := builtin.NewSet(items...)
+	if err != nil {
+		t.Fatalf("NewSet(): %v", err)
+	}
+	return s
+}
@@ -1,7 +1,9 @@
 package slurp
 
 import (
+	"context"
 	"fmt"
+	"math/big"
 	"reflect"
 	"strings"
 
@@ -9,7 +11,10 @@ import (
 	"github.com/spy16/slurp/core"
 )
 
-var errType = reflect.TypeOf((*error)(nil)).Elem()
+var (
+	errType = reflect.TypeOf((*error)(nil)).Elem()
+	ctxType = reflect.TypeOf((*context.Context)(nil)).Elem()
+)
 
 // Value converts the given arbitrary Go value into a slurp compatible value
 // type with well defined behaviours. If no known equivalent type is found,
@@ -23,12 +28,24 @@ func Value(v interface{}) core.Any {
 		return expr
 	}
 
+	switch val := v.(type) {
+	case *big.Int:
+		return builtin.NewBigInt(val)
+
+	case *big.Rat:
+		return builtin.NewRatio(val)
+	}
+
 	rv := reflect.ValueOf(v)
 
 	switch rv.Kind() {
 	case reflect.Func:
 		return Func(fmt.Sprintf("%v", v), rv)
 
+	case reflect.Chan:
+		c, _ := builtin.ToChan(v)
+		return c
+
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return builtin.Int64(rv.Int())
 
@@ -51,7 +68,9 @@ func Value(v interface{}) core.Any {
 }
 
 // Func converts the given Go func value to a slurp Invokable value. Panics
-// if the given value is not of Func kind.
+// if the given value is not of Func kind. If the first parameter of the
+// func is a context.Context, the context of the evaluation is passed to
+// it and it does not count as an argument.
 func Func(name string, v interface{}) core.Invokable {
 	rv := reflect.ValueOf(v)
 	rt := rv.Type()
@@ -59,7 +78,12 @@ func Func(name string, v interface{}) core.Invokable {
 		panic("not a func")
 	}
 
-	minArgs := rt.NumIn()
+	first := 0
+	if rt.NumIn() > 0 && rt.In(0) == ctxType {
+		first = 1
+	}
+
+	minArgs := rt.NumIn() - first
 	if rt.IsVariadic() {
 		minArgs = minArgs - 1
 	}
@@ -74,6 +98,7 @@ func Func(name string, v interface{}) core.Invokable {
 		rv:         rv,
 		rt:         rt,
 		name:       name,
+		first:      first,
 		minArgs:    minArgs,
 		returnsErr: returnsErr,
 		lastOutIdx: lastOutIdx,
@@ -84,23 +109,31 @@ type funcWrapper struct {
 	rv         reflect.Value
 	rt         reflect.Type
 	name       string
+	first      int // 1 if the func accepts a context.Context.
 	minArgs    int
 	returnsErr bool
 	lastOutIdx int
 }
 
 func (fw *funcWrapper) Invoke(args ...core.Any) (core.Any, error) {
+	return fw.InvokeContext(context.Background(), args...)
+}
+
+func (fw *funcWrapper) InvokeContext(ctx context.Context, args ...core.Any) (core.Any, error) {
 	// allocate argument slice.
-	argCount := len(args)
+	argCount := len(args) + fw.first
 	argVals := make([]reflect.Value, argCount)
+	if fw.first > 0 {
+		argVals[0] = reflect.ValueOf(&ctx).Elem()
+	}
 
 	// populate reflect.Value version of each argument.
 	for i, arg := range args {
-		argVals[i] = reflect.ValueOf(arg)
+		argVals[fw.first+i] = reflect.ValueOf(arg)
 	}
 
 	// verify number of args match the required function parameters.
-	if err := fw.checkArgCount(len(argVals)); err != nil {
+	if err := fw.checkArgCount(len(args)); err != nil {
 		return nil, err
 	}
 
@@ -132,8 +165,8 @@ func (fw *funcWrapper) argNames() []string {
 
 	var argNames []string
 
-	i := 0
-	for ; i < fw.minArgs; i++ {
+	i := fw.first
+	for ; i < fw.first+fw.minArgs; i++ {
 		argNames = append(argNames, cleanArgName(fw.rt.In(i)))
 	}
 
@@ -43,7 +43,7 @@ func (r *Renderer) Print(val interface{}) (err error) {
 
 	switch val.(type) {
 	case error:
-		_, err = fmt.Fprintf(r.Err, "%#s\n", val)
+		_, err = fmt.Fprintf(r.Err, "%+v\n", val)
 	default:
 		_, err = fmt.Fprintf(r.Out, "%#s\n", val)
 	}
@@ -17,6 +17,12 @@ type Evaluator interface {
 	Eval(form core.Any) (core.Any, error)
 }
 
+// NSProvider can be implemented by the Evaluator to expose the active
+// namespace. The namespace is shown in the prompt if available.
+type NSProvider interface {
+	CurrentNS() string
+}
+
 // REPL implements a read-eval-print loop for a generic Runtime.
 type REPL struct {
 	exec        Evaluator
@@ -129,7 +135,10 @@ func (repl *REPL) read() ([]core.Any, error) {
 }
 
 func (repl *REPL) currentNS() string {
-	return "" // stub
+	if nsp, ok := repl.exec.(NSProvider); ok {
+		return nsp.CurrentNS()
+	}
+	return ""
 }
 
 func (repl *REPL) setPrompt(multiline bool) {
@@ -4,12 +4,19 @@ package slurp
 
 import (
 	"bytes"
+	"context"
+	"io/fs"
+	"sync"
 
 	"github.com/spy16/slurp/builtin"
 	"github.com/spy16/slurp/core"
+	corelib "github.com/spy16/slurp/lib/core"
 	"github.com/spy16/slurp/reader"
 )
 
+// defaultNS is the namespace that is current when the interpreter starts.
+const defaultNS = "user"
+
 // New returns a new slurp interpreter session.
 func New(opts ...Option) *Interpreter {
 	buf := bytes.Buffer{}
@@ -17,14 +24,67 @@ func New(opts ...Option) *Interpreter {
 		buf:    &buf,
 		reader: reader.New(&buf),
 	}
+	// forms are read from the strings passed to EvalStr.
+	ins.reader.File = "<string>"
 
 	for _, opt := range withDefaults(opts) {
 		opt(ins)
 	}
 
+	if ba, ok := ins.analyzer.(*builtin.Analyzer); ok && ins.profile != nil {
+		restricted := *ba
+		restricted.Profile = ins.profile
+		ins.analyzer = &restricted
+	}
+
+	if builtin.CurrentNS(ins.env) == nil {
+		_, _ = builtin.InNS(ins.env, defaultNS)
+	}
+
+	if ins.coreLib {
+		ins.bindMissing(corelib.Bindings())
+		ins.bindMissing(libBindings())
+	}
+
 	return ins
 }
 
+// bindMissing binds the values whose names are not already bound in the
+// env, so that the bindings of the host env (See WithEnv) are kept.
+func (ins *Interpreter) bindMissing(vals map[string]core.Any) {
+	for k, v := range vals {
+		if _, err := ins.env.Resolve(k); err == nil {
+			continue
+		}
+		_ = ins.env.Bind(k, v)
+	}
+}
+
+// libBindings returns the functions over the builtin types that are bound
+// along with the standard library by WithCoreLib.
+func libBindings() map[string]core.Any {
+	return map[string]core.Any{
+		"meta":      Func("meta", builtin.MetaOf),
+		"with-meta": Func("with-meta", builtin.WithMeta),
+		"deref":     Func("deref", builtin.Deref),
+		"promise":   Func("promise", builtin.NewPromise),
+		"deliver":   Func("deliver", builtin.Deliver),
+		"chan":      Func("chan", builtin.MakeChan),
+		">!":        Func(">!", builtin.Put),
+		"<!":        Func("<!", builtin.Take),
+		"close!":    Func("close!", builtin.CloseChan),
+		"alts!":     Func("alts!", builtin.Alts),
+
+		"atom":             Func("atom", builtin.MakeAtom),
+		"swap!":            Func("swap!", builtin.SwapAtom),
+		"reset!":           Func("reset!", builtin.ResetAtom),
+		"compare-and-set!": Func("compare-and-set!", builtin.CompareAndSetAtom),
+		"set-validator!":   Func("set-validator!", builtin.SetValidator),
+		"add-watch":        Func("add-watch", builtin.AddWatch),
+		"remove-watch":     Func("remove-watch", builtin.RemoveWatch),
+	}
+}
+
 // Option values can be used with New() to customise slurp instance
 // during initialisation.
 type Option func(ins *Interpreter)
@@ -35,31 +95,116 @@ type Interpreter struct {
 	buf      *bytes.Buffer
 	reader   *reader.Reader
 	analyzer core.Analyzer
+
+	fs     fs.FS
+	mu     sync.Mutex // guards loaded and usage.
+	loaded map[string]*moduleLoad
+
+	budget  *core.Budget
+	usage   core.Usage
+	profile *builtin.Profile
+	coreLib bool
 }
 
 // Eval performs syntax analysis of the given form to produce an Expr and
 // evaluates the Expr for result.
 func (ins *Interpreter) Eval(form core.Any) (core.Any, error) {
-	return core.Eval(ins.env, ins.analyzer, form)
+	return ins.EvalContext(context.Background(), form)
+}
+
+// EvalContext is same as Eval but the evaluation is done with the given
+// context. Cancellation of the context is checked before every invocation
+// and loop iteration, and the evaluation fails with an error wrapping the
+// ctx.Err() once the context is done. The context is also passed to the
+// Go funcs (See Func) that accept one. If a budget is set (See WithBudget),
+// each call to EvalContext is limited by it.
+func (ins *Interpreter) EvalContext(ctx context.Context, form core.Any) (core.Any, error) {
+	ctx, done := ins.metered(ctx)
+	defer done()
+
+	return core.Eval(core.ContextChild(ins.env, "<eval>", ctx), ins.analyzer, form)
+}
+
+// WithBindings returns a copy of the context in which the dynamic vars with
+// given names are bound to the values. Use the context with EvalContext to
+// set the bindings for a single evaluation. Returns an error that wraps
+// builtin.ErrNotDynamic if a name does not refer to a dynamic var.
+//
+//	ctx, err := ins.WithBindings(ctx, map[string]core.Any{"*user*": user})
+//	if err != nil { ... }
+//	res, err := ins.EvalContext(ctx, form)
+func (ins *Interpreter) WithBindings(ctx context.Context, bindings map[string]core.Any) (context.Context, error) {
+	vals := make(map[*builtin.Var]core.Any, len(bindings))
+	for name, v := range bindings {
+		dv, err := builtin.ResolveVar(ins.env, builtin.Symbol(name))
+		if err != nil {
+			return nil, err
+		}
+		vals[dv] = v
+	}
+	return builtin.WithBindings(ctx, vals), nil
+}
+
+// Usage returns the resources consumed by the last evaluation to finish.
+// Usage is measured only if a budget is set using WithBudget. To measure
+// each of the concurrent evaluations, pass a context with a meter of its
+// own (See core.WithMeter) to EvalContext and read the usage from the
+// meter instead.
+func (ins *Interpreter) Usage() core.Usage {
+	ins.mu.Lock()
+	defer ins.mu.Unlock()
+	return ins.usage
+}
+
+// metered returns a copy of the context that carries a new meter for the
+// budget if one is set and the context does not carry a meter already.
+// done records the usage and must be called after the evaluation.
+func (ins *Interpreter) metered(ctx context.Context) (_ context.Context, done func()) {
+	if ins.budget == nil || core.MeterOf(ctx) != nil {
+		return ctx, func() {}
+	}
+
+	meter := core.NewMeter(*ins.budget)
+	return core.WithMeter(ctx, meter), func() {
+		ins.mu.Lock()
+		defer ins.mu.Unlock()
+		ins.usage = meter.Usage()
+	}
 }
 
-// EvalStr reads forms from the given string and evaluates it for result.
+// EvalStr reads forms from the given string and evaluates them one after
+// the other. Result of the last form is returned. Since each form is
+// analyzed only after the previous form is evaluated, macros and namespace
+// changes take effect for the forms that follow. The budget (if set) is
+// shared by all the forms.
 func (ins *Interpreter) EvalStr(s string) (core.Any, error) {
 	if _, err := ins.buf.WriteString(s); err != nil {
 		return nil, err
 	}
 
-	f, err := ins.reader.All()
+	forms, err := ins.reader.All()
 	if err != nil {
 		return nil, err
 	}
 
-	do, err := builtin.Cons(builtin.Symbol("do"), builtin.NewList(f...))
-	if err != nil {
-		return nil, err
+	ctx, done := ins.metered(context.Background())
+	defer done()
+
+	var res core.Any = builtin.Nil{}
+	for _, form := range forms {
+		if res, err = ins.EvalContext(ctx, form); err != nil {
+			return nil, err
+		}
 	}
+	return res, nil
+}
 
-	return ins.Eval(do)
+// CurrentNS returns the name of the current namespace.
+func (ins *Interpreter) CurrentNS() string {
+	if ns := builtin.CurrentNS(ins.env); ns != nil {
+		return ns.Name()
+	}
+	return ""
 }
 
 // Bind can be used to set global bindings that will be available while
@@ -92,14 +237,29 @@ func WithAnalyzer(a core.Analyzer) Option {
 		if a == nil {
 			a = &builtin.Analyzer{
 				Specials: map[string]builtin.ParseSpecial{
-					"go":    parseGo,
-					"do":    parseDo,
-					"if":    parseIf,
-					"fn":    parseFn,
-					"def":   parseDef,
-					"let":   parseLet,
-					"macro": parseMacro,
-					"quote": parseQuote,
+					"go":               parseGo,
+					"ns":               parseNS,
+					"in-ns":            parseInNS,
+					"refer":            parseRefer,
+					"require":          ins.parseRequire,
+					"alias":            parseAlias,
+					"binding":          parseBinding,
+					"do":               parseDo,
+					"if":               parseIf,
+					"fn":               parseFn,
+					"def":              parseDef,
+					"let":              parseLet,
+					"lazy-seq":         parseLazySeq,
+					"load":             ins.parseLoad,
+					"loop":             parseLoop,
+					"recur":            parseRecur,
+					"macro":            parseMacro,
+					"quote":            parseQuote,
+					"syntax-quote":     parseSyntaxQuote,
+					"throw":            parseThrow,
+					"try":              parseTry,
+					"unquote":          parseUnquote,
+					"unquote-splicing": parseUnquote,
 				},
 			}
 		}
@@ -107,6 +267,28 @@ func WithAnalyzer(a core.Analyzer) Option {
 	}
 }
 
+// WithBudget limits the steps, call depth and collection length of every
+// evaluation. An evaluation exceeding the budget fails with an error that
+// wraps core.ErrBudgetExceeded. Useful for evaluating untrusted code.
+func WithBudget(budget core.Budget) Option {
+	return func(ins *Interpreter) {
+		ins.budget = &budget
+	}
+}
+
+// WithCoreLib binds the arithmetic, comparison and sequence functions of
+// the standard library (See package lib/core) in the root env, along with
+// the functions over the builtin types: metadata (meta and with-meta),
+// futures and promises (deref, promise and deliver), channels (chan, >!,
+// <!, close! and alts!) and atoms (atom, swap!, reset! etc.). Note that
+// restricted profiles (See WithProfile) must allow the bindings to be used.
+// Names already bound in the env (See WithEnv) are not rebound.
+func WithCoreLib() Option {
+	return func(ins *Interpreter) {
+		ins.coreLib = true
+	}
+}
+
 func withDefaults(opts []Option) []Option {
 	return append([]Option{
 		WithAnalyzer(nil),
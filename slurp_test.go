@@ -0,0 +1,1299 @@
+package slurp
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+	"github.com/spy16/slurp/reader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterpreter_EvalStr(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		setup   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "Closure",
+			src:   `(((fn [a] (fn [] a)) 5))`,
+			want:  builtin.Int64(5),
+		},
+		{
+			title: "ClosureOverLet",
+			src:   `(let [x 1] ((fn [] x)))`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "MultiArityFn",
+			src: `(def f (fn f
+			           ([] :none)
+			           ([a] a)
+			           ([a b] b)))
+			      [(f) (f 1) (f 1 2)]`,
+			want: builtin.NewVector(builtin.Keyword("none"), builtin.Int64(1), builtin.Int64(2)),
+		},
+		{
+			title:   "MultiArityFn_NoMatch",
+			src:     `((fn ([] 1) ([a b] 2)) 1)`,
+			wantErr: core.ErrArity,
+		},
+		{
+			title: "VariadicFn",
+			src:   `((fn [a & more] [a more]) 1 2 3)`,
+			want:  builtin.NewVector(builtin.Int64(1), builtin.NewList(builtin.Int64(2), builtin.Int64(3))),
+		},
+		{
+			title: "VariadicFn_NoRestArgs",
+			src:   `((fn [a & more] more) 1)`,
+			want:  builtin.Nil{},
+		},
+		{
+			title: "VariadicMacro",
+			setup: `(def when (macro [test & body] (list 'if test (cons 'do body))))`,
+			src:   `(when true 1 2 3)`,
+			want:  builtin.Int64(3),
+		},
+		{
+			title: "Loop",
+			src: `(loop [i 0 acc 0]
+			        (if (< i 100000)
+			          (recur (inc i) (+ acc i))
+			          acc))`,
+			want: builtin.Int64(4999950000),
+		},
+		{
+			title: "LoopDestructure",
+			src: `(loop [[x & xs] [1 2 3] acc 0]
+			        (if x (recur xs (+ acc x)) acc))`,
+			want: builtin.Int64(6),
+		},
+		{
+			title: "LoopDestructure_LaterBindings",
+			src:   `(loop [[a] [1] b a [c] [(inc b)] a 10] [a b c])`,
+			want:  builtin.NewVector(builtin.Int64(10), builtin.Int64(1), builtin.Int64(2)),
+		},
+		{
+			title: "LoopDestructure_Recur",
+			src: `(loop [[a] [0] b (inc a)]
+			        (if (< b 5) (recur [b] (inc b)) [a b]))`,
+			want: builtin.NewVector(builtin.Int64(4), builtin.Int64(5)),
+		},
+		{
+			title: "LoopClosures",
+			src: `(loop [i 0 f nil]
+			        (if (< i 3) (recur (inc i) (if f f (fn [] i))) (f)))`,
+			want: builtin.Int64(0),
+		},
+		{
+			title: "FnRecur",
+			src: `((fn [n acc] (if (< n 1) acc (recur (dec n) (+ acc n))))
+			       100000 0)`,
+			want: builtin.Int64(5000050000),
+		},
+		{
+			title: "FnRecur_OtherArity",
+			src: `((fn ([n] (recur n 0))
+			           ([n acc] (if (< n 1) acc (recur (dec n) (+ acc n))))) 10)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "RecurOutsideLoop",
+			src:     `(recur 1)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "RecurNotInTail",
+			src:     `(loop [i 0] (inc (recur i)))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "RecurInIfTest",
+			src:     `(loop [i 0] (if (recur i) 1 2))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "RecurInLetValue",
+			src:     `(loop [i 0] (let [x (recur i)] x))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "RecurArity",
+			src:     `(loop [i 0] (recur))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "MutualTailCalls",
+			setup: `(def even? (fn [n] (if (< n 1) true (odd? (dec n)))))
+			        (def odd? (fn [n] (if (< n 1) false (even? (dec n)))))`,
+			src:  `(even? 100001)`,
+			want: builtin.Bool(false),
+		},
+		{
+			title: "TailCallFromLetAndDo",
+			setup: `(def count-down (fn [n] (let [m (dec n)] (do 1 (if (< m 0) :done (count-down m))))))`,
+			src:   `(count-down 100000)`,
+			want:  builtin.Keyword("done"),
+		},
+		{
+			title: "TailCallFromLoop",
+			setup: `(def f (fn [n] (loop [i 0] (if (< i 1) (recur (inc i)) (if (< n 1) n (f (dec n)))))))`,
+			src:   `(f 100000)`,
+			want:  builtin.Int64(0),
+		},
+		{
+			title: "TryNoError",
+			src:   `(try 1 2 (catch :default e 3))`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title: "TryCatchCause",
+			src:   `(try ((fn [a] a)) (catch ErrNotFound e :not-found) (catch ErrArity e :arity))`,
+			want:  builtin.Keyword("arity"),
+		},
+		{
+			title: "TryCatchThrownValue",
+			src:   `(try (throw :oops) (catch Int64 e :int) (catch Keyword e e))`,
+			want:  builtin.Keyword("oops"),
+		},
+		{
+			title: "TryRethrow",
+			src:   `(try (try (throw 1) (catch :default e (throw e))) (catch Int64 e (inc e)))`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title: "TryFinally",
+			src:   `[(try (throw 1) (catch :default e e) (finally (def fin true))) fin]`,
+			want:  builtin.NewVector(builtin.Int64(1), builtin.Bool(true)),
+		},
+		{
+			title:   "TryUncaught",
+			src:     `(try (throw :oops) (catch ErrArity e 1))`,
+			wantErr: core.ErrThrown,
+		},
+		{
+			title:   "TryCatchAfterFinally",
+			src:     `(try 1 (finally 2) (catch :default e 3))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "TryBodyAfterCatch",
+			src:     `(try 1 (catch :default e 3) 4)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "TryCatchNoName",
+			src:     `(try 1 (catch :default))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "TryRecur",
+			src:     `(loop [i 0] (try (recur i)))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "ThrowNoArgs",
+			src:     `(throw)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "NamespaceDef",
+			src:   `(ns foo) (def x 1) (in-ns user) (def x 2) [x foo/x]`,
+			want:  builtin.NewVector(builtin.Int64(2), builtin.Int64(1)),
+		},
+		{
+			title: "NamespaceReferAll",
+			src:   `(ns foo) (def x 1) (def y 2) (ns user (:refer foo)) [x y]`,
+			want:  builtin.NewVector(builtin.Int64(1), builtin.Int64(2)),
+		},
+		{
+			title: "NamespaceReferNames",
+			src:   `(ns foo) (def x 1) (def y 2) (in-ns 'user) (refer 'foo 'x) [x (try y (catch ErrNotFound e :none))]`,
+			want:  builtin.NewVector(builtin.Int64(1), builtin.Keyword("none")),
+		},
+		{
+			title: "NamespaceAlias",
+			src:   `(ns foo.bar) (def x 1) (ns user (:alias fb foo.bar)) fb/x`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "NamespaceMacro",
+			setup: `(ns macros) (def unless (macro [test & body] ` + "`" + `(if ~test nil (do ~@body))))`,
+			src:   `(ns user (:refer macros unless)) (unless false :ok)`,
+			want:  builtin.Keyword("ok"),
+		},
+		{
+			title: "NamespaceGlobalsVisible",
+			src:   `(ns foo) (inc 1)`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title:   "NamespaceReferUnknown",
+			src:     `(refer unknown)`,
+			wantErr: builtin.ErrNoNamespace,
+		},
+		{
+			title:   "NamespaceInvalidClause",
+			src:     `(ns foo (:unknown bar))`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "NamespaceInvalidName",
+			src:     `(in-ns 1)`,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "RecurTargetsInnermost",
+			src: `(loop [i 0]
+			        (if (< i 2)
+			          (recur (inc i))
+			          ((fn [a b] (if (< a 1) b (recur (dec a) (+ b 1)))) 3 i)))`,
+			want: builtin.Int64(5),
+		},
+		{
+			title: "DefWithMeta",
+			src:   `(def ^:private x 10) x`,
+			want:  builtin.Int64(10),
+		},
+		{
+			title: "FnParamsWithMeta",
+			src:   `((fn ^:foo add [^Int64 a ^Int64 b] (+ a b)) 1 2)`,
+			want:  builtin.Int64(3),
+		},
+		{
+			title: "LetBindingWithMeta",
+			src:   `(let [^String s "a" [^Int64 n] [1]] [s n])`,
+			want:  builtin.NewVector(builtin.String("a"), builtin.Int64(1)),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New()
+			require.NoError(t, ins.Bind(testGlobals))
+
+			if tt.setup != "" {
+				_, err := ins.EvalStr(tt.setup)
+				require.NoError(t, err)
+			}
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+var testGlobals = map[string]core.Any{
+	"list": Func("list", builtin.NewList),
+	"cons": Func("cons", builtin.Cons),
+	"inc":  Func("inc", func(i builtin.Int64) builtin.Int64 { return i + 1 }),
+	"dec":  Func("dec", func(i builtin.Int64) builtin.Int64 { return i - 1 }),
+	"+":    Func("+", func(a, b builtin.Int64) builtin.Int64 { return a + b }),
+	"<":    Func("<", func(a, b builtin.Int64) builtin.Bool { return a < b }),
+
+	"Int64":   reflect.TypeOf(builtin.Int64(0)),
+	"Keyword": reflect.TypeOf(builtin.Keyword("")),
+}
+
+func TestInterpreter_Meta(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title string
+		src   string
+		want  core.Any
+	}{
+		{
+			title: "WithMeta",
+			src:   `(meta (with-meta [1 2] {:a 1}))`,
+			want:  mustMap(t, builtin.Keyword("a"), builtin.Int64(1)),
+		},
+		{
+			title: "MetaReaderMacro",
+			src:   `(meta ^:foo [1])`,
+			want:  mustMap(t, builtin.Keyword("foo"), builtin.Bool(true)),
+		},
+		{
+			title: "MetaQuotedSymbol",
+			src:   `(meta '^{:doc "x"} x)`,
+			want:  mustMap(t, builtin.Keyword("doc"), builtin.String("x")),
+		},
+		{
+			title: "MetaLiteralWithoutPosition",
+			src:   `(meta [1 2])`,
+			want:  builtin.Nil{},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := New(WithCoreLib()).EvalStr(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("NotBoundWithoutCoreLib", func(t *testing.T) {
+		_, err := New().EvalStr(`(meta [1 2])`)
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestInterpreter_EvalStr_PerForm(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title string
+		src   string
+		want  core.Any
+	}{
+		{
+			// the macro must be defined before the form using it is analyzed.
+			title: "MacroDefinedEarlier",
+			src:   `(def m (macro [] '(quote expanded))) (m)`,
+			want:  builtin.Symbol("expanded"),
+		},
+		{
+			// the symbol must be qualified with the namespace switched to.
+			title: "NamespaceSwitchedEarlier",
+			src:   "(ns foo) `x",
+			want:  builtin.Symbol("foo/x"),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := New().EvalStr(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInterpreter_ErrorMatchers(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title string
+		src   string
+		want  core.Any
+	}{
+		{title: "Arity", src: `(try ((fn [a] a)) (catch ErrArity e :arity))`, want: builtin.Keyword("arity")},
+		{title: "NotFound", src: `(try undefined (catch ErrNotFound e :not-found))`, want: builtin.Keyword("not-found")},
+		{title: "NotInvokable", src: `(try (1 2) (catch ErrNotInvokable e :not-invokable))`, want: builtin.Keyword("not-invokable")},
+		{title: "Thrown", src: `(try (throw :oops) (catch ErrArity e 1) (catch ErrThrown e 2))`, want: builtin.Int64(2)},
+		{title: "NotDynamic", src: `(def x 1) (try (binding [x 2] x) (catch ErrNotDynamic e :not-dynamic))`, want: builtin.Keyword("not-dynamic")},
+		{title: "NotBound", src: `(try ErrArity (catch ErrNotFound e :not-found))`, want: builtin.Keyword("not-found")},
+		{title: "HostBinding", src: `(def ErrArity 1) [ErrArity (try ((fn [a] a)) (catch ErrArity e :arity))]`, want: builtin.NewVector(builtin.Int64(1), builtin.Keyword("arity"))},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := New().EvalStr(tt.src)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInterpreter_EvalContext(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	table := []struct {
+		title   string
+		src     string
+		ctx     func() (context.Context, context.CancelFunc)
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "InfiniteLoop",
+			src:   `(loop [i 0] (recur (inc i)))`,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			title: "InfiniteRecur",
+			src:   `((fn [] (recur)))`,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			title: "InfiniteTailCall",
+			src:   `(def f (fn [x] (f x))) (f 1)`,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			title: "DeadlineNotCaught",
+			src:   `(try (loop [i 0] (recur (inc i))) (catch :default e :caught))`,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			title: "Cancelled",
+			src:   `(inc 1)`,
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			wantErr: context.Canceled,
+		},
+		{
+			title: "ContextPassedToFunc",
+			src:   `(def get-value (fn [] (ctx-value))) (get-value)`,
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "foo"))
+			},
+			want: "foo",
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New()
+			require.NoError(t, ins.Bind(testGlobals))
+			require.NoError(t, ins.Bind(map[string]core.Any{
+				"ctx-value": Func("ctx-value", func(ctx context.Context) interface{} {
+					return ctx.Value(ctxKey{})
+				}),
+			}))
+
+			forms, err := reader.New(strings.NewReader(tt.src)).All()
+			require.NoError(t, err)
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			var got core.Any
+			for _, form := range forms {
+				if got, err = ins.EvalContext(ctx, form); err != nil {
+					break
+				}
+			}
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.True(t, errors.As(err, &core.Error{}), "error must be a core.Error")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpreter_Budget(t *testing.T) {
+	t.Parallel()
+
+	budget := core.Budget{MaxSteps: 100, MaxDepth: 10, MaxLength: 3}
+
+	table := []struct {
+		title     string
+		src       string
+		want      core.Any
+		wantUsage core.Usage
+		wantErr   error
+	}{
+		{
+			title:     "WithinBudget",
+			src:       `(def f (fn [x] (inc x))) [(f 1) (f 2)]`,
+			want:      builtin.NewVector(builtin.Int64(2), builtin.Int64(3)),
+			wantUsage: core.Usage{Steps: 4, Depth: 2, Length: 2},
+		},
+		{
+			title:     "TailCallsDoNotAddDepth",
+			src:       `(def f (fn [n] (if (< n 20) (f (inc n)) n))) (f 0)`,
+			want:      builtin.Int64(20),
+			wantUsage: core.Usage{Steps: 62, Depth: 2, Length: 0},
+		},
+		{
+			title:   "StepsExceeded",
+			src:     `(loop [i 0] (recur (inc i)))`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "StepsExceededNotCaught",
+			src:     `(try (loop [i 0] (recur (inc i))) (catch ErrBudgetExceeded e :caught) (catch :default e :caught))`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "DepthExceeded",
+			src:     `(def f (fn [n] (inc (f n)))) (f 0)`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "VectorLengthExceeded",
+			src:     `[1 2 3 4]`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "ListLengthExceeded",
+			src:     `(cons 0 (list 1 2 3))`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(WithBudget(budget))
+			require.NoError(t, ins.Bind(testGlobals))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.True(t, errors.As(err, &core.Error{}), "error must be a core.Error")
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, tt.wantUsage, ins.Usage())
+			}
+		})
+	}
+	t.Run("ConcurrentUsage", func(t *testing.T) {
+		const n = 8
+
+		ins := New(WithBudget(budget))
+		require.NoError(t, ins.Bind(testGlobals))
+
+		forms, err := reader.New(strings.NewReader(`(inc 1)`)).All()
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		usages := make([]core.Usage, n)
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+
+				meter := core.NewMeter(budget)
+				_, err := ins.EvalContext(core.WithMeter(context.Background(), meter), forms[0])
+				assert.NoError(t, err)
+				usages[i] = meter.Usage()
+
+				_, err = ins.EvalContext(context.Background(), forms[0])
+				assert.NoError(t, err)
+				_ = ins.Usage()
+			}(i)
+		}
+		wg.Wait()
+
+		for _, usage := range usages {
+			assert.Equal(t, core.Usage{Steps: 1, Depth: 1}, usage)
+		}
+		assert.Equal(t, core.Usage{Steps: 1, Depth: 1}, ins.Usage())
+	})
+}
+
+func TestInterpreter_Profile(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		profile builtin.Profile
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title:   "PureExpression",
+			profile: ProfilePure.WithBindings("inc"),
+			src:     `(let [x 1] (if x (inc x) 0))`,
+			want:    builtin.Int64(2),
+		},
+		{
+			title:   "PureRejectsFn",
+			profile: ProfilePure.WithBindings("inc"),
+			src:     `((fn [x] (inc x)) 1)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "PureRejectsBinding",
+			profile: ProfilePure.WithBindings("inc"),
+			src:     `(dec 1)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RulesAllowsFnAndLoop",
+			profile: ProfileRules.WithBindings("inc", "<"),
+			src:     `((fn [n] (loop [i 0] (if (< i n) (recur (inc i)) i))) 3)`,
+			want:    builtin.Int64(3),
+		},
+		{
+			title:   "RulesAllowsErrorMatchers",
+			profile: ProfileRules,
+			src:     `(try ((fn [a] a)) (catch ErrArity e :arity))`,
+			want:    builtin.Keyword("arity"),
+		},
+		{
+			title:   "RulesRejectsRebindingVar",
+			profile: ProfileRules.WithBindings("whoami"),
+			src:     `(binding [*user* "admin"] (whoami))`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RulesAllowsRebindingAllowedVar",
+			profile: ProfileRules.WithBindings("whoami", "*user*"),
+			src:     `(binding [*user* "admin"] (whoami))`,
+			want:    builtin.String("admin"),
+		},
+		{
+			title:   "RulesRejectsDef",
+			profile: ProfileRules,
+			src:     `(def x 1)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RulesRejectsGo",
+			profile: ProfileRules,
+			src:     `(go 1)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RulesRejectsNestedBinding",
+			profile: ProfileRules.WithBindings("inc"),
+			src:     `((fn [x] [(inc x) (dec x)]) 1)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "Full",
+			profile: ProfileFull,
+			src:     `(def x (dec 2)) (go x) x`,
+			want:    builtin.Int64(1),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(WithProfile(tt.profile))
+			require.NoError(t, ins.Bind(testGlobals))
+
+			user := builtin.NewVar("*user*", builtin.String("guest"))
+			require.NoError(t, ins.Bind(map[string]core.Any{
+				"*user*": user,
+				"whoami": Func("whoami", func(ctx context.Context) (core.Any, error) { return user.Deref(ctx) }),
+			}))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpreter_Profile_NamespaceVars(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		profile builtin.Profile
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title:   "RejectsVar",
+			profile: ProfileRules,
+			src:     `secret`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RejectsQualifiedVar",
+			profile: ProfileRules,
+			src:     `host/token`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RejectsReferredVar",
+			profile: ProfileRules,
+			src:     `(token)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "RejectsQualifiedReferredVar",
+			profile: ProfileRules,
+			src:     `user/token`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title:   "AllowsVarByQualifiedName",
+			profile: ProfileRules.WithBindings("user/secret", "host/token"),
+			src:     `[secret user/secret (token) (host/token)]`,
+			want: builtin.NewVector(
+				builtin.String("s3cr3t"), builtin.String("s3cr3t"),
+				builtin.String("t0k3n"), builtin.String("t0k3n"),
+			),
+		},
+		{
+			title:   "AllowsVarByName",
+			profile: ProfileRules.WithBindings("secret"),
+			src:     `secret`,
+			want:    builtin.String("s3cr3t"),
+		},
+		{
+			title:   "AllowsLocal",
+			profile: ProfileRules,
+			src:     `(let [secret 1] ((fn [token] [secret token]) 2))`,
+			want:    builtin.NewVector(builtin.Int64(1), builtin.Int64(2)),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			// namespace vars defined by the host.
+			env := core.New(nil)
+			host, err := builtin.InNS(env, "host")
+			require.NoError(t, err)
+			require.NoError(t, host.Bind("token", Func("token", func() builtin.String { return "t0k3n" })))
+
+			user, err := builtin.InNS(env, "user")
+			require.NoError(t, err)
+			require.NoError(t, user.Bind("secret", builtin.String("s3cr3t")))
+			user.Refer(host, "token")
+
+			got, err := New(WithEnv(env), WithProfile(tt.profile)).EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpreter_Future(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "DerefGo",
+			src:   `(deref (go (inc 1)))`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title: "DerefReaderMacro",
+			src:   `(def f (go (+ 1 2))) @f`,
+			want:  builtin.Int64(3),
+		},
+		{
+			title:   "GoErrorOnDeref",
+			src:     `@(go (undefined-fn 1))`,
+			wantErr: core.ErrNotFound,
+		},
+		{
+			title: "PromiseDeliver",
+			src:   `(let [p (promise)] (go (deliver p :done)) @p)`,
+			want:  builtin.Keyword("done"),
+		},
+		{
+			title: "DeliverOnce",
+			src:   `(let [p (promise)] [(deliver p 1) (deliver p 2)] @p)`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "DerefTimeout",
+			src:   `(deref (promise) 10 :timeout)`,
+			want:  builtin.Keyword("timeout"),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(WithCoreLib())
+			require.NoError(t, ins.Bind(testGlobals))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+
+	t.Run("DerefCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		forms, err := reader.New(strings.NewReader(`@(promise)`)).All()
+		require.NoError(t, err)
+
+		_, err = New(WithCoreLib()).EvalContext(ctx, forms[0])
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("NotBoundWithoutCoreLib", func(t *testing.T) {
+		_, err := New().EvalStr(`(promise)`)
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestInterpreter_Chan(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "Unbuffered",
+			src:   `(let [c (chan)] (go (>! c (inc 1))) (<! c))`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title: "BufferedAndClosed",
+			src:   `(let [c (chan 1)] [(>! c :a) (close! c) (<! c) (<! c) (>! c :b)])`,
+			want: builtin.NewVector(builtin.Bool(true), builtin.Nil{}, builtin.Keyword("a"),
+				builtin.Nil{}, builtin.Bool(false)),
+		},
+		{
+			title: "AltsDefault",
+			src:   `(alts! [(chan)] :default :none)`,
+			want:  builtin.NewVector(builtin.Keyword("none"), builtin.Keyword("default")),
+		},
+		{
+			title: "AltsPut",
+			src:   `(def c (chan 1)) (alts! [[c 1]]) (<! c)`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "GoChannels",
+			src:   `(>! out (+ (<! in) 1)) (<! out)`,
+			want:  builtin.Int64(42),
+		},
+		{
+			title:   "CloseTwice",
+			src:     `(let [c (chan)] (close! c) (close! c))`,
+			wantErr: builtin.ErrClosed,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			in, out := make(chan int, 1), make(chan int, 1)
+			in <- 41
+
+			ins := New(WithCoreLib())
+			require.NoError(t, ins.Bind(testGlobals))
+			require.NoError(t, ins.Bind(map[string]core.Any{"in": in, "out": out}))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+	t.Run("NotBoundWithoutCoreLib", func(t *testing.T) {
+		_, err := New().EvalStr(`(chan)`)
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestInterpreter_Atom(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "SwapAndReset",
+			src:   `(def a (atom 1)) [(swap! a + 2) (reset! a 10) @a]`,
+			want:  builtin.NewVector(builtin.Int64(3), builtin.Int64(10), builtin.Int64(10)),
+		},
+		{
+			title: "CompareAndSet",
+			src:   `(def a (atom 1)) [(compare-and-set! a 2 3) (compare-and-set! a 1 3) @a]`,
+			want:  builtin.NewVector(builtin.Bool(false), builtin.Bool(true), builtin.Int64(3)),
+		},
+		{
+			title: "ConcurrentSwap",
+			src: `(def a (atom 0))
+				  (def done (chan 10))
+				  (loop [i 0] (if (< i 10) (do (go (do (swap! a inc) (>! done true))) (recur (inc i)))))
+				  (loop [i 0] (if (< i 10) (do (<! done) (recur (inc i)))))
+				  @a`,
+			want: builtin.Int64(10),
+		},
+		{
+			title:   "ValidatorRejects",
+			src:     `(def a (atom 1 :validator (fn [x] (< 0 x)))) (reset! a 0)`,
+			wantErr: builtin.ErrInvalidState,
+		},
+		{
+			title: "FnWatch",
+			src: `(def log (atom []))
+				  (def a (atom 1))
+				  (add-watch a :log (fn [k r old new] (reset! log [k old new])))
+				  (swap! a inc)
+				  @log`,
+			want: builtin.NewVector(builtin.Keyword("log"), builtin.Int64(1), builtin.Int64(2)),
+		},
+		{
+			title: "GoFuncWatch",
+			src:   `(def a (atom 1)) (add-watch a :go go-watch) (reset! a 5) @changes`,
+			want:  builtin.Int64(5),
+		},
+		{
+			title: "RawGoFuncWatchAndValidator",
+			src:   `(def a (atom 1)) (set-validator! a positive?) (add-watch a :go raw-watch) (reset! a 7) @changes`,
+			want:  builtin.Int64(7),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			changes := builtin.NewAtom(builtin.Nil{})
+
+			ins := New(WithCoreLib())
+			require.NoError(t, ins.Bind(testGlobals))
+			require.NoError(t, ins.Bind(map[string]core.Any{
+				"changes": changes,
+				"go-watch": Func("go-watch", func(ctx context.Context, key, ref, old, v core.Any) error {
+					_, err := changes.Reset(ctx, v)
+					return err
+				}),
+				"raw-watch": func(ctx context.Context, key, ref, old, v core.Any) error {
+					_, err := changes.Reset(ctx, v)
+					return err
+				},
+				"positive?": func(v int64) bool { return v > 0 },
+			}))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+	t.Run("NotBoundWithoutCoreLib", func(t *testing.T) {
+		_, err := New().EvalStr(`(atom 1)`)
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestInterpreter_Binding(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "RootValue",
+			src:   `(def ^:dynamic *x* 1) *x*`,
+			want:  builtin.Int64(1),
+		},
+		{
+			title: "DynamicExtent",
+			src: `(def ^:dynamic *x* 1)
+				  (def get-x (fn [] *x*))
+				  [(binding [*x* 2] (get-x)) (get-x)]`,
+			want: builtin.NewVector(builtin.Int64(2), builtin.Int64(1)),
+		},
+		{
+			title: "Nested",
+			src: `(def ^:dynamic *x* 1)
+				  (def ^:dynamic *y* 1)
+				  (binding [*x* 2 *y* 2] (binding [*x* 3] [*x* *y*]))`,
+			want: builtin.NewVector(builtin.Int64(3), builtin.Int64(2)),
+		},
+		{
+			title: "ConveyedToGo",
+			src: `(def ^:dynamic *x* 1)
+				  (def f (binding [*x* 2] (go *x*)))
+				  @f`,
+			want: builtin.Int64(2),
+		},
+		{
+			title: "QualifiedSymbol",
+			src:   `(def ^:dynamic *x* 1) (binding [user/*x* 2] user/*x*)`,
+			want:  builtin.Int64(2),
+		},
+		{
+			title:   "NotDynamic",
+			src:     `(def x 1) (binding [x 2] x)`,
+			wantErr: builtin.ErrNotDynamic,
+		},
+		{
+			title:   "RecurInBinding",
+			src:     `(def ^:dynamic *x* 1) (loop [] (binding [*x* 2] (recur)))`,
+			wantErr: ErrParseSpecial,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(WithCoreLib())
+			require.NoError(t, ins.Bind(testGlobals))
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpreter_WithBindings(t *testing.T) {
+	t.Parallel()
+
+	ins := New()
+	require.NoError(t, ins.Bind(map[string]core.Any{
+		"*user*": builtin.NewVar("*user*", builtin.Nil{}),
+		"greet":  builtin.String("hello"),
+	}))
+
+	_, err := ins.EvalStr(`(def ^:dynamic *request-id* nil) (def current-user (fn [] *user*))`)
+	require.NoError(t, err)
+
+	ctx, err := ins.WithBindings(context.Background(), map[string]core.Any{
+		"*user*":       builtin.String("bob"),
+		"*request-id*": builtin.Int64(42),
+	})
+	require.NoError(t, err)
+
+	form := builtin.NewVector(
+		builtin.NewList(builtin.Symbol("current-user")),
+		builtin.Symbol("*request-id*"),
+	)
+
+	got, err := ins.EvalContext(ctx, form)
+	require.NoError(t, err)
+	assert.Equal(t, builtin.NewVector(builtin.String("bob"), builtin.Int64(42)), got)
+
+	got, err = ins.Eval(form)
+	require.NoError(t, err)
+	assert.Equal(t, builtin.NewVector(builtin.Nil{}, builtin.Nil{}), got, "bindings must be limited to the eval")
+
+	_, err = ins.WithBindings(context.Background(), map[string]core.Any{"greet": builtin.Nil{}})
+	assert.ErrorIs(t, err, builtin.ErrNotDynamic)
+}
+
+func TestInterpreter_CurrentNS(t *testing.T) {
+	t.Parallel()
+
+	ins := New()
+	assert.Equal(t, "user", ins.CurrentNS())
+
+	got, err := ins.EvalStr(`(ns foo)`)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", ins.CurrentNS())
+	assert.Equal(t, builtin.CurrentNS(ins.env), got)
+}
+
+func TestInterpreter_CoreLib(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		opts    []Option
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "Arithmetic",
+			src:   `(+ 1 (* 2 3) (/ 1 2) 0.5M)`,
+			want:  mustDecimal(t, "8.0"),
+		},
+		{
+			title: "Sequences",
+			src:   `(reduce + (map inc (filter (fn [x] (= 0 (mod x 2))) (range 10))))`,
+			want:  builtin.Int64(25),
+		},
+		{
+			title: "ApplyStr",
+			src:   `(apply str "n=" (take 2 (drop 1 (concat [1 2] '(3)))))`,
+			want:  builtin.String("n=23"),
+		},
+		{
+			title: "Collections",
+			src:   `[(count (conj [1] 2)) (get {:a 1} :a) (nth '(1 2) 1) (first (rest (cons 0 [1])))]`,
+			want:  builtin.NewVector(builtin.Int64(2), builtin.Int64(1), builtin.Int64(2), builtin.Int64(1)),
+		},
+		{
+			title: "LazySeqs",
+			src:   `[(take 3 (map inc (range))) (take 3 (filter (fn [x] (= 0 (mod x 3))) (iterate inc 1))) (take 3 (cycle [1 2])) (repeat 2 :a)]`,
+			want: builtin.NewVector(
+				builtin.NewList(builtin.Int64(1), builtin.Int64(2), builtin.Int64(3)),
+				builtin.NewList(builtin.Int64(3), builtin.Int64(6), builtin.Int64(9)),
+				builtin.NewList(builtin.Int64(1), builtin.Int64(2), builtin.Int64(1)),
+				builtin.NewList(builtin.Keyword("a"), builtin.Keyword("a")),
+			),
+		},
+		{
+			title: "Transducers",
+			src:   `(into [] (comp (map inc) (filter (fn [x] (= 1 (mod x 2)))) (take 3)) (range))`,
+			want:  builtin.NewVector(builtin.Int64(1), builtin.Int64(3), builtin.Int64(5)),
+		},
+		{
+			title:   "BudgetTransduce",
+			opts:    []Option{WithBudget(core.Budget{MaxSteps: 1000})},
+			src:     `(transduce (map inc) + (range))`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "Budget",
+			opts:    []Option{WithBudget(core.Budget{MaxLength: 100})},
+			src:     `(take 1000 (range))`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "BudgetInfiniteSeq",
+			opts:    []Option{WithBudget(core.Budget{MaxSteps: 1000})},
+			src:     `(reduce + (range))`,
+			wantErr: core.ErrBudgetExceeded,
+		},
+		{
+			title:   "Profile",
+			opts:    []Option{WithProfile(ProfilePure)},
+			src:     `(+ 1 2)`,
+			wantErr: builtin.ErrNotAllowed,
+		},
+		{
+			title: "KeepsHostBindings",
+			opts: []Option{WithEnv(core.New(map[string]core.Any{
+				"inc":  builtin.Keyword("host"),
+				"atom": builtin.Keyword("host"),
+			}))},
+			src:  `[inc atom (dec 1)]`,
+			want: builtin.NewVector(builtin.Keyword("host"), builtin.Keyword("host"), builtin.Int64(0)),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(append([]Option{WithCoreLib()}, tt.opts...)...)
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				eq, err := core.Eq(tt.want, got)
+				require.NoError(t, err)
+				assert.True(t, eq, "want=%#v\ngot=%#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestInterpreter_LazySeq(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+	}{
+		{
+			title: "Recursive",
+			src:   `(def nat (fn [n] (lazy-seq (cons n (nat (inc n)))))) (take 3 (nat 0))`,
+			want:  builtin.NewList(builtin.Int64(0), builtin.Int64(1), builtin.Int64(2)),
+		},
+		{
+			title: "Empty",
+			src:   `[(count (lazy-seq)) (first (lazy-seq nil))]`,
+			want:  builtin.NewVector(builtin.Int64(0), builtin.Nil{}),
+		},
+		{
+			title: "RealizedOnce",
+			src:   `(def n (atom 0)) (def s (lazy-seq (swap! n inc) [1 2])) [@n (first s) (count s) @n]`,
+			want:  builtin.NewVector(builtin.Int64(0), builtin.Int64(1), builtin.Int64(2), builtin.Int64(1)),
+		},
+		{
+			title: "Destructure",
+			src:   `(let [[a b & more] (range)] [a b (first more)])`,
+			want:  builtin.NewVector(builtin.Int64(0), builtin.Int64(1), builtin.Int64(2)),
+		},
+		{
+			title:   "RecurNotAllowed",
+			src:     `(fn [x] (lazy-seq (recur x)))`,
+			wantErr: ErrParseSpecial,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			ins := New(WithCoreLib())
+
+			got, err := ins.EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				eq, err := core.Eq(tt.want, got)
+				require.NoError(t, err)
+				assert.True(t, eq, "want=%#v\ngot=%#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func mustDecimal(t *testing.T, s string) builtin.BigDecimal {
+	d, err := builtin.ParseBigDecimal(s)
+	require.NoError(t, err)
+	return d
+}
+
+func mustMap(t *testing.T, kvs ...core.Any) builtin.PersistentMap {
+	m, err := builtin.NewMap(kvs...)
+	require.NoError(t, err)
+	return m
+}
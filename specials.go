@@ -14,6 +14,19 @@ import (
 // fails due to malformed syntax.
 var ErrParseSpecial = errors.New("invalid special form")
 
+// recurTargetKey is bound in the analysis env of loop and fn bodies to
+// the recurTarget for validating the recur forms within.
+const recurTargetKey = "<recur>"
+
+// restSymbol separates the fixed parameters from the rest parameter in
+// a parameter list. e.g., (fn [a b & more] ...)
+const restSymbol = builtin.Symbol("&")
+
+const (
+	symCatch   = builtin.Symbol("catch")
+	symFinally = builtin.Symbol("finally")
+)
+
 // parseDo parses the (do <expr>*) form and returns a DoExpr.
 func parseDo(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 	var de builtin.DoExpr
@@ -106,7 +119,7 @@ func parseDef(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 		return nil, err
 	}
 
-	sym, ok := first.(builtin.Symbol)
+	sym, ok := builtin.SymbolOf(first)
 	if !ok {
 		return nil, e.With(fmt.Sprintf(
 			"first arg must be symbol, not '%s'", reflect.TypeOf(first)))
@@ -128,11 +141,83 @@ func parseDef(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 	}
 
 	return builtin.DefExpr{
-		Name:  string(sym),
-		Value: res,
+		Name:    string(sym),
+		Value:   res,
+		Dynamic: isDynamic(first),
 	}, nil
 }
 
+// isDynamic returns true if the form has :dynamic set in its metadata
+// (i.e., ^:dynamic name).
+func isDynamic(form core.Any) bool {
+	meta, ok := builtin.MetaOf(form).(core.Map)
+	if !ok {
+		return false
+	}
+
+	v, err := meta.EntryAt(builtin.Keyword("dynamic"))
+	return err == nil && builtin.IsTruthy(v)
+}
+
+// parseBinding parses the (binding [name value*] body*) form. Each name must
+// be a symbol referring to a dynamic var when evaluated.
+func parseBinding(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: binding", ErrParseSpecial)}
+
+	if args == nil {
+		return nil, e.With("requires binding vector, got nothing")
+	}
+
+	items, err := core.ToSlice(args)
+	if err != nil {
+		return nil, err
+	} else if len(items) == 0 {
+		return nil, e.With("requires binding vector, got nothing")
+	}
+
+	bindings, err := paramSeq(items[0])
+	if err != nil {
+		return nil, e.With(fmt.Sprintf(
+			"expecting binding vector, got '%s'", reflect.TypeOf(items[0])))
+	}
+
+	forms, err := core.ToSlice(bindings)
+	if err != nil {
+		return nil, err
+	} else if len(forms)%2 != 0 {
+		return nil, e.With(fmt.Sprintf(
+			"requires even number of binding forms, got %d", len(forms)))
+	}
+
+	var be builtin.BindingExpr
+	for i := 0; i < len(forms); i += 2 {
+		sym, ok := builtin.SymbolOf(forms[i])
+		if !ok {
+			return nil, e.With(fmt.Sprintf(
+				"binding name must be symbol, not '%s'", reflect.TypeOf(forms[i])))
+		}
+
+		// analyze the name as a reference to the var so that the profile
+		// (if any) of the analyzer restricts rebinding the var as well.
+		if _, err := a.Analyze(env, sym); err != nil {
+			return nil, err
+		}
+
+		val, err := a.Analyze(env, forms[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		be.Names = append(be.Names, sym)
+		be.Values = append(be.Values, val)
+	}
+
+	if be.Body, err = analyzeBody(a, env, items[1:]); err != nil {
+		return nil, err
+	}
+	return be, nil
+}
+
 func parseLet(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 
 	e := core.Error{Cause: fmt.Errorf("%w: def", ErrParseSpecial)}
@@ -150,6 +235,7 @@ func parseLet(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 	}
 
 	var let builtin.LetExpr
+	letEnv := env.Child("<let>", nil)
 
 	// analyze bindings
 	bs, err := args.First()
@@ -159,7 +245,7 @@ func parseLet(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 
 	switch s := bs.(type) {
 	case core.Seq:
-		if err = parseLetBindings(a, env, s, &let); err != nil {
+		if err = parseLetBindings(a, letEnv, s, &let); err != nil {
 			return nil, err
 		}
 
@@ -168,7 +254,7 @@ func parseLet(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 		if err != nil {
 			return nil, err
 		}
-		if err = parseLetBindings(a, env, seq, &let); err != nil {
+		if err = parseLetBindings(a, letEnv, seq, &let); err != nil {
 			return nil, err
 		}
 
@@ -185,7 +271,7 @@ func parseLet(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 	}
 
 	err = core.ForEach(args, func(item core.Any) (bool, error) {
-		expr, err := a.Analyze(env, item)
+		expr, err := a.Analyze(letEnv, item)
 		if err == nil {
 			let.Exprs = append(let.Exprs, expr)
 		}
@@ -195,30 +281,475 @@ func parseLet(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 	return let, err
 }
 
+// parseLetBindings analyzes the binding-form/value pairs and appends the
+// resulting bindings to the let. Binding forms are destructured, and each
+// value is analyzed with the previous bindings in scope.
 func parseLetBindings(a core.Analyzer, env core.Env, seq core.Seq, le *builtin.LetExpr) error {
-	return core.ForEach(seq, func(item core.Any) (bool, error) {
-		// symbol?
-		if len(le.Names)%2 == 0 {
-			s, ok := item.(builtin.Symbol)
-			if !ok {
-				return false, core.Error{
-					Cause:   fmt.Errorf("%w: let", ErrParseSpecial),
-					Message: fmt.Sprintf("expected symbol, got %s", reflect.TypeOf(item)),
-				}
+	items, err := core.ToSlice(seq)
+	if err != nil {
+		return err
+	} else if len(items)%2 != 0 {
+		return core.Error{
+			Cause:   fmt.Errorf("%w: let", ErrParseSpecial),
+			Message: fmt.Sprintf("requires even number of binding forms, got %d", len(items)),
+		}
+	}
+
+	for i := 0; i < len(items); i += 2 {
+		expr, err := a.Analyze(env, items[i+1])
+		if err != nil {
+			return err
+		}
+
+		if err := destructure(a, env, items[i], expr, le); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseLoop parses the (loop [binding*] <expr>*) form and returns a LoopExpr.
+// Binding forms are destructured same as let.
+func parseLoop(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: loop", ErrParseSpecial)}
+
+	if args == nil {
+		return nil, e.With("requires binding vector, got nothing")
+	}
+
+	items, err := core.ToSlice(args)
+	if err != nil {
+		return nil, err
+	} else if len(items) == 0 {
+		return nil, e.With("requires binding vector, got nothing")
+	}
+
+	bindings, err := paramSeq(items[0])
+	if err != nil {
+		return nil, e.With(fmt.Sprintf(
+			"expecting binding vector, got '%s'", reflect.TypeOf(items[0])))
+	}
+
+	forms, err := core.ToSlice(bindings)
+	if err != nil {
+		return nil, err
+	} else if len(forms)%2 != 0 {
+		return nil, e.With(fmt.Sprintf(
+			"requires even number of binding forms, got %d", len(forms)))
+	}
+
+	var loop builtin.LoopExpr
+	var let builtin.LetExpr
+	loopEnv := env.Child("<loop>", nil)
+
+	// once a binding form is destructured, the loop binds the values to
+	// generated names and the let binds the names in the binding forms in
+	// order. each value is evaluated within the let of the bindings before
+	// it so that the bindings behave same as let.
+	for i := 0; i < len(forms); i += 2 {
+		val, err := a.Analyze(loopEnv, forms[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		if len(let.Names) > 0 {
+			val = builtin.LetExpr{
+				Names:  append([]string(nil), let.Names...),
+				Values: append([]core.Expr(nil), let.Values...),
+				Exprs:  builtin.DoExpr{val},
 			}
+		}
 
-			le.Names = append(le.Names, string(s))
-			return false, nil
+		sym, isSym := builtin.SymbolOf(forms[i])
+		destructured := !isSym || len(let.Names) > 0
+		if sym == restSymbol {
+			return nil, e.With("unexpected '&'")
+		} else if destructured {
+			sym = builtin.Symbol(gensym("loop"))
 		}
 
-		// it's a value
+		loop.Names = append(loop.Names, string(sym))
+		loop.Values = append(loop.Values, val)
+		if err := loopEnv.Bind(string(sym), nil); err != nil {
+			return nil, err
+		}
+
+		if destructured {
+			val := builtin.ResolveExpr{Symbol: sym}
+			if err := destructure(a, loopEnv, forms[i], val, &let); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := loopEnv.Bind(recurTargetKey, recurTarget{arity: len(loop.Names)}); err != nil {
+		return nil, err
+	}
+
+	if loop.Body, err = analyzeBody(a, loopEnv, items[1:]); err != nil {
+		return nil, err
+	} else if err := checkRecur(loop.Body, true); err != nil {
+		return nil, err
+	}
+
+	if len(let.Names) > 0 {
+		let.Exprs = builtin.DoExpr{loop.Body}
+		loop.Body = let
+	}
+
+	return loop, nil
+}
+
+// parseRecur parses the (recur <expr>*) form and returns a RecurExpr. The
+// recur must be within a loop or fn and have as many args as the bindings
+// (or params) of it.
+func parseRecur(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: recur", ErrParseSpecial)}
+
+	target, found := resolveRecurTarget(env)
+	if !found {
+		return nil, e.With("recur is allowed only within loop or fn")
+	}
+
+	var re builtin.RecurExpr
+	err := core.ForEach(args, func(item core.Any) (bool, error) {
 		expr, err := a.Analyze(env, item)
 		if err == nil {
-			le.Values = append(le.Values, expr)
+			re.Args = append(re.Args, expr)
 		}
-
 		return false, err
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(re.Args) != target.arity {
+		return nil, e.With(fmt.Sprintf(
+			"requires %d arguments, got %d", target.arity, len(re.Args)))
+	}
+
+	return re, nil
+}
+
+// parseTry parses the (try <expr>* (catch <matcher> <name> <expr>*)*
+// (finally <expr>*)?) form and returns a TryExpr.
+func parseTry(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: try", ErrParseSpecial)}
+
+	var forms []core.Any
+	if args != nil {
+		var err error
+		if forms, err = core.ToSlice(args); err != nil {
+			return nil, err
+		}
+	}
+
+	var te builtin.TryExpr
+	var body []core.Any
+	for i, form := range forms {
+		switch {
+		case isForm(form, symCatch):
+			if te.Finally != nil {
+				return nil, e.With("catch clause after finally")
+			}
+
+			ce, err := parseCatch(a, env, form.(core.Seq))
+			if err != nil {
+				return nil, err
+			}
+			te.Catches = append(te.Catches, *ce)
+
+		case isForm(form, symFinally):
+			if i != len(forms)-1 {
+				return nil, e.With("finally clause must be the last form")
+			}
+
+			clause, err := core.ToSlice(form.(core.Seq))
+			if err != nil {
+				return nil, err
+			}
+
+			if te.Finally, err = analyzeBody(a, env, clause[1:]); err != nil {
+				return nil, err
+			}
+
+		default:
+			if len(te.Catches) > 0 {
+				return nil, e.With("body forms after catch clause")
+			}
+			body = append(body, form)
+		}
+	}
+
+	var err error
+	if te.Body, err = analyzeBody(a, env, body); err != nil {
+		return nil, err
+	}
+
+	return te, nil
+}
+
+// errorMatchers are the error sentinels that can be named in catch clauses
+// to catch errors by their cause. For example, (catch ErrArity e ...). The
+// names are not bound in the env and are resolved only in catch clauses.
+var errorMatchers = map[string]core.Any{
+	"ErrArity":            core.ErrArity,
+	"ErrNotFound":         core.ErrNotFound,
+	"ErrNotInvokable":     core.ErrNotInvokable,
+	"ErrIncomparable":     core.ErrIncomparable,
+	"ErrInvalidName":      core.ErrInvalidName,
+	"ErrThrown":           core.ErrThrown,
+	"ErrBudgetExceeded":   core.ErrBudgetExceeded,
+	"ErrNotNumber":        builtin.ErrNotNumber,
+	"ErrArithmetic":       builtin.ErrArithmetic,
+	"ErrIndexOutOfBounds": builtin.ErrIndexOutOfBounds,
+	"ErrNoNamespace":      builtin.ErrNoNamespace,
+	"ErrNotDynamic":       builtin.ErrNotDynamic,
+	"ErrInvalidState":     builtin.ErrInvalidState,
+	"ErrClosed":           builtin.ErrClosed,
+	"ErrChanDir":          builtin.ErrChanDir,
+	"ErrNotAllowed":       builtin.ErrNotAllowed,
+	"ErrParseSpecial":     ErrParseSpecial,
+	"ErrNoFS":             ErrNoFS,
+	"ErrCircularRequire":  ErrCircularRequire,
+}
+
+func parseCatch(a core.Analyzer, env core.Env, clause core.Seq) (*builtin.CatchExpr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: catch", ErrParseSpecial)}
+
+	items, err := core.ToSlice(clause)
+	if err != nil {
+		return nil, err
+	} else if len(items) < 3 {
+		return nil, e.With(fmt.Sprintf(
+			"requires matcher and binding name, got %d argument(s)", len(items)-1))
+	}
+
+	name, ok := builtin.SymbolOf(items[2])
+	if !ok {
+		return nil, e.With(fmt.Sprintf(
+			"binding name must be a symbol, not '%s'", reflect.TypeOf(items[2])))
+	}
+
+	var matcher core.Expr
+	if sym, ok := items[1].(builtin.Symbol); ok && errorMatchers[string(sym)] != nil {
+		matcher = builtin.ConstExpr{Const: errorMatchers[string(sym)]}
+	} else if matcher, err = a.Analyze(env, items[1]); err != nil {
+		return nil, err
+	}
+
+	catchEnv := env.Child("<catch>", nil)
+	if err := catchEnv.Bind(string(name), nil); err != nil {
+		return nil, err
+	}
+
+	body, err := analyzeBody(a, catchEnv, items[3:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &builtin.CatchExpr{
+		Matcher: matcher,
+		Name:    string(name),
+		Body:    body,
+	}, nil
+}
+
+// parseThrow parses the (throw <expr>) form and returns a ThrowExpr.
+func parseThrow(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: throw", ErrParseSpecial)}
+
+	if args == nil {
+		return nil, e.With("requires exactly 1 argument, got 0")
+	}
+
+	if count, err := args.Count(); err != nil {
+		return nil, err
+	} else if count != 1 {
+		return nil, e.With(fmt.Sprintf(
+			"requires exactly 1 argument, got %d", count))
+	}
+
+	first, err := args.First()
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := a.Analyze(env, first)
+	if err != nil {
+		return nil, err
+	}
+
+	return builtin.ThrowExpr{Value: val}, nil
+}
+
+// analyzeBody analyzes the forms as the body of (do <expr>*).
+func analyzeBody(a core.Analyzer, env core.Env, forms []core.Any) (core.Expr, error) {
+	do, err := builtin.Cons(builtin.Symbol("do"), builtin.NewList(forms...))
+	if err != nil {
+		return nil, err
+	}
+	return a.Analyze(env, do)
+}
+
+// parseNS parses the (ns name doc? clause*) form where each clause is one
+// of (:refer ns name*), (:alias alias ns) or (:require spec*). The namespace
+// is created if required and set as the current namespace before the
+// clauses are applied. The require clause is analyzed as (require spec*).
+func parseNS(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: ns", ErrParseSpecial)}
+
+	var items []core.Any
+	if args != nil {
+		var err error
+		if items, err = core.ToSlice(args); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, e.With("requires namespace name")
+	}
+
+	name, err := nsName(items[0])
+	if err != nil {
+		return nil, e.With(err.Error())
+	}
+
+	clauses := items[1:]
+	if len(clauses) > 0 {
+		if _, isDoc := clauses[0].(builtin.String); isDoc {
+			clauses = clauses[1:]
+		}
+	}
+
+	do := builtin.DoExpr{builtin.InNSExpr{Name: name}}
+	for _, clause := range clauses {
+		seq, ok := clause.(core.Seq)
+		if !ok {
+			return nil, e.With(fmt.Sprintf(
+				"expecting clause list, got '%s'", reflect.TypeOf(clause)))
+		}
+
+		kind, err := seq.First()
+		if err != nil {
+			return nil, err
+		}
+
+		rest, err := seq.Next()
+		if err != nil {
+			return nil, err
+		} else if rest == nil {
+			rest = builtin.NewList()
+		}
+
+		var expr core.Expr
+		switch kind {
+		case builtin.Keyword("refer"):
+			expr, err = parseRefer(a, env, rest)
+
+		case builtin.Keyword("alias"):
+			expr, err = parseAlias(a, env, rest)
+
+		case builtin.Keyword("require"):
+			var form core.Seq
+			if form, err = builtin.Cons(builtin.Symbol("require"), rest); err == nil {
+				expr, err = a.Analyze(env, form)
+			}
+
+		default:
+			return nil, e.With(fmt.Sprintf("unknown clause '%v'", kind))
+		}
+		if err != nil {
+			return nil, err
+		}
+		do = append(do, expr)
+	}
+
+	// ns form evaluates to the namespace.
+	return append(do, builtin.InNSExpr{Name: name}), nil
+}
+
+// parseInNS parses the (in-ns name) form and returns InNSExpr.
+func parseInNS(_ core.Analyzer, _ core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: in-ns", ErrParseSpecial)}
+
+	items, err := nsArgs(args, 1)
+	if err != nil {
+		return nil, e.With(err.Error())
+	}
+
+	return builtin.InNSExpr{Name: items[0]}, nil
+}
+
+// parseRefer parses the (refer ns name*) form and returns ReferExpr.
+func parseRefer(_ core.Analyzer, _ core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: refer", ErrParseSpecial)}
+
+	items, err := nsArgs(args, -1)
+	if err != nil {
+		return nil, e.With(err.Error())
+	} else if len(items) == 0 {
+		return nil, e.With("requires namespace name")
+	}
+
+	return builtin.ReferExpr{NS: items[0], Names: items[1:]}, nil
+}
+
+// parseAlias parses the (alias alias ns) form and returns AliasExpr.
+func parseAlias(_ core.Analyzer, _ core.Env, args core.Seq) (core.Expr, error) {
+	e := core.Error{Cause: fmt.Errorf("%w: alias", ErrParseSpecial)}
+
+	items, err := nsArgs(args, 2)
+	if err != nil {
+		return nil, e.With(err.Error())
+	}
+
+	return builtin.AliasExpr{Alias: items[0], NS: items[1]}, nil
+}
+
+// nsArgs returns the names in the args of a namespace form. If count is
+// not negative, exactly count args are required.
+func nsArgs(args core.Seq, count int) ([]string, error) {
+	var items []core.Any
+	if args != nil {
+		var err error
+		if items, err = core.ToSlice(args); err != nil {
+			return nil, err
+		}
+	}
+
+	if count >= 0 && len(items) != count {
+		return nil, fmt.Errorf("requires exactly %d argument(s), got %d", count, len(items))
+	}
+
+	names := make([]string, len(items))
+	for i, item := range items {
+		name, err := nsName(item)
+		if err != nil {
+			return nil, err
+		}
+		names[i] = name
+	}
+	return names, nil
+}
+
+// nsName returns the name from a symbol or a quoted symbol form.
+func nsName(form core.Any) (string, error) {
+	if isForm(form, builtin.Symbol("quote")) {
+		arg, err := quoteArg(form.(core.Seq), builtin.Symbol("quote"))
+		if err != nil {
+			return "", err
+		}
+		form = arg
+	}
+
+	sym, ok := form.(builtin.Symbol)
+	if !ok {
+		return "", fmt.Errorf("expecting symbol, got '%s'", reflect.TypeOf(form))
+	}
+	return string(sym), nil
 }
 
 func parseGo(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
@@ -247,18 +778,29 @@ func parseGo(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
 	return builtin.GoExpr{Form: e}, nil
 }
 
-// parseFn parses (fn name? doc? (<params>*) <body>*) special form and
-// returns an Fn definition.
+// parseLazySeq parses the (lazy-seq <body>*) form and returns LazySeqExpr.
+// The body is analyzed like a do form and evaluated when the sequence is
+// realized.
+func parseLazySeq(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	body, err := parseDo(a, env, args)
+	if err != nil {
+		return nil, err
+	}
+	return builtin.LazySeqExpr{Body: body}, nil
+}
+
+// parseFn parses (fn name? doc? (<params>*) <body>*) or the multi-arity form
+// (fn name? doc? ((<params>*) <body>*)+) and returns an Fn definition.
 func parseFn(a core.Analyzer, env core.Env, argSeq core.Seq) (core.Expr, error) {
 	fn, err := parseFnDef(a, env, argSeq)
 	if err != nil {
 		return nil, err
 	}
-	return builtin.ConstExpr{Const: *fn}, nil
+	return builtin.FnExpr{Fn: *fn}, nil
 }
 
 // parseMacro parses (macro name? doc? (<params>*) <body>*) special form and
-// returns an Fn definition.
+// returns an Fn definition. Multi-arity form is supported same as parseFn.
 func parseMacro(a core.Analyzer, env core.Env, argSeq core.Seq) (core.Expr, error) {
 	fn, err := parseFnDef(a, env, argSeq)
 	if err != nil {
@@ -288,40 +830,137 @@ func parseFnDef(a core.Analyzer, env core.Env, argSeq core.Seq) (*builtin.Fn, er
 	}
 
 	i := 0
-	if sym, ok := args[i].(builtin.Symbol); ok {
+	if sym, ok := builtin.SymbolOf(args[i]); ok {
 		fn.Name = strings.TrimSpace(sym.String())
 		i++
 	}
 
-	if str, ok := args[i].(builtin.String); ok {
-		fn.Doc = string(str)
-		i++
+	if i < len(args)-1 {
+		if str, ok := args[i].(builtin.String); ok {
+			fn.Doc = string(str)
+			i++
+		}
 	}
 
-	// TODO: add support for multi-arity parsing.
+	if i >= len(args) {
+		return nil, core.Error{
+			Cause:   fmt.Errorf("%w: fn", ErrParseSpecial),
+			Message: "expecting parameter list or arity definitions",
+		}
+	}
 
-	fnArgs, ok := args[i].(core.Seq)
-	if !ok {
-		return nil, fmt.Errorf(
-			"expecting a list of symbols, got '%s'", reflect.TypeOf(args[i]))
+	fn.Env = env.Child(fn.Name, nil)
+
+	multiArity, err := isMultiArity(args[i:])
+	if err != nil {
+		return nil, err
+	}
+
+	if !multiArity {
+		f, err := parseFunc(a, fn, args[i], args[i+1:])
+		if err != nil {
+			return nil, err
+		}
+		fn.Funcs = append(fn.Funcs, *f)
+		return &fn, nil
+	}
+
+	for _, arity := range args[i:] {
+		def, ok := arity.(core.Seq)
+		if !ok {
+			return nil, core.Error{
+				Cause: fmt.Errorf("%w: fn", ErrParseSpecial),
+				Message: fmt.Sprintf(
+					"expecting arity definition list, got '%s'", reflect.TypeOf(arity)),
+			}
+		}
+
+		items, err := core.ToSlice(def)
+		if err != nil {
+			return nil, err
+		} else if len(items) == 0 {
+			return nil, core.Error{
+				Cause:   fmt.Errorf("%w: fn", ErrParseSpecial),
+				Message: "arity definition must begin with a parameter list",
+			}
+		}
+
+		f, err := parseFunc(a, fn, items[0], items[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := checkArity(fn.Funcs, *f); err != nil {
+			return nil, err
+		}
+		fn.Funcs = append(fn.Funcs, *f)
+	}
+
+	return &fn, nil
+}
+
+// parseFunc parses the parameter list and body of a single arity of fn and
+// returns the Func definition.
+func parseFunc(a core.Analyzer, fn builtin.Fn, params core.Any, body []core.Any) (*builtin.Func, error) {
+	fnArgs, err := paramSeq(params)
+	if err != nil {
+		return nil, err
 	}
-	i++
 
 	f := builtin.Func{}
-	fnEnv := env.Child(fn.Name, nil)
+	fnEnv := fn.Env.Child(fn.Name, nil)
 	argSet := map[string]struct{}{}
+	restMarker := false
+
+	// destructuring parameters are bound to generated names and then
+	// destructured by a let wrapping the body.
+	var let builtin.LetExpr
+	var patterns []core.Any
+	var patternParams []builtin.Symbol
+
 	err = core.ForEach(fnArgs, func(item core.Any) (bool, error) {
-		sym, ok := item.(builtin.Symbol)
+		if f.Variadic {
+			return true, core.Error{
+				Cause:   fmt.Errorf("%w: fn", ErrParseSpecial),
+				Message: fmt.Sprintf("unexpected parameter '%s' after rest parameter", item),
+			}
+		}
+
+		sym, ok := builtin.SymbolOf(item)
 		if !ok {
-			return true, fmt.Errorf(
-				"expecting parameter to be a symbol, got '%s'",
-				reflect.TypeOf(item))
+			switch item.(type) {
+			case core.Vector, core.Map:
+				sym = builtin.Symbol(gensym("p"))
+				patterns = append(patterns, item)
+				patternParams = append(patternParams, sym)
+
+			default:
+				return true, core.Error{
+					Cause: fmt.Errorf("%w: fn", ErrParseSpecial),
+					Message: fmt.Sprintf(
+						"expecting parameter to be a symbol, vector or map, got '%s'",
+						reflect.TypeOf(item)),
+				}
+			}
 		}
+
+		if sym == restSymbol {
+			if restMarker {
+				return true, core.Error{
+					Cause:   fmt.Errorf("%w: fn", ErrParseSpecial),
+					Message: "multiple '&' in parameter list",
+				}
+			}
+			restMarker = true
+			return false, nil
+		}
+
 		if _, found := argSet[string(sym)]; found {
 			return true, fmt.Errorf("duplicate arg name '%s'", sym)
 		}
 		argSet[string(sym)] = struct{}{}
 		f.Params = append(f.Params, string(sym))
+		f.Variadic = restMarker
 
 		if err := fnEnv.Bind(string(sym), nil); err != nil {
 			return false, err
@@ -331,22 +970,334 @@ func parseFnDef(a core.Analyzer, env core.Env, argSeq core.Seq) (*builtin.Fn, er
 	})
 	if err != nil {
 		return nil, err
+	} else if restMarker && !f.Variadic {
+		return nil, core.Error{
+			Cause:   fmt.Errorf("%w: fn", ErrParseSpecial),
+			Message: "expecting rest parameter name after '&'",
+		}
+	}
+
+	if err := fnEnv.Bind(recurTargetKey, recurTarget{arity: len(f.Params)}); err != nil {
+		return nil, err
+	}
+
+	for i, pattern := range patterns {
+		param := builtin.ResolveExpr{Symbol: patternParams[i]}
+		if err := destructure(a, fnEnv, pattern, param, &let); err != nil {
+			return nil, err
+		}
 	}
 
 	// wrap body in (do <expr>*) and analyze.
-	bodyExprs, err := builtin.Cons(builtin.Symbol("do"), builtin.NewList(args[i:]...))
+	bodyExprs, err := builtin.Cons(builtin.Symbol("do"), builtin.NewList(body...))
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := a.Analyze(fnEnv, bodyExprs)
-	if err != nil {
+	if f.Body, err = a.Analyze(fnEnv, bodyExprs); err != nil {
+		return nil, err
+	} else if err := checkRecur(f.Body, true); err != nil {
 		return nil, err
 	}
-	f.Body = body
+	f.Body = markTail(f.Body)
 
-	fn.Env = fnEnv
-	fn.Funcs = append(fn.Funcs, f)
+	if len(let.Names) > 0 {
+		let.Exprs = builtin.DoExpr{f.Body}
+		f.Body = let
+	}
 
-	return &fn, nil
+	return &f, nil
+}
+
+// checkArity returns error if the arity of f conflicts with any of the
+// arities already defined. At most one variadic arity is allowed and it
+// must not accept fewer arguments than any of the fixed arities.
+func checkArity(funcs []builtin.Func, f builtin.Func) error {
+	e := core.Error{Cause: fmt.Errorf("%w: fn", ErrParseSpecial)}
+
+	for _, other := range funcs {
+		switch {
+		case f.Variadic && other.Variadic:
+			return e.With("multiple variadic arities")
+
+		case f.Variadic && len(other.Params) > len(f.Params)-1,
+			other.Variadic && len(f.Params) > len(other.Params)-1:
+			return e.With("fixed arity with more parameters than variadic arity")
+
+		case !f.Variadic && !other.Variadic && len(other.Params) == len(f.Params):
+			return e.With(fmt.Sprintf(
+				"multiple arities with %d parameter(s)", len(f.Params)))
+		}
+	}
+	return nil
+}
+
+// isMultiArity returns true if the forms are arity definitions (i.e., lists
+// whose first item is a vector parameter list). A list whose first item is
+// a vector or a list cannot be a single arity parameter list, so returns
+// error if any of the forms is not an arity definition in that case.
+func isMultiArity(forms []core.Any) (bool, error) {
+	first, err := arityParams(forms[0])
+	if err != nil {
+		return false, err
+	}
+
+	switch first.(type) {
+	case core.Vector:
+
+	case core.Seq:
+		return false, core.Error{
+			Cause:   fmt.Errorf("%w: fn", ErrParseSpecial),
+			Message: "arity definition must begin with a vector parameter list",
+		}
+
+	default:
+		return false, nil
+	}
+
+	for _, form := range forms[1:] {
+		params, err := arityParams(form)
+		if err != nil {
+			return false, err
+		} else if _, ok := params.(core.Vector); !ok {
+			return false, core.Error{
+				Cause: fmt.Errorf("%w: fn", ErrParseSpecial),
+				Message: fmt.Sprintf(
+					"ambiguous fn definition: every arity must be a list beginning with a vector parameter list, got '%s'",
+					reflect.TypeOf(form)),
+			}
+		}
+	}
+	return true, nil
+}
+
+// arityParams returns the first item of the form if it is a list. Returns
+// nil otherwise.
+func arityParams(form core.Any) (core.Any, error) {
+	seq, ok := form.(core.Seq)
+	if !ok {
+		return nil, nil
+	}
+	return seq.First()
+}
+
+// paramSeq returns the parameter list (list or vector) form as a sequence.
+func paramSeq(params core.Any) (core.Seq, error) {
+	switch p := params.(type) {
+	case core.Seq:
+		return p, nil
+
+	case core.Vector:
+		if seqable, ok := p.(core.Seqable); ok {
+			return seqable.Seq()
+		}
+	}
+
+	return nil, fmt.Errorf(
+		"expecting a list of symbols, got '%s'", reflect.TypeOf(params))
+}
+
+// recurTarget holds information about the loop or fn that a recur within
+// it will re-execute.
+type recurTarget struct{ arity int }
+
+func resolveRecurTarget(env core.Env) (recurTarget, bool) {
+	for ; env != nil; env = env.Parent() {
+		v, err := env.Resolve(recurTargetKey)
+		if err == nil {
+			target, ok := v.(recurTarget)
+			return target, ok
+		}
+	}
+	return recurTarget{}, false
+}
+
+// checkRecur returns error if a RecurExpr appears in the expr at a position
+// other than the tail. Bodies of nested loop and fn forms are not checked
+// since a recur in them targets the nested form.
+func checkRecur(expr core.Expr, tail bool) error {
+	checkAll := func(exprs ...core.Expr) error {
+		for _, e := range exprs {
+			if err := checkRecur(e, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	notTail := core.Error{
+		Cause:   fmt.Errorf("%w: recur", ErrParseSpecial),
+		Message: "recur is allowed only in tail position",
+	}
+
+	switch e := expr.(type) {
+	case builtin.RecurExpr:
+		if !tail {
+			return notTail
+		}
+		return checkAll(e.Args...)
+
+	case builtin.VectorExpr:
+		// items of collection literals are analyzed only when evaluated,
+		// so the forms are checked instead. items are never in tail
+		// position.
+		if hasRecur(e.Vector) {
+			return notTail
+		}
+
+	case builtin.MapExpr:
+		if hasRecur(e.Map) {
+			return notTail
+		}
+
+	case builtin.SetExpr:
+		if hasRecur(e.Set) {
+			return notTail
+		}
+
+	case builtin.DoExpr:
+		if len(e) == 0 {
+			return nil
+		}
+		if err := checkAll(e[:len(e)-1]...); err != nil {
+			return err
+		}
+		return checkRecur(e[len(e)-1], tail)
+
+	case builtin.IfExpr:
+		if err := checkAll(e.Test); err != nil {
+			return err
+		}
+		if err := checkRecur(e.Then, tail); err != nil {
+			return err
+		}
+		return checkRecur(e.Else, tail)
+
+	case builtin.LetExpr:
+		if err := checkAll(e.Values...); err != nil {
+			return err
+		}
+		return checkRecur(e.Exprs, tail)
+
+	case builtin.LoopExpr:
+		return checkAll(e.Values...)
+
+	case builtin.InvokeExpr:
+		if err := checkAll(e.Target); err != nil {
+			return err
+		}
+		return checkAll(e.Args...)
+
+	case builtin.DefExpr:
+		return checkAll(e.Value)
+
+	case builtin.GoExpr:
+		return checkAll(e.Form)
+
+	case builtin.LazySeqExpr:
+		// the body is evaluated later, outside the enclosing fn or loop.
+		return checkAll(e.Body)
+
+	case builtin.BindingExpr:
+		// recur would escape the dynamic extent of the bindings.
+		return checkAll(append(append([]core.Expr(nil), e.Values...), e.Body)...)
+
+	case builtin.ThrowExpr:
+		return checkAll(e.Value)
+
+	case builtin.TryExpr:
+		if err := checkAll(e.Body, e.Finally); err != nil {
+			return err
+		}
+		for _, c := range e.Catches {
+			if err := checkAll(c.Matcher, c.Body); err != nil {
+				return err
+			}
+		}
+
+	case nthExpr:
+		return checkAll(e.Coll)
+
+	case getExpr:
+		return checkAll(e.Map, e.Default)
+
+	case templateExpr:
+		for _, item := range e.Items {
+			if err := checkAll(item.Expr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// hasRecur returns true if the form contains a recur form that targets the
+// enclosing loop or fn. Quoted forms and the bodies of nested fn, macro and
+// loop forms are skipped.
+func hasRecur(form core.Any) bool {
+	var items core.Seq
+	switch f := form.(type) {
+	case core.Seq:
+		if first, err := f.First(); err == nil {
+			switch first {
+			case builtin.Symbol("recur"):
+				return true
+			case builtin.Symbol("quote"), builtin.Symbol("syntax-quote"),
+				builtin.Symbol("fn"), builtin.Symbol("macro"), builtin.Symbol("loop"):
+				return false
+			}
+		}
+		items = f
+
+	case core.Seqable:
+		// map entries are vectors of key and value, and are walked same
+		// as the vectors.
+		items, _ = f.Seq()
+
+	default:
+		return false
+	}
+
+	found := false
+	_ = core.ForEach(items, func(item core.Any) (bool, error) {
+		found = hasRecur(item)
+		return found, nil
+	})
+	return found
+}
+
+// markTail returns the expr with the invocations in tail position marked
+// as tail calls (i.e., Tail set in InvokeExpr). Tail positions are the
+// last expr of do, then and else branches of if, and bodies of let and
+// loop forms that are themselves in tail position.
+func markTail(expr core.Expr) core.Expr {
+	switch e := expr.(type) {
+	case builtin.InvokeExpr:
+		e.Tail = true
+		return e
+
+	case builtin.DoExpr:
+		if len(e) == 0 {
+			return e
+		}
+		do := append(builtin.DoExpr(nil), e...)
+		do[len(do)-1] = markTail(do[len(do)-1])
+		return do
+
+	case builtin.IfExpr:
+		e.Then = markTail(e.Then)
+		e.Else = markTail(e.Else)
+		return e
+
+	case builtin.LetExpr:
+		e.Exprs = markTail(e.Exprs).(builtin.DoExpr)
+		return e
+
+	case builtin.LoopExpr:
+		e.Body = markTail(e.Body)
+		return e
+	}
+
+	return expr
 }
@@ -31,11 +31,8 @@ func Test_parseFn(t *testing.T) {
 			env:   core.New(nil),
 			args:  builtin.NewList(builtin.NewList()),
 			assert: func(t *testing.T, got core.Expr, err error) {
-				require.IsType(t, builtin.ConstExpr{}, got)
-				ce := got.(builtin.ConstExpr)
-
-				require.IsType(t, builtin.Fn{}, ce.Const)
-				fn := ce.Const.(builtin.Fn)
+				require.IsType(t, builtin.FnExpr{}, got)
+				fn := got.(builtin.FnExpr).Fn
 
 				require.Empty(t, fn.Name, "unexpected name: %s", fn.Name)
 				require.Empty(t, fn.Doc, "unexpected doc: %s", fn.Doc)
@@ -52,11 +49,8 @@ func Test_parseFn(t *testing.T) {
 				builtin.NewList(),
 			),
 			assert: func(t *testing.T, got core.Expr, err error) {
-				require.IsType(t, builtin.ConstExpr{}, got)
-				ce := got.(builtin.ConstExpr)
-
-				require.IsType(t, builtin.Fn{}, ce.Const)
-				fn := ce.Const.(builtin.Fn)
+				require.IsType(t, builtin.FnExpr{}, got)
+				fn := got.(builtin.FnExpr).Fn
 
 				require.Equal(t, "foo", fn.Name, "unexpected name: %s", fn.Name)
 				require.Equal(t, "hello", fn.Doc, "unexpected doc: %s", fn.Doc)
@@ -73,11 +67,8 @@ func Test_parseFn(t *testing.T) {
 				builtin.NewList(builtin.Symbol("do"), 1, 2),
 			),
 			assert: func(t *testing.T, got core.Expr, err error) {
-				require.IsType(t, builtin.ConstExpr{}, got)
-				ce := got.(builtin.ConstExpr)
-
-				require.IsType(t, builtin.Fn{}, ce.Const)
-				fn := ce.Const.(builtin.Fn)
+				require.IsType(t, builtin.FnExpr{}, got)
+				fn := got.(builtin.FnExpr).Fn
 
 				require.Equal(t, "foo", fn.Name, "unexpected name: %s", fn.Name)
 				require.Empty(t, fn.Doc, "unexpected doc: %s", fn.Doc)
@@ -85,6 +76,146 @@ func Test_parseFn(t *testing.T) {
 				require.Len(t, fn.Funcs, 1, "expected only one method")
 			},
 		},
+		{
+			title: "Arity1_Fn_VectorParams",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("a")),
+				builtin.Symbol("a"),
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				require.IsType(t, builtin.FnExpr{}, got)
+				fn := got.(builtin.FnExpr).Fn
+
+				require.Len(t, fn.Funcs, 1, "expected only one method")
+				assert.Equal(t, []string{"a"}, fn.Funcs[0].Params)
+			},
+		},
+		{
+			title: "MultiArity_Fn",
+			args: builtin.NewList(
+				builtin.Symbol("foo"),
+				builtin.String("hello"),
+				builtin.NewList(builtin.NewVector()),
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a")), builtin.Symbol("a")),
+				builtin.NewList(
+					builtin.NewVector(builtin.Symbol("a"), builtin.Symbol("b")),
+					builtin.Symbol("b"),
+				),
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				require.IsType(t, builtin.FnExpr{}, got)
+				fn := got.(builtin.FnExpr).Fn
+
+				require.Equal(t, "foo", fn.Name, "unexpected name: %s", fn.Name)
+				require.Equal(t, "hello", fn.Doc, "unexpected doc: %s", fn.Doc)
+				require.Len(t, fn.Funcs, 3, "expected 3 methods")
+				assert.Empty(t, fn.Funcs[0].Params)
+				assert.Equal(t, []string{"a"}, fn.Funcs[1].Params)
+				assert.Equal(t, []string{"a", "b"}, fn.Funcs[2].Params)
+			},
+		},
+		{
+			title: "MultiArity_DuplicateArity",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a")), builtin.Symbol("a")),
+				builtin.NewList(builtin.NewVector(builtin.Symbol("b")), builtin.Symbol("b")),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "MultiArity_EmptyArityDef",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a")), builtin.Symbol("a")),
+				builtin.NewList(),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "MultiArity_InvalidArityDef",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a")), builtin.Symbol("a")),
+				builtin.Int64(10),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "MultiArity_ListParams",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewList(builtin.Symbol("a")), builtin.Symbol("a")),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			// a list parameter list destructuring a vector or an arity
+			// definition with a body that is not an arity definition.
+			title: "MultiArity_Ambiguous",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a"))),
+				builtin.Symbol("a"),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "Arity1_Fn_ListParamsWithMap",
+			args: builtin.NewList(
+				builtin.NewList(mustMap(t, builtin.Keyword("keys"), builtin.NewVector(builtin.Symbol("a")))),
+				builtin.Symbol("a"),
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				require.IsType(t, builtin.FnExpr{}, got)
+				require.Len(t, got.(builtin.FnExpr).Fn.Funcs, 1, "expected only one method")
+			},
+		},
+		{
+			title: "Variadic_Fn",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("a"), builtin.Symbol("&"), builtin.Symbol("rest")),
+				builtin.Symbol("rest"),
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				require.IsType(t, builtin.FnExpr{}, got)
+				fn := got.(builtin.FnExpr).Fn
+
+				require.Len(t, fn.Funcs, 1, "expected only one method")
+				assert.True(t, fn.Funcs[0].Variadic)
+				assert.Equal(t, []string{"a", "rest"}, fn.Funcs[0].Params)
+			},
+		},
+		{
+			title: "Variadic_MissingRestParam",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("a"), builtin.Symbol("&")),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "Variadic_MultipleRestParams",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("&"), builtin.Symbol("a"), builtin.Symbol("b")),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "MultiArity_MultipleVariadic",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewVector(builtin.Symbol("&"), builtin.Symbol("a"))),
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a"), builtin.Symbol("&"), builtin.Symbol("b"))),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "MultiArity_AmbiguousVariadic",
+			args: builtin.NewList(
+				builtin.NewList(builtin.NewVector(builtin.Symbol("&"), builtin.Symbol("a"))),
+				builtin.NewList(builtin.NewVector(builtin.Symbol("a"), builtin.Symbol("b"))),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "NameWithoutParams",
+			args:    builtin.NewList(builtin.Symbol("foo")),
+			wantErr: ErrParseSpecial,
+		},
 	}
 
 	for _, tt := range table {
@@ -159,6 +290,21 @@ func Test_parseDef(t *testing.T) {
 				assert.Equal(t, want, got)
 			},
 		},
+		{
+			title: "Dynamic",
+			args: builtin.NewList(
+				builtin.MetaSymbol{Symbol: "*foo*"}.WithMeta(mustMap(t, builtin.Keyword("dynamic"), builtin.Bool(true))),
+				100,
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				want := builtin.DefExpr{
+					Name:    "*foo*",
+					Value:   builtin.ConstExpr{Const: 100},
+					Dynamic: true,
+				}
+				assert.Equal(t, want, got)
+			},
+		},
 	}
 
 	for _, tt := range table {
@@ -169,6 +315,55 @@ func Test_parseDef(t *testing.T) {
 	}
 }
 
+func Test_parseBinding(t *testing.T) {
+	t.Parallel()
+
+	table := []specialTest{
+		{
+			title:   "NilArgs",
+			args:    nil,
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "NotVector",
+			args:    builtin.NewList(builtin.Int64(1)),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "OddBindings",
+			args:    builtin.NewList(builtin.NewVector(builtin.Symbol("*x*"))),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "NonSymbolName",
+			args:    builtin.NewList(builtin.NewVector(builtin.Keyword("x"), 1)),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "Valid",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("*x*"), 1),
+				builtin.Symbol("*x*"),
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				want := builtin.BindingExpr{
+					Names:  []builtin.Symbol{"*x*"},
+					Values: []core.Expr{builtin.ConstExpr{Const: 1}},
+					Body:   builtin.DoExpr{builtin.ResolveExpr{Symbol: "*x*"}},
+				}
+				assert.Equal(t, want, got)
+			},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			tt.env = core.New(nil)
+			runSpecialTest(t, tt, parseBinding)
+		})
+	}
+}
+
 func Test_parseLet(t *testing.T) {
 	t.Parallel()
 
@@ -224,6 +419,147 @@ func Test_parseLet(t *testing.T) {
 	}
 }
 
+func Test_parseLoop(t *testing.T) {
+	t.Parallel()
+
+	recurForm := builtin.NewList(builtin.Symbol("recur"), builtin.Symbol("x"))
+	recurSet, err := builtin.NewSet(recurForm)
+	require.NoError(t, err)
+
+	assertNotTail := func(t *testing.T, _ core.Expr, err error) {
+		assert.Contains(t, err.Error(), "recur is allowed only in tail position")
+	}
+
+	table := []specialTest{
+		{
+			title:   "NilArgs",
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "NotBindingVector",
+			args:    builtin.NewList(builtin.Int64(1)),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "OddBindings",
+			args:    builtin.NewList(builtin.NewVector(builtin.Symbol("x"))),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "Valid",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("x"), builtin.Int64(42)),
+				builtin.NewList(builtin.Symbol("recur"), builtin.Symbol("x")),
+			),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				want := builtin.LoopExpr{
+					Names:  []string{"x"},
+					Values: []core.Expr{builtin.ConstExpr{Const: builtin.Int64(42)}},
+					Body: builtin.DoExpr{
+						builtin.RecurExpr{Args: []core.Expr{builtin.ResolveExpr{Symbol: "x"}}},
+					},
+				}
+
+				assert.Equal(t, want, got)
+			},
+		},
+		{
+			title: "RecurNotInTail",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("x"), builtin.Int64(42)),
+				builtin.NewList(builtin.Symbol("recur"), builtin.Symbol("x")),
+				builtin.Symbol("x"),
+			),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "RecurInVector",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("x"), builtin.Int64(42)),
+				builtin.NewVector(recurForm),
+			),
+			wantErr: ErrParseSpecial,
+			assert:  assertNotTail,
+		},
+		{
+			title: "RecurInMap",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("x"), builtin.Int64(42)),
+				mustMap(t, builtin.Keyword("a"), recurForm),
+			),
+			wantErr: ErrParseSpecial,
+			assert:  assertNotTail,
+		},
+		{
+			title: "RecurInSet",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("x"), builtin.Int64(42)),
+				recurSet,
+			),
+			wantErr: ErrParseSpecial,
+			assert:  assertNotTail,
+		},
+		{
+			title: "RecurInVector_NestedFn",
+			args: builtin.NewList(
+				builtin.NewVector(builtin.Symbol("x"), builtin.Int64(42)),
+				builtin.NewVector(builtin.NewList(
+					builtin.Symbol("fn"), builtin.NewVector(builtin.Symbol("y")), recurForm,
+				)),
+			),
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			tt.env = core.New(nil)
+			runSpecialTest(t, tt, parseLoop)
+		})
+	}
+}
+
+func Test_parseRecur(t *testing.T) {
+	t.Parallel()
+
+	table := []specialTest{
+		{
+			title:   "NoTarget",
+			args:    builtin.NewList(builtin.Int64(1)),
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title: "Valid",
+			env: core.New(map[string]core.Any{
+				recurTargetKey: recurTarget{arity: 1},
+			}),
+			args: builtin.NewList(builtin.Int64(1)),
+			assert: func(t *testing.T, got core.Expr, err error) {
+				want := builtin.RecurExpr{
+					Args: []core.Expr{builtin.ConstExpr{Const: builtin.Int64(1)}},
+				}
+				assert.Equal(t, want, got)
+			},
+		},
+		{
+			title: "ArityMismatch",
+			env: core.New(map[string]core.Any{
+				recurTargetKey: recurTarget{arity: 2},
+			}),
+			args:    builtin.NewList(builtin.Int64(1)),
+			wantErr: ErrParseSpecial,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			if tt.env == nil {
+				tt.env = core.New(nil)
+			}
+			runSpecialTest(t, tt, parseRecur)
+		})
+	}
+}
+
 type specialTest struct {
 	title   string
 	env     core.Env
@@ -241,6 +577,8 @@ func runSpecialTest(t *testing.T, tt specialTest, parse builtin.ParseSpecial) {
 			"fn":    parseFn,
 			"def":   parseDef,
 			"let":   parseLet,
+			"loop":  parseLoop,
+			"recur": parseRecur,
 			"macro": parseMacro,
 			"quote": parseQuote,
 		},
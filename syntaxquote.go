@@ -0,0 +1,384 @@
+package slurp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+)
+
+const (
+	symSyntaxQuote     = builtin.Symbol("syntax-quote")
+	symUnquote         = builtin.Symbol("unquote")
+	symUnquoteSplicing = builtin.Symbol("unquote-splicing")
+)
+
+// parseSyntaxQuote parses the (syntax-quote <form>) special form. The form
+// is expanded into an expression that constructs the form with unquoted
+// parts (~x and ~@xs) replaced by their evaluated values. Symbols ending
+// with '#' are replaced by generated symbols that are unique for the form.
+// Other symbols are qualified with their namespace. Nested syntax-quotes
+// are supported and unquotes apply to the innermost level as in Scheme's
+// quasiquote.
+func parseSyntaxQuote(a core.Analyzer, env core.Env, args core.Seq) (core.Expr, error) {
+	if args == nil {
+		return nil, core.Error{
+			Cause:   fmt.Errorf("%w: syntax-quote", ErrParseSpecial),
+			Message: "requires exactly 1 argument, got 0",
+		}
+	}
+
+	if count, err := args.Count(); err != nil {
+		return nil, err
+	} else if count != 1 {
+		return nil, core.Error{
+			Cause:   fmt.Errorf("%w: syntax-quote", ErrParseSpecial),
+			Message: fmt.Sprintf("requires exactly 1 argument, got %d", count),
+		}
+	}
+
+	form, err := args.First()
+	if err != nil {
+		return nil, err
+	}
+
+	sq := syntaxQuoter{
+		analyzer: a,
+		env:      env,
+		gensyms:  map[builtin.Symbol]builtin.Symbol{},
+	}
+	return sq.expand(form, 1)
+}
+
+// parseUnquote returns error for unquote and unquote-splicing forms that
+// appear outside of a syntax-quote.
+func parseUnquote(_ core.Analyzer, _ core.Env, _ core.Seq) (core.Expr, error) {
+	return nil, core.Error{
+		Cause:   fmt.Errorf("%w: unquote", ErrParseSpecial),
+		Message: "unquote is not allowed outside syntax-quote",
+	}
+}
+
+type syntaxQuoter struct {
+	analyzer core.Analyzer
+	env      core.Env
+	gensyms  map[builtin.Symbol]builtin.Symbol
+}
+
+func (sq syntaxQuoter) expand(form core.Any, depth int) (core.Expr, error) {
+	switch f := form.(type) {
+	case builtin.Symbol:
+		return builtin.QuoteExpr{Form: sq.symbol(f, depth)}, nil
+
+	case builtin.MetaSymbol:
+		// the metadata is kept as is.
+		sym := builtin.MetaSymbol{Symbol: sq.symbol(f.Symbol, depth)}
+		return builtin.QuoteExpr{Form: sym.WithMeta(f.Meta())}, nil
+
+	case core.Seq:
+		return sq.expandSeq(f, depth)
+
+	case core.Vector:
+		return sq.expandColl(f, templateVector, depth)
+
+	case core.Map:
+		return sq.expandColl(f, templateMap, depth)
+
+	case core.Set:
+		return sq.expandColl(f, templateSet, depth)
+
+	default:
+		return builtin.QuoteExpr{Form: form}, nil
+	}
+}
+
+// symbol returns the symbol to be used in place of sym in the expansion.
+// Auto-gensyms (e.g., x#) are replaced by the generated symbols and other
+// symbols are qualified. Symbols in nested syntax-quotes are unmodified.
+func (sq syntaxQuoter) symbol(sym builtin.Symbol, depth int) builtin.Symbol {
+	if depth != 1 {
+		return sym
+	}
+
+	if strings.HasSuffix(string(sym), "#") && len(sym) > 1 {
+		gen, found := sq.gensyms[sym]
+		if !found {
+			gen = builtin.Symbol(gensym(strings.TrimSuffix(string(sym), "#")) + "__auto__")
+			sq.gensyms[sym] = gen
+		}
+		return gen
+	}
+	return sq.qualify(sym)
+}
+
+// qualify returns the symbol qualified with the namespace it resolves to
+// in the current namespace, or the current namespace itself if it cannot
+// be resolved. Special form names, already qualified symbols and symbols
+// bound in the root env are returned unmodified.
+func (sq syntaxQuoter) qualify(sym builtin.Symbol) builtin.Symbol {
+	ns := builtin.CurrentNS(sq.env)
+	if ns == nil || sym == restSymbol || strings.Contains(string(sym), "/") {
+		return sym
+	}
+
+	var specials map[string]builtin.ParseSpecial
+	switch ba := sq.analyzer.(type) {
+	case builtin.Analyzer:
+		specials = ba.Specials
+	case *builtin.Analyzer:
+		specials = ba.Specials
+	}
+
+	if _, isSpecial := specials[string(sym)]; isSpecial {
+		return sym
+	}
+
+	owner, found := ns.Owner(string(sym))
+	if !found {
+		if _, err := core.Root(sq.env).Resolve(string(sym)); err == nil {
+			return sym
+		}
+		owner = ns.Name()
+	}
+
+	return builtin.Symbol(owner + "/" + string(sym))
+}
+
+func (sq syntaxQuoter) expandSeq(seq core.Seq, depth int) (core.Expr, error) {
+	cnt, err := seq.Count()
+	if err != nil {
+		return nil, err
+	} else if cnt == 0 {
+		return builtin.QuoteExpr{Form: seq}, nil
+	}
+
+	first, err := seq.First()
+	if err != nil {
+		return nil, err
+	}
+
+	switch first {
+	case symUnquote, symUnquoteSplicing:
+		arg, err := quoteArg(seq, first.(builtin.Symbol))
+		if err != nil {
+			return nil, err
+		}
+
+		if depth == 1 {
+			if first == symUnquoteSplicing {
+				return nil, core.Error{
+					Cause:   fmt.Errorf("%w: syntax-quote", ErrParseSpecial),
+					Message: "unquote-splicing is allowed only within a collection",
+				}
+			}
+			return sq.analyzer.Analyze(sq.env, arg)
+		}
+		return sq.wrap(first, arg, depth-1)
+
+	case symSyntaxQuote:
+		arg, err := quoteArg(seq, symSyntaxQuote)
+		if err != nil {
+			return nil, err
+		}
+		return sq.wrap(first, arg, depth+1)
+	}
+
+	return sq.expandColl(seq, templateList, depth)
+}
+
+// wrap expands the form at the given depth and wraps it in (<sym> <form>).
+func (sq syntaxQuoter) wrap(sym core.Any, form core.Any, depth int) (core.Expr, error) {
+	expr, err := sq.expand(form, depth)
+	if err != nil {
+		return nil, err
+	}
+
+	return sq.simplify(templateExpr{
+		Kind: templateList,
+		Items: []templateItem{
+			{Expr: builtin.QuoteExpr{Form: sym}},
+			{Expr: expr},
+		},
+	})
+}
+
+func (sq syntaxQuoter) expandColl(coll core.Any, kind templateKind, depth int) (core.Expr, error) {
+	seq, err := collSeq(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	te := templateExpr{Kind: kind}
+	err = core.ForEach(seq, func(item core.Any) (bool, error) {
+		if kind == templateMap {
+			// map entries are [key value] vectors. expand both.
+			entry := item.(core.Vector)
+			for i := 0; i < 2; i++ {
+				v, err := entry.EntryAt(i)
+				if err != nil {
+					return true, err
+				}
+
+				expr, err := sq.expand(v, depth)
+				if err != nil {
+					return true, err
+				}
+				te.Items = append(te.Items, templateItem{Expr: expr})
+			}
+			return false, nil
+		}
+
+		if depth == 1 && isForm(item, symUnquoteSplicing) {
+			arg, err := quoteArg(item.(core.Seq), symUnquoteSplicing)
+			if err != nil {
+				return true, err
+			}
+
+			expr, err := sq.analyzer.Analyze(sq.env, arg)
+			if err != nil {
+				return true, err
+			}
+			te.Items = append(te.Items, templateItem{Expr: expr, Splice: true})
+			return false, nil
+		}
+
+		expr, err := sq.expand(item, depth)
+		if err != nil {
+			return true, err
+		}
+		te.Items = append(te.Items, templateItem{Expr: expr})
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sq.simplify(te)
+}
+
+// simplify returns the template as a constant if none of its items need
+// evaluation.
+func (sq syntaxQuoter) simplify(te templateExpr) (core.Expr, error) {
+	for _, item := range te.Items {
+		if _, ok := item.Expr.(builtin.QuoteExpr); !ok || item.Splice {
+			return te, nil
+		}
+	}
+
+	form, err := te.Eval(sq.env)
+	if err != nil {
+		return nil, err
+	}
+	return builtin.QuoteExpr{Form: form}, nil
+}
+
+func isForm(form core.Any, sym builtin.Symbol) bool {
+	seq, ok := form.(core.Seq)
+	if !ok {
+		return false
+	}
+
+	if cnt, err := seq.Count(); err != nil || cnt == 0 {
+		return false
+	}
+
+	first, err := seq.First()
+	return err == nil && first == sym
+}
+
+// quoteArg returns the single argument of (<sym> <arg>) form.
+func quoteArg(seq core.Seq, sym builtin.Symbol) (core.Any, error) {
+	if cnt, err := seq.Count(); err != nil {
+		return nil, err
+	} else if cnt != 2 {
+		return nil, core.Error{
+			Cause:   fmt.Errorf("%w: %s", ErrParseSpecial, sym),
+			Message: fmt.Sprintf("requires exactly 1 argument, got %d", cnt-1),
+		}
+	}
+
+	next, err := seq.Next()
+	if err != nil {
+		return nil, err
+	}
+	return next.First()
+}
+
+func collSeq(coll core.Any) (core.Seq, error) {
+	switch c := coll.(type) {
+	case core.Seq:
+		return c, nil
+
+	case core.Seqable:
+		return c.Seq()
+
+	default:
+		return nil, fmt.Errorf("cannot syntax-quote value of type '%s'", reflect.TypeOf(coll))
+	}
+}
+
+type templateKind int
+
+const (
+	templateList templateKind = iota
+	templateVector
+	templateMap
+	templateSet
+)
+
+// templateExpr constructs a collection of Kind from the values of Items
+// when evaluated. Values of splicing items are sequences whose items are
+// added individually.
+type templateExpr struct {
+	Kind  templateKind
+	Items []templateItem
+}
+
+type templateItem struct {
+	Expr   core.Expr
+	Splice bool
+}
+
+func (te templateExpr) Eval(env core.Env) (core.Any, error) {
+	var vals []core.Any
+	for _, item := range te.Items {
+		v, err := item.Expr.Eval(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if !item.Splice {
+			vals = append(vals, v)
+			continue
+		}
+
+		seq, err := toSeq(v)
+		if err != nil {
+			return nil, err
+		} else if seq == nil {
+			continue
+		}
+
+		items, err := core.ToSlice(seq)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, items...)
+	}
+
+	switch te.Kind {
+	case templateVector:
+		return builtin.NewVector(vals...), nil
+
+	case templateMap:
+		return builtin.NewMap(vals...)
+
+	case templateSet:
+		return builtin.NewSet(vals...)
+
+	default:
+		return builtin.NewList(vals...), nil
+	}
+}
@@ -0,0 +1,193 @@
+package slurp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spy16/slurp/builtin"
+	"github.com/spy16/slurp/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyntaxQuote(t *testing.T) {
+	t.Parallel()
+
+	sym := func(s string) builtin.Symbol { return builtin.Symbol(s) }
+	i := func(v int64) builtin.Int64 { return builtin.Int64(v) }
+
+	table := []struct {
+		title   string
+		src     string
+		want    core.Any
+		wantErr error
+		assert  func(t *testing.T, got core.Any)
+	}{
+		{
+			title: "Constant",
+			src:   "`(a b :c)",
+			want:  builtin.NewList(sym("user/a"), sym("user/b"), builtin.Keyword("c")),
+		},
+		{
+			title: "EmptyList",
+			src:   "`()",
+			want:  builtin.NewList(),
+		},
+		{
+			title: "Unquote",
+			src:   "(let [x 1] `(a ~x))",
+			want:  builtin.NewList(sym("user/a"), i(1)),
+		},
+		{
+			title: "UnquoteTopLevel",
+			src:   "(let [x 1] `~x)",
+			want:  i(1),
+		},
+		{
+			title: "UnquoteSplicing",
+			src:   "(let [xs [2 3]] `(a ~@xs 4))",
+			want:  builtin.NewList(sym("user/a"), i(2), i(3), i(4)),
+		},
+		{
+			title: "UnquoteSplicing_Nil",
+			src:   "(let [xs nil] `(a ~@xs))",
+			want:  builtin.NewList(sym("user/a")),
+		},
+		{
+			title: "Vector",
+			src:   "(let [x 1 xs '(2 3)] `[~x ~@xs b])",
+			want:  builtin.NewVector(i(1), i(2), i(3), sym("user/b")),
+		},
+		{
+			title: "Map",
+			src:   "(let [x 1] `{:a ~x})",
+			want:  mustMap(t, builtin.Keyword("a"), i(1)),
+		},
+		{
+			title: "Set",
+			src:   "(let [x 1] `#{~x})",
+			want: func() core.Any {
+				s, err := builtin.NewSet(i(1))
+				require.NoError(t, err)
+				return s
+			}(),
+		},
+		{
+			title: "Nested",
+			src:   "(let [x 1] `(a `(b ~(c ~x))))",
+			want: builtin.NewList(sym("user/a"),
+				builtin.NewList(sym("syntax-quote"),
+					builtin.NewList(sym("b"),
+						builtin.NewList(sym("unquote"),
+							builtin.NewList(sym("user/c"), i(1)))))),
+		},
+		{
+			title: "AutoGensym",
+			src:   "`(let [x# 1] [x# y])",
+			assert: func(t *testing.T, got core.Any) {
+				items, err := core.ToSlice(got.(core.Seq))
+				require.NoError(t, err)
+
+				bindings := items[1].(builtin.PersistentVector)
+				body := items[2].(builtin.PersistentVector)
+
+				name, _ := bindings.EntryAt(0)
+				ref, _ := body.EntryAt(0)
+				y, _ := body.EntryAt(1)
+
+				assert.Equal(t, name, ref, "same auto-gensym must resolve to same symbol")
+				assert.True(t, strings.HasPrefix(string(name.(builtin.Symbol)), "x__"))
+				assert.True(t, strings.HasSuffix(string(name.(builtin.Symbol)), "__auto__"))
+				assert.Equal(t, sym("user/y"), y)
+			},
+		},
+		{
+			title: "MetaSymbol",
+			src:   "`(foo ^:x bar ^:y x#)",
+			assert: func(t *testing.T, got core.Any) {
+				items, err := core.ToSlice(got.(core.Seq))
+				require.NoError(t, err)
+				require.Len(t, items, 3)
+				assert.Equal(t, sym("user/foo"), items[0])
+
+				for j, want := range []string{"x", "y"} {
+					ms, ok := items[j+1].(builtin.MetaSymbol)
+					require.True(t, ok, "metadata must be kept")
+
+					found, err := ms.Meta().HasKey(builtin.Keyword(want))
+					require.NoError(t, err)
+					assert.True(t, found)
+				}
+
+				assert.Equal(t, sym("user/bar"), items[1].(builtin.MetaSymbol).Symbol)
+				assert.True(t, strings.HasSuffix(string(items[2].(builtin.MetaSymbol).Symbol), "__auto__"))
+			},
+		},
+		{
+			title:   "UnquoteOutside",
+			src:     "~x",
+			wantErr: ErrParseSpecial,
+		},
+		{
+			title:   "UnquoteSplicingTopLevel",
+			src:     "(let [x [1]] `~@x)",
+			wantErr: ErrParseSpecial,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.title, func(t *testing.T) {
+			got, err := New().EvalStr(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.assert != nil {
+				tt.assert(t, got)
+			} else {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSyntaxQuote_Qualify(t *testing.T) {
+	t.Parallel()
+
+	ins := New()
+	require.NoError(t, ins.Bind(map[string]core.Any{"global": builtin.Int64(1)}))
+
+	_, err := ins.EvalStr(`(ns other) (def x 1) (ns user (:refer other))`)
+	require.NoError(t, err)
+
+	got, err := ins.EvalStr("`(if x y global other/z &)")
+	require.NoError(t, err)
+
+	want := builtin.NewList(
+		builtin.Symbol("if"),
+		builtin.Symbol("other/x"),
+		builtin.Symbol("user/y"),
+		builtin.Symbol("global"),
+		builtin.Symbol("other/z"),
+		builtin.Symbol("&"),
+	)
+	assert.Equal(t, want, got)
+}
+
+func TestSyntaxQuote_Macro(t *testing.T) {
+	t.Parallel()
+
+	ins := New()
+	_, err := ins.EvalStr("(def when (macro [test & body] `(if ~test (do ~@body))))")
+	require.NoError(t, err)
+
+	got, err := ins.EvalStr("(when true 1 2)")
+	require.NoError(t, err)
+	assert.Equal(t, builtin.Int64(2), got)
+
+	got, err = ins.EvalStr("(when false 1 2)")
+	require.NoError(t, err)
+	assert.Equal(t, builtin.Nil{}, got)
+}